@@ -33,10 +33,19 @@ func main() {
 	}
 
 	// Add some dependencies
-	c.AddDependency("php", "^8.0")
-	c.AddDependency("symfony/console", "^6.0")
-	c.AddDependency("monolog/monolog", "^2.3")
-	c.AddDevDependency("phpunit/phpunit", "^9.5")
+	for _, dep := range []struct{ name, version string }{
+		{"php", "^8.0"},
+		{"ext-json", "*"},
+		{"symfony/console", "^6.0"},
+		{"monolog/monolog", "^2.3"},
+	} {
+		if err := c.AddDependency(dep.name, dep.version); err != nil {
+			log.Fatalf("Error adding dependency %s: %v", dep.name, err)
+		}
+	}
+	if err := c.AddDevDependency("phpunit/phpunit", "^9.5"); err != nil {
+		log.Fatalf("Error adding dev dependency: %v", err)
+	}
 
 	// Set up PSR-4 autoloading
 	psr4Map := make(map[string]interface{})
@@ -11,6 +11,7 @@
 package composer
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -20,7 +21,10 @@ import (
 	"github.com/scagogogo/php-composer-json-parser/pkg/composer/archive"
 	"github.com/scagogogo/php-composer-json-parser/pkg/composer/autoload"
 	"github.com/scagogogo/php-composer-json-parser/pkg/composer/config"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/constraint"
 	"github.com/scagogogo/php-composer-json-parser/pkg/composer/dependency"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/document"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/manipulator"
 	"github.com/scagogogo/php-composer-json-parser/pkg/composer/parser"
 	"github.com/scagogogo/php-composer-json-parser/pkg/composer/repository"
 	"github.com/scagogogo/php-composer-json-parser/pkg/composer/serializer"
@@ -44,6 +48,17 @@ var (
 	ErrUnmarshallingJSON = parser.ErrUnmarshallingJSON
 )
 
+// ParseError 描述JSON语法错误的行号、列号、字节偏移、JSON指针和可能的原因
+//
+// 示例:
+//
+//	_, err := composer.ParseFile("./composer.json")
+//	var parseErr *composer.ParseError
+//	if errors.As(err, &parseErr) {
+//		fmt.Printf("composer.json:%d:%d: %s\n", parseErr.Line, parseErr.Column, parseErr.Hint)
+//	}
+type ParseError = parser.ParseError
+
 // ParseFile 从文件路径解析composer.json文件
 //
 // 参数:
@@ -63,12 +78,12 @@ var (
 //	fmt.Println("版本:", composer.Version)
 //	fmt.Println("PHP依赖版本:", composer.Require["php"])
 func ParseFile(filePath string) (*ComposerJSON, error) {
-	rawData, err := parser.ParseFile(filePath)
+	data, err := parser.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
 
-	return convertToComposerJSON(rawData)
+	return parseBytes(data)
 }
 
 // ParseDir 在指定目录中查找并解析composer.json文件
@@ -128,12 +143,12 @@ func ParseDir(dir string) (*ComposerJSON, error) {
 //
 //	composer, err = composer.Parse(resp.Body)
 func Parse(r io.Reader) (*ComposerJSON, error) {
-	rawData, err := parser.Parse(r)
+	data, err := io.ReadAll(r)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrReadingFile, err)
 	}
 
-	return convertToComposerJSON(rawData)
+	return parseBytes(data)
 }
 
 // ParseString 解析composer.json字符串
@@ -161,12 +176,106 @@ func Parse(r io.Reader) (*ComposerJSON, error) {
 //	}
 //	fmt.Println("包名:", composer.Name)
 func ParseString(jsonStr string) (*ComposerJSON, error) {
-	rawData, err := parser.ParseString(jsonStr)
-	if err != nil {
+	return parseBytes([]byte(jsonStr))
+}
+
+// parseBytes 解析composer.json的原始字节，并记录原始文档以便保存时保留格式
+func parseBytes(data []byte) (*ComposerJSON, error) {
+	return parseBytesWithOptions(data, ParseOptions{})
+}
+
+// parseBytesWithOptions 使用指定的选项解析composer.json的原始字节
+func parseBytesWithOptions(data []byte, opts ParseOptions) (*ComposerJSON, error) {
+	if _, err := parser.ParseBytes(data); err != nil {
 		return nil, err
 	}
 
-	return convertToComposerJSON(rawData)
+	// 先按字段类型检查文档，使错误可以指出具体的字段
+	if doc, err := document.Parse(data); err == nil {
+		if errs := checkFieldTypes(doc.Root, opts.CollectAllErrors); len(errs) > 0 {
+			if opts.CollectAllErrors {
+				return nil, errs
+			}
+			return nil, errs[0]
+		}
+	}
+
+	// 直接从原始字节解码，使未建模字段保留原始的JSON文本
+	var composer ComposerJSON
+	if err := json.Unmarshal(data, &composer); err != nil {
+		return nil, conversionError(err)
+	}
+
+	composer.attachSource(data)
+	return &composer, nil
+}
+
+// attachSource 记录原始文档及其经过结构体转换后的值
+//
+// 原始文档的根不是对象时不做记录，此时ToJSON和Save退回到完整的重新序列化。
+func (c *ComposerJSON) attachSource(data []byte) {
+	c.source, c.sourceModel = nil, nil
+
+	doc, err := document.Parse(data)
+	if err != nil || doc.Root.Kind != document.Object {
+		return
+	}
+
+	model, err := document.FromValue(c)
+	if err != nil {
+		return
+	}
+
+	c.source, c.sourceModel = doc, model
+}
+
+// editSource 使用manipulator对原始文档及其基准值执行同样的就地编辑
+//
+// 这样依赖的增删直接作用于原始文本（包括按config.sort-packages插入到有序位置），
+// 保存时这些部分按编辑后的文本原样输出，而不是由结构体重新序列化。
+// 任一编辑失败时保持原状，保存时退回到Merge的合并结果。
+func (c *ComposerJSON) editSource(edit func(m *manipulator.Manipulator) error) {
+	if c.source == nil {
+		return
+	}
+
+	source, err := manipulator.New(c.source.Source)
+	if err != nil || edit(source) != nil {
+		return
+	}
+	model, err := manipulator.New(c.sourceModel.Raw)
+	if err != nil || edit(model) != nil {
+		return
+	}
+
+	doc, err := document.Parse(source.Contents())
+	if err != nil {
+		return
+	}
+	modelDoc, err := document.Parse(model.Contents())
+	if err != nil {
+		return
+	}
+
+	doc.Format = c.source.Format
+	c.source, c.sourceModel = doc, modelDoc.Root
+}
+
+// Format 返回composer.json的排版风格
+//
+// 对于从文件或字符串解析得到的结构体，返回从原始文本中检测到的缩进、换行符和末尾换行；
+// 对于新创建的结构体，返回Composer默认的排版风格。
+//
+// 示例:
+//
+//	composer, _ := composer.ParseFile("./composer.json")
+//	format := composer.Format()
+//	fmt.Printf("缩进: %q, 换行: %q\n", format.Indent, format.Newline)
+func (c *ComposerJSON) Format() document.Format {
+	if c.source != nil {
+		return c.source.Format
+	}
+	return document.DefaultFormat()
 }
 
 // convertToComposerJSON 将原始map转换为ComposerJSON结构体
@@ -189,7 +298,7 @@ func convertToComposerJSON(data map[string]interface{}) (*ComposerJSON, error) {
 
 	var composer ComposerJSON
 	if err := json.Unmarshal(jsonData, &composer); err != nil {
-		return nil, fmt.Errorf("error converting to ComposerJSON: %v", err)
+		return nil, conversionError(err)
 	}
 
 	return &composer, nil
@@ -200,6 +309,9 @@ func convertToComposerJSON(data map[string]interface{}) (*ComposerJSON, error) {
 // 参数:
 //   - indent: 是否缩进格式化JSON（true为美化输出，false为紧凑输出）
 //
+// 对于解析得到的结构体，美化输出会保留原始文件的键顺序、缩进、换行符和末尾换行，
+// 未修改的部分按原样输出；新创建的结构体按字母顺序输出所有键。
+//
 // 返回:
 //   - string: 转换后的JSON字符串
 //   - error: 如果转换失败，返回错误
@@ -221,14 +333,26 @@ func convertToComposerJSON(data map[string]interface{}) (*ComposerJSON, error) {
 //		log.Fatal(err)
 //	}
 func (c *ComposerJSON) ToJSON(indent bool) (string, error) {
+	// 保留原始文档的格式
+	if indent && c.source != nil {
+		current, err := document.FromValue(c)
+		if err != nil {
+			return "", err
+		}
+		return string(c.source.Merge(c.sourceModel, current)), nil
+	}
+
 	// 将结构体转换为map
 	jsonData, err := json.Marshal(c)
 	if err != nil {
 		return "", fmt.Errorf("error marshalling to JSON: %v", err)
 	}
 
+	// 使用json.Number避免未建模字段中的大整数丢失精度
 	var rawData map[string]interface{}
-	if err := json.Unmarshal(jsonData, &rawData); err != nil {
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+	if err := decoder.Decode(&rawData); err != nil {
 		return "", fmt.Errorf("error unmarshalling to map: %v", err)
 	}
 
@@ -241,6 +365,8 @@ func (c *ComposerJSON) ToJSON(indent bool) (string, error) {
 //   - filePath: 保存的文件路径
 //   - indent: 是否缩进格式化JSON（true为美化输出，false为紧凑输出）
 //
+// 对于解析得到的结构体，只有修改过的部分会被重写，例如只添加一个依赖时文件只产生一行差异。
+//
 // 返回:
 //   - error: 如果保存失败，返回错误
 //
@@ -264,7 +390,15 @@ func (c *ComposerJSON) Save(filePath string, indent bool) error {
 		return err
 	}
 
-	return os.WriteFile(filePath, []byte(jsonData), 0644)
+	if err := os.WriteFile(filePath, []byte(jsonData), 0644); err != nil {
+		return err
+	}
+
+	// 以保存后的内容作为下一次保存的基准
+	if indent {
+		c.attachSource([]byte(jsonData))
+	}
+	return nil
 }
 
 // CreateBackup 在修改前创建composer.json的备份
@@ -341,7 +475,7 @@ func (c *ComposerJSON) DevDependencyExists(packageName string) bool {
 // AddDependency 向require部分添加包
 //
 // 参数:
-//   - packageName: 要添加的包名，格式为"vendor/package"
+//   - packageName: 要添加的包名，格式为"vendor/package"，或平台包名，如"php"、"ext-intl"
 //   - version: 依赖版本，如"^5.4"、">=7.4"
 //
 // 返回:
@@ -366,13 +500,20 @@ func (c *ComposerJSON) DevDependencyExists(packageName string) bool {
 //	// 保存修改
 //	composer.Save("./composer.json", true)
 func (c *ComposerJSON) AddDependency(packageName, version string) error {
-	return dependency.AddDependency(c.Require, packageName, version)
+	if err := dependency.AddDependency(c.Require, packageName, version); err != nil {
+		return err
+	}
+
+	c.editSource(func(m *manipulator.Manipulator) error {
+		return m.AddLink("require", packageName, version, c.Config.SortPackages)
+	})
+	return nil
 }
 
 // AddDevDependency 向require-dev部分添加包
 //
 // 参数:
-//   - packageName: 要添加的包名，格式为"vendor/package"
+//   - packageName: 要添加的包名，格式为"vendor/package"，或平台包名，如"ext-xdebug"
 //   - version: 依赖版本，如"^9.0"
 //
 // 返回:
@@ -397,7 +538,14 @@ func (c *ComposerJSON) AddDependency(packageName, version string) error {
 //	// 保存修改
 //	composer.Save("./composer.json", true)
 func (c *ComposerJSON) AddDevDependency(packageName, version string) error {
-	return dependency.AddDependency(c.RequireDev, packageName, version)
+	if err := dependency.AddDependency(c.RequireDev, packageName, version); err != nil {
+		return err
+	}
+
+	c.editSource(func(m *manipulator.Manipulator) error {
+		return m.AddLink("require-dev", packageName, version, c.Config.SortPackages)
+	})
+	return nil
 }
 
 // RemoveDependency 从require部分移除包
@@ -421,7 +569,15 @@ func (c *ComposerJSON) AddDevDependency(packageName, version string) error {
 //	// 保存修改
 //	composer.Save("./composer.json", true)
 func (c *ComposerJSON) RemoveDependency(packageName string) bool {
-	return dependency.RemoveDependency(c.Require, packageName)
+	if !dependency.RemoveDependency(c.Require, packageName) {
+		return false
+	}
+
+	c.editSource(func(m *manipulator.Manipulator) error {
+		m.RemoveLink("require", packageName)
+		return nil
+	})
+	return true
 }
 
 // RemoveDevDependency 从require-dev部分移除包
@@ -445,7 +601,15 @@ func (c *ComposerJSON) RemoveDependency(packageName string) bool {
 //	// 保存修改
 //	composer.Save("./composer.json", true)
 func (c *ComposerJSON) RemoveDevDependency(packageName string) bool {
-	return dependency.RemoveDependency(c.RequireDev, packageName)
+	if !dependency.RemoveDependency(c.RequireDev, packageName) {
+		return false
+	}
+
+	c.editSource(func(m *manipulator.Manipulator) error {
+		m.RemoveLink("require-dev", packageName)
+		return nil
+	})
+	return true
 }
 
 // GetAllDependencies 返回所有依赖项（require和require-dev合并）
@@ -467,6 +631,36 @@ func (c *ComposerJSON) GetAllDependencies() map[string]string {
 	return dependency.MergeDependencies(c.Require, c.RequireDev)
 }
 
+// FindContradictions 找出require和require-dev中被conflict完全排除、因而无法满足的依赖
+//
+// 返回:
+//   - []constraint.Contradiction: 先require后require-dev、各自按包名排序的矛盾列表
+//   - error: 如果涉及的版本约束无效，返回错误
+//
+// 示例:
+//
+//	composer, _ := composer.ParseFile("./composer.json")
+//
+//	found, err := composer.FindContradictions()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, c := range found {
+//		fmt.Println(c) // monolog/monolog: require "^2.0" is excluded by conflict ">=2.0"
+//	}
+func (c *ComposerJSON) FindContradictions() ([]constraint.Contradiction, error) {
+	found, err := constraint.FindContradictions(c.Require, c.Conflict)
+	if err != nil {
+		return nil, err
+	}
+
+	dev, err := constraint.FindContradictions(c.RequireDev, c.Conflict)
+	if err != nil {
+		return nil, err
+	}
+	return append(found, dev...), nil
+}
+
 // GetPSR4Map 获取PSR-4自动加载命名空间映射
 //
 // 返回:
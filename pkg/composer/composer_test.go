@@ -828,3 +828,163 @@ func Test_convertToComposerJSON(t *testing.T) {
 		})
 	}
 }
+
+// TestComposerJSON_SavePreservesFormat 测试保存解析得到的结构体时保留原始格式
+func TestComposerJSON_SavePreservesFormat(t *testing.T) {
+	original := "{\r\n" +
+		"  \"name\": \"vendor/project\",\r\n" +
+		"  \"type\": \"project\",\r\n" +
+		"  \"require\": {\r\n" +
+		"    \"symfony/console\": \"^6.0\",\r\n" +
+		"    \"monolog/monolog\": \"^3.0\"\r\n" +
+		"  },\r\n" +
+		"  \"config\": {\r\n" +
+		"    \"preferred-install\": \"dist\",\r\n" +
+		"    \"optimize-autoloader\": true\r\n" +
+		"  }\r\n" +
+		"}\r\n"
+
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "composer.json")
+	if err := os.WriteFile(filePath, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	composer, err := ParseFile(filePath)
+	if err != nil {
+		t.Fatalf("ParseFile() returned unexpected error: %v", err)
+	}
+
+	format := composer.Format()
+	if format.Indent != "  " || format.Newline != "\r\n" || !format.FinalNewline {
+		t.Errorf("Format() = %+v, want two-space indent, CRLF and final newline", format)
+	}
+
+	// 未修改时保存结果与原文件完全一致
+	if err := composer.Save(filePath, true); err != nil {
+		t.Fatalf("Save() returned unexpected error: %v", err)
+	}
+	content, _ := os.ReadFile(filePath)
+	if string(content) != original {
+		t.Errorf("Save() without changes modified the file:\n%q", content)
+	}
+
+	// 添加一个依赖只影响require部分的末尾
+	if err := composer.AddDependency("psr/log", "^3.0"); err != nil {
+		t.Fatalf("AddDependency() returned unexpected error: %v", err)
+	}
+	if err := composer.Save(filePath, true); err != nil {
+		t.Fatalf("Save() returned unexpected error: %v", err)
+	}
+
+	want := strings.Replace(original,
+		"\"monolog/monolog\": \"^3.0\"\r\n",
+		"\"monolog/monolog\": \"^3.0\",\r\n    \"psr/log\": \"^3.0\"\r\n", 1)
+	content, _ = os.ReadFile(filePath)
+	if string(content) != want {
+		t.Errorf("Save() after AddDependency() =\n%q\nwant\n%q", content, want)
+	}
+
+	// 删除依赖后再次保存，以上一次保存的内容为基准
+	composer.RemoveDependency("symfony/console")
+	if err := composer.Save(filePath, true); err != nil {
+		t.Fatalf("Save() returned unexpected error: %v", err)
+	}
+
+	want = strings.Replace(want, "    \"symfony/console\": \"^6.0\",\r\n", "", 1)
+	content, _ = os.ReadFile(filePath)
+	if string(content) != want {
+		t.Errorf("Save() after RemoveDependency() =\n%q\nwant\n%q", content, want)
+	}
+}
+
+// TestComposerJSON_DependencyEditsUseManipulator 测试依赖的增删直接作用于原始文本
+func TestComposerJSON_DependencyEditsUseManipulator(t *testing.T) {
+	original := `{
+    "name": "vendor/project",
+    "require": {"monolog/monolog": "^3.0", "symfony/console": "^6.0"},
+    "require-dev": {
+        "phpunit/phpunit": "^10.0"
+    },
+    "config": {
+        "sort-packages": true,
+        "allow-plugins": {
+            "php-http/discovery": true
+        }
+    }
+}
+`
+	composer, err := ParseString(original)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+	if !composer.Config.SortPackages {
+		t.Fatalf("Config.SortPackages should be parsed from config.sort-packages")
+	}
+
+	// sort-packages开启时新依赖插入到有序位置，行内对象保持行内
+	if err := composer.AddDependency("guzzlehttp/guzzle", "^7.0"); err != nil {
+		t.Fatalf("AddDependency() returned unexpected error: %v", err)
+	}
+	if err := composer.AddDevDependency("mockery/mockery", "^1.6"); err != nil {
+		t.Fatalf("AddDevDependency() returned unexpected error: %v", err)
+	}
+	if !composer.RemoveDependency("symfony/console") {
+		t.Fatalf("RemoveDependency() returned false for an existing dependency")
+	}
+	composer.Description = "Edited through the manipulator"
+
+	got, err := composer.ToJSON(true)
+	if err != nil {
+		t.Fatalf("ToJSON() returned unexpected error: %v", err)
+	}
+
+	want := `{
+    "name": "vendor/project",
+    "require": {"guzzlehttp/guzzle": "^7.0", "monolog/monolog": "^3.0"},
+    "require-dev": {
+        "mockery/mockery": "^1.6",
+        "phpunit/phpunit": "^10.0"
+    },
+    "config": {
+        "sort-packages": true,
+        "allow-plugins": {
+            "php-http/discovery": true
+        }
+    },
+    "description": "Edited through the manipulator"
+}
+`
+	if got != want {
+		t.Errorf("ToJSON() =\n%s\nwant\n%s", got, want)
+	}
+
+	if composer.RemoveDevDependency("missing/package") {
+		t.Errorf("RemoveDevDependency() should return false for missing dependencies")
+	}
+}
+
+func TestComposerJSON_FindContradictions(t *testing.T) {
+	composer, err := ParseString(`{
+		"name": "vendor/project",
+		"require": {"monolog/monolog": "^2.0", "symfony/console": "^6.0"},
+		"require-dev": {"phpunit/phpunit": "^9.6"},
+		"conflict": {"monolog/monolog": ">=2.0", "phpunit/phpunit": "9.*", "symfony/console": "6.2.0"}
+	}`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	found, err := composer.FindContradictions()
+	if err != nil {
+		t.Fatalf("FindContradictions() returned unexpected error: %v", err)
+	}
+	if len(found) != 2 || found[0].Package != "monolog/monolog" || found[1].Package != "phpunit/phpunit" {
+		t.Errorf("FindContradictions() = %v, want monolog/monolog and phpunit/phpunit", found)
+	}
+
+	composer.Conflict["symfony/console"] = "not a constraint"
+	if _, err := composer.FindContradictions(); err == nil {
+		t.Errorf("FindContradictions() should fail for invalid constraints")
+	}
+}
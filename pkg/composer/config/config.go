@@ -1,6 +1,14 @@
 // Package config provides functionality related to PHP Composer configuration
 package config
 
+import (
+	"fmt"
+	"sort"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/dependency"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/version"
+)
+
 // Config contains configuration information for Composer
 type Config struct {
 	ProcessTimeout        int                    `json:"process-timeout,omitempty"`
@@ -44,6 +52,7 @@ type Config struct {
 	DiscardPatches        bool                   `json:"discard-patches,omitempty"`
 	ArchiveFormat         string                 `json:"archive-format,omitempty"`
 	ArchiveDir            string                 `json:"archive-dir,omitempty"`
+	SortPackages          bool                   `json:"sort-packages,omitempty"`
 }
 
 // DefaultConfig returns a Config with sensible defaults
@@ -61,3 +70,53 @@ func DefaultConfig() *Config {
 		OptimizeAutoloader: false,
 	}
 }
+
+// ValidatePlatform checks config.platform overrides: every key must be a platform
+// package such as "php" or "ext-intl", and every value a valid version such as "8.1.2".
+// Entries are checked in name order so the reported error is deterministic.
+func ValidatePlatform(platform map[string]string) error {
+	names := make([]string, 0, len(platform))
+	for name := range platform {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := ValidatePlatformOverride(name, platform[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidatePlatformOverride checks a single config.platform entry
+func ValidatePlatformOverride(name, v string) error {
+	if err := dependency.ValidatePlatformPackageName(name); err != nil {
+		return fmt.Errorf("invalid config.platform entry: %w", err)
+	}
+	if _, err := version.Normalize(v); err != nil {
+		return fmt.Errorf("invalid config.platform version for '%s': %w", name, err)
+	}
+	return nil
+}
+
+// SetPlatform sets the simulated version of a platform package in config.platform
+func (c *Config) SetPlatform(name, v string) error {
+	if err := ValidatePlatformOverride(name, v); err != nil {
+		return err
+	}
+	if c.Platform == nil {
+		c.Platform = make(map[string]string)
+	}
+	c.Platform[name] = v
+	return nil
+}
+
+// RemovePlatform removes a platform package from config.platform and reports whether it was present
+func (c *Config) RemovePlatform(name string) bool {
+	if _, ok := c.Platform[name]; !ok {
+		return false
+	}
+	delete(c.Platform, name)
+	return true
+}
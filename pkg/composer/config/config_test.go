@@ -106,3 +106,47 @@ func TestDefaultConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestValidatePlatform(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform map[string]string
+		wantErr  bool
+	}{
+		{"empty", nil, false},
+		{"valid", map[string]string{"php": "8.1.2", "ext-intl": "1.0", "lib-icu": "72.1"}, false},
+		{"not a platform package", map[string]string{"symfony/console": "6.0.0"}, true},
+		{"invalid version", map[string]string{"php": "eight"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidatePlatform(tt.platform); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePlatform() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetAndRemovePlatform(t *testing.T) {
+	var c Config
+	if err := c.SetPlatform("php", "8.1.2"); err != nil {
+		t.Fatalf("SetPlatform() error = %v", err)
+	}
+	if c.Platform["php"] != "8.1.2" {
+		t.Errorf("Platform[php] = %q, want 8.1.2", c.Platform["php"])
+	}
+	if err := c.SetPlatform("monolog/monolog", "3.0.0"); err == nil {
+		t.Error("SetPlatform(monolog/monolog) error = nil, want error")
+	}
+	if _, ok := c.Platform["monolog/monolog"]; ok {
+		t.Error("SetPlatform() stored an invalid entry")
+	}
+
+	if !c.RemovePlatform("php") {
+		t.Error("RemovePlatform(php) = false, want true")
+	}
+	if c.RemovePlatform("php") {
+		t.Error("RemovePlatform(php) second call = true, want false")
+	}
+}
@@ -0,0 +1,432 @@
+// Package constraint 提供Composer版本约束的解析和匹配功能
+//
+// 本包实现了Composer的完整约束语法，包括：
+// - 或（"||"）和与（空格或逗号）组合，如"^7.4 || ^8.0"、">=1.2 <2.0,!=1.5"
+// - 比较运算符，如">=1.0"、"!=1.5"、"<2.0"
+// - 通配符，如"1.0.*"、"*"
+// - 波浪号和插入号范围，如"~1.2.3"、"^1.2"
+// - 连字符范围，如"1.0 - 2.0"
+// - 稳定性后缀和标志，如"1.0.0-beta2"、">=1.0@beta"
+// - 分支约束和别名，如"dev-main#abc123"、"2.x-dev"、"1.0.x-dev as 1.0.0"
+//
+// 解析结果与Composer的VersionParser::parseConstraints一致。
+package constraint
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/version"
+)
+
+// ErrInvalidConstraint 表示版本约束无效
+var ErrInvalidConstraint = errors.New("invalid version constraint")
+
+// Operator 是约束的比较运算符
+type Operator string
+
+// 支持的比较运算符
+const (
+	OpEqual          Operator = "=="
+	OpNotEqual       Operator = "!="
+	OpGreater        Operator = ">"
+	OpGreaterOrEqual Operator = ">="
+	OpLess           Operator = "<"
+	OpLessOrEqual    Operator = "<="
+)
+
+// Constraint 是解析后的版本约束
+type Constraint interface {
+	// Matches 判断版本是否满足约束，版本可以是未规范化的形式，如"v1.2"、"dev-main"
+	Matches(version string) bool
+
+	// String 返回Composer风格的规范化表示，如"[>= 1.2.0.0-dev < 2.0.0.0-dev]"
+	String() string
+
+	// matches 判断规范化后的版本是否满足约束
+	matches(normalized string) bool
+}
+
+// Single 是一个运算符和一个规范化版本组成的简单约束，如">= 1.0.0.0-dev"
+type Single struct {
+	Operator Operator
+	Version  string
+}
+
+// Matches 判断版本是否满足约束
+func (c *Single) Matches(v string) bool {
+	return matchVersion(c, v)
+}
+
+// String 返回约束的规范化表示
+func (c *Single) String() string {
+	return string(c.Operator) + " " + c.Version
+}
+
+func (c *Single) matches(v string) bool {
+	vBranch, cBranch := version.IsBranch(v), version.IsBranch(c.Version)
+	if c.Operator == OpNotEqual && (vBranch || cBranch) {
+		return v != c.Version
+	}
+	if vBranch && cBranch {
+		return c.Operator == OpEqual && v == c.Version
+	}
+	// 分支不能与数字版本比较
+	if vBranch || cBranch {
+		return false
+	}
+
+	cmp := version.Compare(v, c.Version)
+	switch c.Operator {
+	case OpEqual:
+		return cmp == 0
+	case OpNotEqual:
+		return cmp != 0
+	case OpGreater:
+		return cmp > 0
+	case OpGreaterOrEqual:
+		return cmp >= 0
+	case OpLess:
+		return cmp < 0
+	case OpLessOrEqual:
+		return cmp <= 0
+	}
+	return false
+}
+
+// Multi 是多个约束的组合，Conjunctive为true时表示与（全部满足），否则表示或（满足其一）
+type Multi struct {
+	Constraints []Constraint
+	Conjunctive bool
+}
+
+// Matches 判断版本是否满足约束
+func (c *Multi) Matches(v string) bool {
+	return matchVersion(c, v)
+}
+
+// String 返回约束的规范化表示，如"[>= 1.0.0.0-dev < 2.0.0.0-dev]"
+func (c *Multi) String() string {
+	sep := " || "
+	if c.Conjunctive {
+		sep = " "
+	}
+	parts := make([]string, 0, len(c.Constraints))
+	for _, sub := range c.Constraints {
+		parts = append(parts, sub.String())
+	}
+	return "[" + strings.Join(parts, sep) + "]"
+}
+
+func (c *Multi) matches(v string) bool {
+	for _, sub := range c.Constraints {
+		if sub.matches(v) != c.Conjunctive {
+			return !c.Conjunctive
+		}
+	}
+	return c.Conjunctive
+}
+
+// MatchAll 是匹配任意版本的约束，对应"*"
+type MatchAll struct{}
+
+// Matches 对任意有效版本都返回true
+func (MatchAll) Matches(v string) bool {
+	return matchVersion(MatchAll{}, v)
+}
+
+// String 返回"*"
+func (MatchAll) String() string {
+	return "*"
+}
+
+func (MatchAll) matches(string) bool {
+	return true
+}
+
+// matchVersion 规范化版本后进行匹配，无效的版本不满足任何约束
+func matchVersion(c Constraint, v string) bool {
+	normalized, err := version.Normalize(v)
+	if err != nil {
+		return false
+	}
+	return c.matches(normalized)
+}
+
+const versionPattern = `v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:\.(\d+))?` + version.ModifierPattern + `(?:\+[^\s]+)?`
+
+var (
+	orSplitRegex   = regexp.MustCompile(`\s*\|\|?\s*`)
+	operatorRegex  = regexp.MustCompile(`^(?:<>|!=|>=?|<=?|==?)$`)
+	aliasRegex     = regexp.MustCompile(`^([^,\s]+) +as +([^,\s]+)$`)
+	flagRegex      = regexp.MustCompile(`(?i)^([^,\s]*?)@(stable|RC|beta|alpha|dev)$`)
+	refRegex       = regexp.MustCompile(`(?i)^(dev-[^,\s@]+?|[^,\s@]+?\.x-dev)#.+$`)
+	anyRegex       = regexp.MustCompile(`^(v)?[xX*](\.[xX*])*$`)
+	tildeRegex     = regexp.MustCompile(`(?i)^~>?` + versionPattern + `$`)
+	caretRegex     = regexp.MustCompile(`(?i)^\^` + versionPattern + `$`)
+	wildcardRegex  = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:\.[xX*])+$`)
+	hyphenRegex    = regexp.MustCompile(`(?i)^(` + versionPattern + `) +- +(` + versionPattern + `)$`)
+	basicRegex     = regexp.MustCompile(`^(<>|!=|>=?|<=?|==?)?\s*(.*)$`)
+	branchRegex    = regexp.MustCompile(`^[0-9a-zA-Z-./]+$`)
+	unstableSuffix = regexp.MustCompile(`(?i)-` + version.ModifierPattern + `$`)
+)
+
+// Parse 解析Composer版本约束
+//
+// 参数:
+//   - s: 版本约束，如"^7.4 || ^8.0"、">=1.2 <2.0,!=1.5"、"1.0 - 2.0"、"dev-main#abc123"
+//
+// 返回:
+//   - Constraint: 解析后的约束
+//   - error: 如果约束无效，返回包装了ErrInvalidConstraint的错误
+//
+// 示例:
+//
+//	c, err := constraint.Parse("^7.4 || ^8.0")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(c.Matches("8.1.2")) // true
+//	fmt.Println(c.Matches("7.3.0")) // false
+//	fmt.Println(c)                  // [[>= 7.4.0.0-dev < 8.0.0.0-dev] || [>= 8.0.0.0-dev < 9.0.0.0-dev]]
+func Parse(s string) (Constraint, error) {
+	pretty := strings.TrimSpace(s)
+
+	var groups []Constraint
+	for _, orPart := range orSplitRegex.Split(pretty, -1) {
+		var parts []Constraint
+		for _, andPart := range splitAnd(orPart) {
+			parsed, err := parseSingle(andPart)
+			if err != nil {
+				return nil, fmt.Errorf("%w %q: %v", ErrInvalidConstraint, pretty, err)
+			}
+			parts = append(parts, parsed...)
+		}
+
+		if len(parts) == 1 {
+			groups = append(groups, parts[0])
+		} else {
+			groups = append(groups, &Multi{Constraints: parts, Conjunctive: true})
+		}
+	}
+
+	if len(groups) == 1 {
+		return groups[0], nil
+	}
+	return &Multi{Constraints: groups}, nil
+}
+
+// splitAnd 将或分支按空格和逗号拆分为与约束
+//
+// 连字符范围（"1.0 - 2.0"）、别名（"1.0.x-dev as 1.0.0"）和与版本分开书写的运算符（">= 1.0"）保持为一个整体。
+func splitAnd(s string) []string {
+	tokens := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	if len(tokens) == 0 {
+		return []string{""}
+	}
+
+	var parts []string
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if operatorRegex.MatchString(token) && i+1 < len(tokens) {
+			i++
+			token += tokens[i]
+		}
+		for i+2 < len(tokens) && (tokens[i+1] == "-" || tokens[i+1] == "as") {
+			token += " " + tokens[i+1] + " " + tokens[i+2]
+			i += 2
+		}
+		parts = append(parts, token)
+	}
+	return parts
+}
+
+// parseSingle 解析不含"||"和与组合的单个约束，范围约束会返回上下界两个约束
+func parseSingle(c string) ([]Constraint, error) {
+	// 去掉别名
+	if m := aliasRegex.FindStringSubmatch(c); m != nil {
+		c = m[1]
+	}
+
+	// 去掉稳定性标志，并保留给比较运算符使用
+	var stabilityModifier string
+	if m := flagRegex.FindStringSubmatch(c); m != nil {
+		c = m[1]
+		if c == "" {
+			c = "*"
+		}
+		if !strings.EqualFold(m[2], version.StabilityStable) {
+			stabilityModifier = m[2]
+		}
+	}
+
+	// 去掉只对安装有意义的#ref
+	if m := refRegex.FindStringSubmatch(c); m != nil {
+		c = m[1]
+	}
+
+	if m := anyRegex.FindStringSubmatch(c); m != nil {
+		if m[1] != "" || m[2] != "" {
+			return []Constraint{&Single{Operator: OpGreaterOrEqual, Version: "0.0.0.0-dev"}}, nil
+		}
+		return []Constraint{MatchAll{}}, nil
+	}
+
+	// 波浪号范围：~1.2表示>=1.2.0且<2.0.0，~1.2.3表示>=1.2.3且<1.3.0
+	if m := tildeRegex.FindStringSubmatch(c); m != nil {
+		if strings.HasPrefix(c, "~>") {
+			return nil, fmt.Errorf("invalid operator \"~>\", you probably meant to use the \"~\" operator")
+		}
+
+		position := lastPosition(m[1:5])
+		low, err := version.Normalize(c[1:] + devSuffix(m[5:8]))
+		if err != nil {
+			return nil, err
+		}
+		high := manipulate(m[1:5], max(1, position-1), 1) + "-dev"
+		return rangeOf(low, high), nil
+	}
+
+	// 插入号范围：不改变最左侧的非零数字，^1.2表示<2.0.0，^0.3表示<0.4.0
+	if m := caretRegex.FindStringSubmatch(c); m != nil {
+		position := 3
+		switch {
+		case m[1] != "0" || m[2] == "":
+			position = 1
+		case m[2] != "0" || m[3] == "":
+			position = 2
+		}
+
+		low, err := version.Normalize(c[1:] + devSuffix(m[5:8]))
+		if err != nil {
+			return nil, err
+		}
+		high := manipulate(m[1:5], position, 1) + "-dev"
+		return rangeOf(low, high), nil
+	}
+
+	// 通配符范围：1.0.*表示>=1.0.0且<1.1.0
+	if m := wildcardRegex.FindStringSubmatch(c); m != nil {
+		nums := []string{m[1], m[2], m[3], ""}
+		position := lastPosition(nums)
+		low := manipulate(nums, position, 0) + "-dev"
+		high := manipulate(nums, position, 1) + "-dev"
+		if low == "0.0.0.0-dev" {
+			return []Constraint{&Single{Operator: OpLess, Version: high}}, nil
+		}
+		return rangeOf(low, high), nil
+	}
+
+	// 连字符范围：包含两端，上界不完整时包含以其开头的所有版本，如"1.0 - 2.0"表示<2.1.0
+	if m := hyphenRegex.FindStringSubmatch(c); m != nil {
+		from, to := m[2:9], m[10:17]
+
+		low, err := version.Normalize(m[1])
+		if err != nil {
+			return nil, err
+		}
+		if from[4] == "" && from[6] == "" {
+			low += "-dev"
+		}
+
+		high, err := version.Normalize(m[9])
+		if err != nil {
+			return nil, err
+		}
+		if (to[1] != "" && to[2] != "") || to[4] != "" || to[5] != "" || to[6] != "" {
+			return []Constraint{
+				&Single{Operator: OpGreaterOrEqual, Version: low},
+				&Single{Operator: OpLessOrEqual, Version: high},
+			}, nil
+		}
+
+		position := 2
+		if to[1] == "" {
+			position = 1
+		}
+		return rangeOf(low, manipulate(to[:4], position, 1)+"-dev"), nil
+	}
+
+	// 比较运算符
+	m := basicRegex.FindStringSubmatch(c)
+	raw := m[2]
+	normalized, err := version.Normalize(raw)
+	if err != nil {
+		// 兼容foobar-dev这样应写作dev-foobar的约束
+		if !strings.HasSuffix(raw, "-dev") || !branchRegex.MatchString(raw) {
+			return nil, err
+		}
+		normalized, _ = version.Normalize("dev-" + strings.TrimSuffix(raw, "-dev"))
+	}
+
+	op := operator(m[1])
+	if op != OpEqual && stabilityModifier != "" && version.ParseStability(normalized) == version.StabilityStable {
+		normalized += "-" + stabilityModifier
+	} else if (op == OpLess || op == OpGreaterOrEqual) && !unstableSuffix.MatchString(raw) && !strings.HasPrefix(raw, "dev-") {
+		normalized += "-dev"
+	}
+
+	return []Constraint{&Single{Operator: op, Version: normalized}}, nil
+}
+
+// operator 将约束中书写的运算符转换为Operator
+func operator(s string) Operator {
+	switch s {
+	case "", "=":
+		return OpEqual
+	case "<>":
+		return OpNotEqual
+	}
+	return Operator(s)
+}
+
+// rangeOf 返回[low, high)范围的上下界约束
+func rangeOf(low, high string) []Constraint {
+	return []Constraint{
+		&Single{Operator: OpGreaterOrEqual, Version: low},
+		&Single{Operator: OpLess, Version: high},
+	}
+}
+
+// devSuffix 在没有稳定性修饰符时返回"-dev"，使下界包含该版本的预发布版本
+func devSuffix(modifiers []string) string {
+	for _, m := range modifiers {
+		if m != "" {
+			return ""
+		}
+	}
+	return "-dev"
+}
+
+// lastPosition 返回最后一个出现的版本号部分的位置（从1开始）
+func lastPosition(nums []string) int {
+	for i := len(nums) - 1; i > 0; i-- {
+		if nums[i] != "" {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+// manipulate 将position之后的版本号部分置零，并把position处的部分加上increment
+func manipulate(nums []string, position, increment int) string {
+	parts := make([]string, 4)
+	for i := range parts {
+		switch {
+		case i+1 > position:
+			parts[i] = "0"
+		case i+1 == position:
+			n, _ := strconv.Atoi(nums[i])
+			parts[i] = strconv.Itoa(n + increment)
+		default:
+			parts[i] = nums[i]
+		}
+	}
+	return strings.Join(parts, ".")
+}
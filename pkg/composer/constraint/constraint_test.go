@@ -0,0 +1,103 @@
+package constraint
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{"*", "*"},
+		{"1.0.0", "== 1.0.0.0"},
+		{"v1.0", "== 1.0.0.0"},
+		{"==1.0.0", "== 1.0.0.0"},
+		{"!=1.0.0", "!= 1.0.0.0"},
+		{">1.0.0", "> 1.0.0.0"},
+		{">=1.0", ">= 1.0.0.0-dev"},
+		{">= 1.0", ">= 1.0.0.0-dev"},
+		{"<2.0", "< 2.0.0.0-dev"},
+		{"<=2.0", "<= 2.0.0.0"},
+		{"1.0.0-beta2", "== 1.0.0.0-beta2"},
+		{">=1.0@beta", ">= 1.0.0.0-beta"},
+		{"@dev", "*"},
+		{"^1.2.3", "[>= 1.2.3.0-dev < 2.0.0.0-dev]"},
+		{"^0.3", "[>= 0.3.0.0-dev < 0.4.0.0-dev]"},
+		{"^0.0.3", "[>= 0.0.3.0-dev < 0.0.4.0-dev]"},
+		{"^2.0@beta", "[>= 2.0.0.0-dev < 3.0.0.0-dev]"},
+		{"~1.2", "[>= 1.2.0.0-dev < 2.0.0.0-dev]"},
+		{"~1.2.3", "[>= 1.2.3.0-dev < 1.3.0.0-dev]"},
+		{"~1.2.3-beta", "[>= 1.2.3.0-beta < 1.3.0.0-dev]"},
+		{"1.0.*", "[>= 1.0.0.0-dev < 1.1.0.0-dev]"},
+		{"0.*", "< 1.0.0.0-dev"},
+		{"1.0 - 2.0", "[>= 1.0.0.0-dev < 2.1.0.0-dev]"},
+		{"1.0.0 - 2.1.3", "[>= 1.0.0.0-dev <= 2.1.3.0]"},
+		{">=1.2 <2.0,!=1.5", "[>= 1.2.0.0-dev < 2.0.0.0-dev != 1.5.0.0]"},
+		{"^7.4 || ^8.0", "[[>= 7.4.0.0-dev < 8.0.0.0-dev] || [>= 8.0.0.0-dev < 9.0.0.0-dev]]"},
+		{"dev-main#abc123", "== dev-main"},
+		{"2.x-dev", "== 2.9999999.9999999.9999999-dev"},
+		{"1.0.x-dev as 1.0.0", "== 1.0.9999999.9999999-dev"},
+		{"feature-dev", "== dev-feature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			c, err := Parse(tt.constraint)
+			if err != nil {
+				t.Fatalf("Parse() returned unexpected error: %v", err)
+			}
+			if got := c.String(); got != tt.want {
+				t.Errorf("Parse() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, s := range []string{"", "invalid", "1.0.0$", "~>1.0", "^1.0 ||", ">=1.0 <"} {
+		if _, err := Parse(s); !errors.Is(err, ErrInvalidConstraint) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidConstraint", s, err)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		constraint string
+		match      []string
+		noMatch    []string
+	}{
+		{"^1.2", []string{"1.2.0", "v1.9.9", "1.3.0-beta1"}, []string{"1.1.9", "2.0.0", "dev-main"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"^7.4 || ^8.0", []string{"7.4.33", "8.1.2"}, []string{"7.3.0", "9.0.0"}},
+		{"1.0 - 2.0", []string{"1.0.0", "2.0.5"}, []string{"2.1.0", "0.9"}},
+		{"1.0.0 - 2.1.3", []string{"2.1.3"}, []string{"2.1.4"}},
+		{">=1.2 <2.0,!=1.5", []string{"1.2", "1.5.1"}, []string{"1.5", "2.0"}},
+		{"1.0.0-beta2", []string{"1.0.0-b2"}, []string{"1.0.0-beta10", "1.0.0"}},
+		{"<1.0.0-patch1", []string{"1.0.0"}, []string{"1.0.0-p1"}},
+		{"dev-main", []string{"dev-main"}, []string{"dev-other", "1.0.0"}},
+		{"!=dev-main", []string{"dev-other", "1.0.0"}, []string{"dev-main"}},
+		{"*", []string{"dev-main", "0.0.1"}, []string{"not a version"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			c, err := Parse(tt.constraint)
+			if err != nil {
+				t.Fatalf("Parse() returned unexpected error: %v", err)
+			}
+			for _, v := range tt.match {
+				if !c.Matches(v) {
+					t.Errorf("%s should match %s", tt.constraint, v)
+				}
+			}
+			for _, v := range tt.noMatch {
+				if c.Matches(v) {
+					t.Errorf("%s should not match %s", tt.constraint, v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,500 @@
+package constraint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/version"
+)
+
+// 数字版本空间的两端，与Composer的Interval::fromZero和Interval::untilPositiveInfinity一致
+var (
+	zeroBound     = Bound{Version: "0.0.0.0-dev", Inclusive: true}
+	infinityBound = Bound{Version: "9223372036854775807.0.0.0", Inclusive: false}
+)
+
+// Bound 是区间的一个端点
+type Bound struct {
+	// Version 规范化后的版本
+	Version string
+
+	// Inclusive 是否包含端点本身：作为起点时表示">="，否则表示">"；作为终点时表示"<="，否则表示"<"
+	Inclusive bool
+}
+
+// Interval 是数字版本的一个连续区间
+type Interval struct {
+	Start Bound
+	End   Bound
+}
+
+// Branches 是分支版本（dev-*）的集合
+type Branches struct {
+	// Names 排好序的分支名，如"dev-main"
+	Names []string
+
+	// Exclude 为false时集合只包含Names，为true时集合包含除Names以外的所有分支
+	Exclude bool
+}
+
+// Set 是约束所允许的版本集合：若干不相交的数字区间加上一个分支集合
+//
+// Set的零值是空集。应使用SetOf和Set的方法构造，以保证Intervals有序且互不相交。
+type Set struct {
+	Intervals []Interval
+	Branches  Branches
+}
+
+// SetOf 返回约束所允许的版本集合
+//
+// 示例:
+//
+//	c, _ := constraint.Parse("^1.2, !=1.5.0")
+//	fmt.Println(constraint.SetOf(c)) // >=1.2 <1.5-stable || >1.5 <2.0
+//
+// 输出中的"<1.5-stable"表示包含1.5的预发布版本，"<1.5"会被解析为"<1.5-dev"而排除它们。
+func SetOf(c Constraint) Set {
+	switch c := c.(type) {
+	case MatchAll:
+		return Set{Intervals: []Interval{{zeroBound, infinityBound}}, Branches: Branches{Exclude: true}}
+	case *Multi:
+		var s Set
+		for i, sub := range c.Constraints {
+			subSet := SetOf(sub)
+			switch {
+			case i == 0:
+				s = subSet
+			case c.Conjunctive:
+				s = s.Intersect(subSet)
+			default:
+				s = s.Union(subSet)
+			}
+		}
+		return s
+	case *Single:
+		return singleSet(c)
+	}
+	return Set{}
+}
+
+// singleSet 返回简单约束的版本集合
+func singleSet(c *Single) Set {
+	v := c.Version
+	if version.IsBranch(v) {
+		switch c.Operator {
+		case OpEqual:
+			return Set{Branches: Branches{Names: []string{v}}}
+		case OpNotEqual:
+			return Set{Intervals: []Interval{{zeroBound, infinityBound}}, Branches: Branches{Names: []string{v}, Exclude: true}}
+		}
+		// 分支不能与数字版本比较
+		return Set{}
+	}
+
+	var intervals []Interval
+	branches := Branches{}
+	switch c.Operator {
+	case OpEqual:
+		intervals = []Interval{{Bound{v, true}, Bound{v, true}}}
+	case OpNotEqual:
+		intervals = []Interval{{zeroBound, Bound{v, false}}, {Bound{v, false}, infinityBound}}
+		branches.Exclude = true
+	case OpGreater, OpGreaterOrEqual:
+		intervals = []Interval{{Bound{v, c.Operator == OpGreaterOrEqual}, infinityBound}}
+	case OpLess, OpLessOrEqual:
+		intervals = []Interval{{zeroBound, Bound{v, c.Operator == OpLessOrEqual}}}
+	}
+	return Set{Intervals: normalizeIntervals(intervals), Branches: branches}
+}
+
+// Intersect 返回两个集合的交集
+func (s Set) Intersect(o Set) Set {
+	var intervals []Interval
+	for _, a := range s.Intervals {
+		for _, b := range o.Intervals {
+			start, end := a.Start, a.End
+			if compareStarts(b.Start, start) > 0 {
+				start = b.Start
+			}
+			if compareEnds(b.End, end) < 0 {
+				end = b.End
+			}
+			intervals = append(intervals, Interval{start, end})
+		}
+	}
+
+	var branches Branches
+	switch {
+	case !s.Branches.Exclude && !o.Branches.Exclude:
+		branches.Names = intersectNames(s.Branches.Names, o.Branches.Names)
+	case !s.Branches.Exclude:
+		branches.Names = subtractNames(s.Branches.Names, o.Branches.Names)
+	case !o.Branches.Exclude:
+		branches.Names = subtractNames(o.Branches.Names, s.Branches.Names)
+	default:
+		branches = Branches{Names: unionNames(s.Branches.Names, o.Branches.Names), Exclude: true}
+	}
+
+	return Set{Intervals: normalizeIntervals(intervals), Branches: branches}
+}
+
+// Union 返回两个集合的并集
+func (s Set) Union(o Set) Set {
+	intervals := append(append([]Interval(nil), s.Intervals...), o.Intervals...)
+
+	var branches Branches
+	switch {
+	case !s.Branches.Exclude && !o.Branches.Exclude:
+		branches.Names = unionNames(s.Branches.Names, o.Branches.Names)
+	case !s.Branches.Exclude:
+		branches = Branches{Names: subtractNames(o.Branches.Names, s.Branches.Names), Exclude: true}
+	case !o.Branches.Exclude:
+		branches = Branches{Names: subtractNames(s.Branches.Names, o.Branches.Names), Exclude: true}
+	default:
+		branches = Branches{Names: intersectNames(s.Branches.Names, o.Branches.Names), Exclude: true}
+	}
+
+	return Set{Intervals: normalizeIntervals(intervals), Branches: branches}
+}
+
+// Complement 返回集合的补集，即不被集合允许的所有版本
+func (s Set) Complement() Set {
+	return Set{
+		Intervals: complementIntervals(s.Intervals),
+		Branches:  Branches{Names: append([]string(nil), s.Branches.Names...), Exclude: !s.Branches.Exclude},
+	}
+}
+
+// IsEmpty 判断集合是否为空，即约束不允许任何版本
+func (s Set) IsEmpty() bool {
+	return len(s.Intervals) == 0 && !s.Branches.Exclude && len(s.Branches.Names) == 0
+}
+
+// IsSubsetOf 判断集合是否为o的子集
+func (s Set) IsSubsetOf(o Set) bool {
+	return s.Intersect(o.Complement()).IsEmpty()
+}
+
+// Equal 判断两个集合是否包含相同的版本
+func (s Set) Equal(o Set) bool {
+	return s.IsSubsetOf(o) && o.IsSubsetOf(s)
+}
+
+// String 返回与集合等价的紧凑Composer约束，如">=1.2 <1.5 || >=2.0"、"!=1.5"、"*"
+//
+// 空集返回"[]"（与Composer的MatchNoneConstraint一致）。
+//
+// 注意:
+//   - Composer语法无法表示"部分数字版本加上几乎所有分支"，这种集合只输出数字部分
+func (s Set) String() string {
+	if s.IsEmpty() {
+		return "[]"
+	}
+
+	// 排除了若干个版本的全集，用"!="表示
+	if s.Branches.Exclude {
+		if points, ok := excludedPoints(s.Intervals); ok {
+			var parts []string
+			for _, p := range points {
+				parts = append(parts, "!="+pretty(p))
+			}
+			for _, name := range s.Branches.Names {
+				parts = append(parts, "!="+name)
+			}
+			if len(parts) == 0 {
+				return "*"
+			}
+			return strings.Join(parts, " ")
+		}
+	}
+
+	var parts []string
+	for _, iv := range s.Intervals {
+		parts = append(parts, iv.String())
+	}
+	if !s.Branches.Exclude {
+		parts = append(parts, s.Branches.Names...)
+	}
+	return strings.Join(parts, " || ")
+}
+
+// String 返回区间的Composer约束表示，如">=1.2 <2.0"
+func (iv Interval) String() string {
+	startsAtZero := iv.Start == zeroBound
+	endsAtInfinity := iv.End == infinityBound
+
+	switch {
+	case startsAtZero && endsAtInfinity:
+		return "*"
+	case iv.Start.Version == iv.End.Version:
+		return pretty(iv.Start.Version)
+	case startsAtZero:
+		return renderEnd(iv.End)
+	case endsAtInfinity:
+		return renderStart(iv.Start)
+	}
+	return renderStart(iv.Start) + " " + renderEnd(iv.End)
+}
+
+// Intersects 判断两个约束是否存在同时满足的版本
+//
+// 示例:
+//
+//	a, _ := constraint.Parse("^1.2")
+//	b, _ := constraint.Parse("<1.5 || >=2")
+//	constraint.Intersects(a, b) // true
+func Intersects(a, b Constraint) bool {
+	return !SetOf(a).Intersect(SetOf(b)).IsEmpty()
+}
+
+// IsSubsetOf 判断满足a的版本是否都满足b
+//
+// 示例:
+//
+//	a, _ := constraint.Parse("~1.4.0")
+//	b, _ := constraint.Parse("^1.0")
+//	constraint.IsSubsetOf(a, b) // true
+func IsSubsetOf(a, b Constraint) bool {
+	return SetOf(a).IsSubsetOf(SetOf(b))
+}
+
+// Contradiction 描述一个无法满足的依赖：要求的所有版本都被冲突约束排除
+type Contradiction struct {
+	// Package 包名
+	Package string
+
+	// Require 要求的版本约束
+	Require string
+
+	// Conflict 冲突的版本约束
+	Conflict string
+}
+
+// String 返回矛盾的描述
+func (c Contradiction) String() string {
+	return fmt.Sprintf("%s: require %q is excluded by conflict %q", c.Package, c.Require, c.Conflict)
+}
+
+// FindContradictions 找出require中被conflict完全排除的依赖
+//
+// 参数:
+//   - require: 依赖映射，key为包名，value为版本约束
+//   - conflict: 冲突映射，key为包名，value为版本约束
+//
+// 返回:
+//   - []Contradiction: 按包名排序的矛盾列表，包名比较不区分大小写
+//   - error: 如果涉及的约束无效，返回包装了ErrInvalidConstraint的错误
+//
+// 示例:
+//
+//	found, _ := constraint.FindContradictions(
+//		map[string]string{"monolog/monolog": "^2.0"},
+//		map[string]string{"monolog/monolog": ">=2.0"},
+//	)
+//	fmt.Println(found[0]) // monolog/monolog: require "^2.0" is excluded by conflict ">=2.0"
+func FindContradictions(require, conflict map[string]string) ([]Contradiction, error) {
+	conflicts := make(map[string]string, len(conflict))
+	for name, c := range conflict {
+		conflicts[strings.ToLower(name)] = c
+	}
+
+	var found []Contradiction
+	for name, req := range require {
+		conf, ok := conflicts[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+
+		reqConstraint, err := Parse(req)
+		if err != nil {
+			return nil, fmt.Errorf("require %s: %w", name, err)
+		}
+		confConstraint, err := Parse(conf)
+		if err != nil {
+			return nil, fmt.Errorf("conflict %s: %w", name, err)
+		}
+
+		if IsSubsetOf(reqConstraint, confConstraint) {
+			found = append(found, Contradiction{Package: name, Require: req, Conflict: conf})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Package < found[j].Package
+	})
+	return found, nil
+}
+
+// compareStarts 比较两个区间起点，">= v"排在"> v"之前
+func compareStarts(a, b Bound) int {
+	if c := version.Compare(a.Version, b.Version); c != 0 {
+		return c
+	}
+	switch {
+	case a.Inclusive == b.Inclusive:
+		return 0
+	case a.Inclusive:
+		return -1
+	}
+	return 1
+}
+
+// compareEnds 比较两个区间终点，"< v"排在"<= v"之前
+func compareEnds(a, b Bound) int {
+	if c := version.Compare(a.Version, b.Version); c != 0 {
+		return c
+	}
+	switch {
+	case a.Inclusive == b.Inclusive:
+		return 0
+	case a.Inclusive:
+		return 1
+	}
+	return -1
+}
+
+// nonEmpty 判断[start, end]区间是否包含至少一个版本
+func nonEmpty(start, end Bound) bool {
+	c := version.Compare(start.Version, end.Version)
+	return c < 0 || (c == 0 && start.Inclusive && end.Inclusive)
+}
+
+// normalizeIntervals 去掉空区间，按起点排序并合并相交或相邻的区间
+func normalizeIntervals(intervals []Interval) []Interval {
+	var result []Interval
+	for _, iv := range intervals {
+		if nonEmpty(iv.Start, iv.End) {
+			result = append(result, iv)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return compareStarts(result[i].Start, result[j].Start) < 0
+	})
+
+	merged := result[:0]
+	for _, iv := range result {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			c := version.Compare(last.End.Version, iv.Start.Version)
+			if c > 0 || (c == 0 && (last.End.Inclusive || iv.Start.Inclusive)) {
+				if compareEnds(iv.End, last.End) > 0 {
+					last.End = iv.End
+				}
+				continue
+			}
+		}
+		merged = append(merged, iv)
+	}
+	return merged
+}
+
+// complementIntervals 返回有序不相交区间在整个数字版本空间中的补集
+func complementIntervals(intervals []Interval) []Interval {
+	var result []Interval
+	start := zeroBound
+	for _, iv := range intervals {
+		result = append(result, Interval{start, Bound{iv.Start.Version, !iv.Start.Inclusive}})
+		start = Bound{iv.End.Version, !iv.End.Inclusive}
+	}
+	result = append(result, Interval{start, infinityBound})
+	return normalizeIntervals(result)
+}
+
+// excludedPoints 如果区间恰好是数字版本全集去掉若干个单独的版本，返回这些版本
+func excludedPoints(intervals []Interval) ([]string, bool) {
+	var points []string
+	for _, iv := range complementIntervals(intervals) {
+		if iv.Start.Version != iv.End.Version {
+			return nil, false
+		}
+		points = append(points, iv.Start.Version)
+	}
+	return points, true
+}
+
+// renderStart 返回区间起点的约束表示
+func renderStart(b Bound) string {
+	if b.Inclusive {
+		return ">=" + prettyLower(b.Version)
+	}
+	return ">" + pretty(b.Version)
+}
+
+// renderEnd 返回区间终点的约束表示
+func renderEnd(b Bound) string {
+	if b.Inclusive {
+		return "<=" + pretty(b.Version)
+	}
+	return "<" + prettyLower(b.Version)
+}
+
+// prettyLower 返回">="和"<"运算符使用的版本
+//
+// 解析">="和"<"时，没有稳定性后缀的版本会自动加上"-dev"，因此这里去掉"-dev"，
+// 而稳定版本需要显式写出"-stable"才能原样解析回来。
+func prettyLower(v string) string {
+	if base, ok := strings.CutSuffix(v, "-dev"); ok && !strings.Contains(base, "-") {
+		return pretty(base)
+	}
+	if !strings.Contains(v, "-") {
+		return pretty(v) + "-" + version.StabilityStable
+	}
+	return pretty(v)
+}
+
+// pretty 去掉规范化版本末尾多余的".0"，如"1.2.0.0"变为"1.2"
+func pretty(v string) string {
+	base, suffix, hasSuffix := strings.Cut(v, "-")
+	parts := strings.Split(base, ".")
+	for len(parts) > 2 && parts[len(parts)-1] == "0" {
+		parts = parts[:len(parts)-1]
+	}
+	if hasSuffix {
+		return strings.Join(parts, ".") + "-" + suffix
+	}
+	return strings.Join(parts, ".")
+}
+
+// intersectNames 返回两个有序名称列表的交集
+func intersectNames(a, b []string) []string {
+	var result []string
+	for _, name := range a {
+		if containsName(b, name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// subtractNames 返回在a中但不在b中的名称
+func subtractNames(a, b []string) []string {
+	var result []string
+	for _, name := range a {
+		if !containsName(b, name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// unionNames 返回两个名称列表的有序并集
+func unionNames(a, b []string) []string {
+	result := append([]string(nil), a...)
+	for _, name := range b {
+		if !containsName(result, name) {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
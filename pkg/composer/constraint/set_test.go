@@ -0,0 +1,161 @@
+package constraint
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func mustParse(t *testing.T, s string) Constraint {
+	t.Helper()
+	c, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q) returned unexpected error: %v", s, err)
+	}
+	return c
+}
+
+func TestSetString(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{"*", "*"},
+		{"^1.2", ">=1.2 <2.0"},
+		{"~1.4.0", ">=1.4 <1.5"},
+		{"1.0.0", "1.0"},
+		{"!=1.5", "!=1.5"},
+		{"!=dev-main", "!=dev-main"},
+		{">1.0 <=2.0", ">1.0 <=2.0"},
+		{"0.*", "<1.0"},
+		{"1.0.0-beta1 - 2.0", ">=1.0-beta1 <2.1"},
+		{"^1 || ^1.5 || ^2", ">=1.0 <3.0"},
+		{"<1.5 || >=2", "<1.5 || >=2.0"},
+		{"^1.2, !=1.5.0", ">=1.2 <1.5-stable || >1.5 <2.0"},
+		{"dev-main || ^2", ">=2.0 <3.0 || dev-main"},
+		{">=2, <1", "[]"},
+		{"dev-a, dev-b", "[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			set := SetOf(mustParse(t, tt.constraint))
+			got := set.String()
+			if got != tt.want {
+				t.Fatalf("SetOf(%q).String() = %q, want %q", tt.constraint, got, tt.want)
+			}
+			if got == "[]" {
+				return
+			}
+			// 输出的约束应当能解析回同一个集合
+			if back := SetOf(mustParse(t, got)); !back.Equal(set) {
+				t.Errorf("%q does not round-trip: got %s", got, back)
+			}
+		})
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	caret := SetOf(mustParse(t, "^1.2"))
+	split := SetOf(mustParse(t, "<1.5 || >=2"))
+
+	if got := caret.Intersect(split).String(); got != ">=1.2 <1.5" {
+		t.Errorf("Intersect() = %s, want >=1.2 <1.5", got)
+	}
+	// 并集覆盖了所有数字版本，但不包含分支
+	union := caret.Union(split)
+	if len(union.Intervals) != 1 || union.Intervals[0].String() != "*" || union.Branches.Exclude {
+		t.Errorf("Union() = %+v, want all numeric versions", union)
+	}
+	if union.Equal(SetOf(MatchAll{})) {
+		t.Errorf("Union() should not contain branches")
+	}
+	if got := caret.Complement().String(); got != "<1.2 || >=2.0" {
+		t.Errorf("Complement() = %s, want <1.2 || >=2.0", got)
+	}
+	if !caret.Intersect(caret.Complement()).IsEmpty() {
+		t.Errorf("a set and its complement should not intersect")
+	}
+	if !caret.Union(caret.Complement()).Equal(SetOf(MatchAll{})) {
+		t.Errorf("a set and its complement should cover every version")
+	}
+	if (Set{}).String() != "[]" || !(Set{}).IsEmpty() {
+		t.Errorf("the zero Set should be empty")
+	}
+}
+
+func TestIntersectsAndSubset(t *testing.T) {
+	tests := []struct {
+		a, b       string
+		intersects bool
+		subset     bool
+	}{
+		{"^1.2", "<1.5 || >=2", true, false},
+		{"~1.4.0", "^1.0", true, true},
+		{"^1.0", "~1.4.0", true, false},
+		{"^1.0", "^2.0", false, false},
+		{"1.5.0", "!=1.5", false, false},
+		{"1.5.1", "!=1.5", true, true},
+		{"dev-main", "*", true, true},
+		{"dev-main", ">=1.0", false, false},
+		{"dev-main", "dev-main || ^1", true, true},
+		{"2.x-dev", "^2.0", true, true},
+		{"<=2.0", "<2.0", true, false},
+		{"1.0.0-beta1", "^1.0", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			a, b := mustParse(t, tt.a), mustParse(t, tt.b)
+			if got := Intersects(a, b); got != tt.intersects {
+				t.Errorf("Intersects() = %v, want %v", got, tt.intersects)
+			}
+			if got := IsSubsetOf(a, b); got != tt.subset {
+				t.Errorf("IsSubsetOf() = %v, want %v", got, tt.subset)
+			}
+		})
+	}
+}
+
+func TestFindContradictions(t *testing.T) {
+	require := map[string]string{
+		"php":             "^8.1",
+		"monolog/monolog": "^2.0",
+		"symfony/console": "^6.0",
+		"psr/log":         "^1.0 || ^3.0",
+	}
+	conflict := map[string]string{
+		"Monolog/Monolog": ">=2.0",
+		"symfony/console": "6.2.0",
+		"psr/log":         "<2.0 || >=3.0",
+	}
+
+	found, err := FindContradictions(require, conflict)
+	if err != nil {
+		t.Fatalf("FindContradictions() returned unexpected error: %v", err)
+	}
+
+	want := []Contradiction{
+		{Package: "monolog/monolog", Require: "^2.0", Conflict: ">=2.0"},
+		{Package: "psr/log", Require: "^1.0 || ^3.0", Conflict: "<2.0 || >=3.0"},
+	}
+	if len(found) != len(want) {
+		t.Fatalf("FindContradictions() = %v, want %v", found, want)
+	}
+	for i := range want {
+		if found[i] != want[i] {
+			t.Errorf("FindContradictions()[%d] = %v, want %v", i, found[i], want[i])
+		}
+	}
+
+	_, err = FindContradictions(map[string]string{"a/b": "^1.0"}, map[string]string{"a/b": "~>1.0"})
+	if !errors.Is(err, ErrInvalidConstraint) {
+		t.Errorf("FindContradictions() error = %v, want ErrInvalidConstraint", err)
+	}
+}
+
+func ExampleSetOf() {
+	c, _ := Parse("^1.2, !=1.5.0")
+	fmt.Println(SetOf(c))
+	// Output: >=1.2 <1.5-stable || >1.5 <2.0
+}
@@ -288,20 +288,23 @@ func ValidateComposerJSON(name, description, stability string) error {
 	return validation.ValidateComposerJSON(name, description, stability)
 }
 
-// ValidateVersion 验证版本字符串是否符合语义化版本规范
+// ValidateVersion 验证版本约束是否符合Composer的约束语法
 //
 // 参数:
-//   - version: 要验证的版本字符串，如"1.0.0"、"^2.1"、">=7.4"
+//   - version: 要验证的版本约束，如"1.0.0"、"^2.1"、">=7.4"
 //
 // 返回:
 //   - error: 如果验证失败，返回错误；验证通过返回nil
 //
 // 支持的格式:
 //   - 精确版本: "1.0.0"
-//   - 范围版本: ">=1.0.0"、"<=2.0.0"、">1.0.0 <2.0.0"
+//   - 范围版本: ">=1.0.0"、"<=2.0.0"、">1.0.0 <2.0.0"、">=1.2 <2.0,!=1.5"
+//   - 连字符范围: "1.0 - 2.0"
+//   - 或组合: "^7.4 || ^8.0"
 //   - 通配符: "1.0.*"
 //   - 赋值符: "^1.0.0"（兼容1.x.x）、"~1.0.0"（兼容1.0.x）
-//   - 稳定性标识: "1.0.0-beta"、"1.0.0-RC1"
+//   - 稳定性标识: "1.0.0-beta"、"1.0.0-RC1"、"^2.0@beta"
+//   - 分支: "dev-main"、"dev-main#abc123"、"2.x-dev"、"1.0.x-dev as 1.0.0"
 //
 // 示例:
 //
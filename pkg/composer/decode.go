@@ -0,0 +1,261 @@
+package composer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/document"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/parser"
+)
+
+// FieldTypeError 表示composer.json中某个字段的类型与预期不符
+//
+// FieldTypeError包装了ErrUnmarshallingJSON，可以使用errors.Is(err, composer.ErrUnmarshallingJSON)判断。
+//
+// 示例:
+//
+//	_, err := composer.ParseString(`{"require": ["a/b"]}`)
+//	var typeErr *composer.FieldTypeError
+//	if errors.As(err, &typeErr) {
+//		fmt.Println(typeErr.Pointer, typeErr.Expected, typeErr.Actual) // /require object array
+//	}
+type FieldTypeError struct {
+	// Pointer 出错字段的JSON指针，如"/require"、"/authors/0/name"
+	Pointer string
+
+	// Expected 预期的JSON类型，如"object"、"array"、"string"、"integer"
+	Expected string
+
+	// Actual 实际的JSON类型
+	Actual string
+}
+
+// Error 实现error接口
+func (e *FieldTypeError) Error() string {
+	return fmt.Sprintf("invalid type for field %s: expected %s, got %s", e.Pointer, e.Expected, e.Actual)
+}
+
+// Unwrap 返回ErrUnmarshallingJSON，使errors.Is可以匹配
+func (e *FieldTypeError) Unwrap() error {
+	return ErrUnmarshallingJSON
+}
+
+// FieldTypeErrors 是按文档顺序排列的多个字段类型错误
+//
+// 使用ParseOptions.CollectAllErrors解析时返回，errors.As可以从中取出第一个*FieldTypeError。
+type FieldTypeErrors []*FieldTypeError
+
+// Error 实现error接口
+func (e FieldTypeErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%d field type errors: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Unwrap 返回所有的字段类型错误
+func (e FieldTypeErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// ParseOptions 控制composer.json的解析行为
+type ParseOptions struct {
+	// CollectAllErrors 为true时收集所有字段类型错误并以FieldTypeErrors返回，
+	// 为false时在第一个错误处停止并返回*FieldTypeError
+	CollectAllErrors bool
+}
+
+// ParseFileWithOptions 使用指定的选项从文件路径解析composer.json文件
+//
+// 示例:
+//
+//	_, err := composer.ParseFileWithOptions("./composer.json", composer.ParseOptions{CollectAllErrors: true})
+//	var typeErrs composer.FieldTypeErrors
+//	if errors.As(err, &typeErrs) {
+//		for _, e := range typeErrs {
+//			fmt.Printf("%s: 应为%s，实际为%s\n", e.Pointer, e.Expected, e.Actual)
+//		}
+//	}
+func ParseFileWithOptions(filePath string, opts ParseOptions) (*ComposerJSON, error) {
+	data, err := parser.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBytesWithOptions(data, opts)
+}
+
+// ParseWithOptions 使用指定的选项从io.Reader解析composer.json
+func ParseWithOptions(r io.Reader, opts ParseOptions) (*ComposerJSON, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReadingFile, err)
+	}
+
+	return parseBytesWithOptions(data, opts)
+}
+
+// ParseStringWithOptions 使用指定的选项解析composer.json字符串
+func ParseStringWithOptions(jsonStr string, opts ParseOptions) (*ComposerJSON, error) {
+	return parseBytesWithOptions([]byte(jsonStr), opts)
+}
+
+// checkFieldTypes 按ComposerJSON的字段类型检查文档，返回按文档顺序排列的类型错误
+//
+// 参数:
+//   - root: 文档的根节点
+//   - all: 为false时在第一个错误处停止
+func checkFieldTypes(root *document.Node, all bool) FieldTypeErrors {
+	c := &typeChecker{all: all}
+	c.check(root, reflect.TypeOf(composerJSONFields{}), "")
+	return c.errs
+}
+
+// typeChecker 递归比较JSON节点与Go类型
+type typeChecker struct {
+	all  bool
+	errs FieldTypeErrors
+}
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+func (c *typeChecker) check(n *document.Node, t reflect.Type, pointer string) {
+	if !c.all && len(c.errs) > 0 {
+		return
+	}
+	// 与encoding/json一致，null可以赋给任何字段
+	if n.Kind == document.Null || t == rawMessageType {
+		return
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	expected := jsonTypeName(t)
+	ok := true
+	switch t.Kind() {
+	case reflect.Interface:
+		return
+	case reflect.String:
+		ok = n.Kind == document.String
+	case reflect.Bool:
+		ok = n.Kind == document.Bool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		ok = n.Kind == document.Number && isInteger(n.Raw)
+	case reflect.Float32, reflect.Float64:
+		ok = n.Kind == document.Number
+	case reflect.Map:
+		if ok = n.Kind == document.Object; ok {
+			for _, m := range n.Members {
+				c.check(m.Value, t.Elem(), pointer+"/"+parser.EscapePointer(m.Key))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if ok = n.Kind == document.Array; ok {
+			for i, el := range n.Elements {
+				c.check(el, t.Elem(), pointer+"/"+strconv.Itoa(i))
+			}
+		}
+	case reflect.Struct:
+		if ok = n.Kind == document.Object; ok {
+			fields := structFields(t)
+			for _, m := range n.Members {
+				if ft, found := fields[m.Key]; found {
+					c.check(m.Value, ft, pointer+"/"+parser.EscapePointer(m.Key))
+				}
+			}
+		}
+	}
+
+	if !ok {
+		c.errs = append(c.errs, &FieldTypeError{Pointer: pointer, Expected: expected, Actual: n.Kind.String()})
+	}
+}
+
+// structFields 返回结构体中JSON键到字段类型的映射
+func structFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		// 与encoding/json一致，重复的键会被忽略
+		if _, dup := fields[name]; dup {
+			fields[name] = reflect.TypeOf((*interface{})(nil)).Elem()
+			continue
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// jsonTypeName 返回Go类型对应的JSON类型名称
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return t.String()
+	}
+}
+
+// isInteger 判断数字字面量是否为整数
+func isInteger(raw []byte) bool {
+	_, err := strconv.ParseInt(string(raw), 10, 64)
+	return err == nil
+}
+
+// conversionError 将encoding/json的类型错误转换为FieldTypeError，其他错误保持原来的格式
+func conversionError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		return fmt.Errorf("error converting to ComposerJSON: %v", err)
+	}
+
+	var pointer string
+	if typeErr.Field != "" {
+		for _, part := range strings.Split(typeErr.Field, ".") {
+			pointer += "/" + parser.EscapePointer(part)
+		}
+	}
+
+	actual := typeErr.Value
+	switch {
+	case actual == "bool":
+		actual = "boolean"
+	case strings.HasPrefix(actual, "number"):
+		actual = "number"
+	}
+	return &FieldTypeError{Pointer: pointer, Expected: jsonTypeName(typeErr.Type), Actual: actual}
+}
@@ -0,0 +1,124 @@
+package composer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFieldTypeError(t *testing.T) {
+	tests := []struct {
+		name    string
+		jsonStr string
+		want    FieldTypeError
+	}{
+		{
+			name:    "Require as array",
+			jsonStr: `{"name": "vendor/project", "require": ["a/b"]}`,
+			want:    FieldTypeError{Pointer: "/require", Expected: "object", Actual: "array"},
+		},
+		{
+			name:    "Authors as object",
+			jsonStr: `{"authors": {}}`,
+			want:    FieldTypeError{Pointer: "/authors", Expected: "array", Actual: "object"},
+		},
+		{
+			name:    "Constraint as number",
+			jsonStr: `{"require": {"php": 8}}`,
+			want:    FieldTypeError{Pointer: "/require/php", Expected: "string", Actual: "number"},
+		},
+		{
+			name:    "Nested author field",
+			jsonStr: `{"authors": [{"name": "A"}, {"name": true}]}`,
+			want:    FieldTypeError{Pointer: "/authors/1/name", Expected: "string", Actual: "boolean"},
+		},
+		{
+			name:    "Escaped pointer token",
+			jsonStr: `{"config": {"platform": {"ext/odd": 1}}}`,
+			want:    FieldTypeError{Pointer: "/config/platform/ext~1odd", Expected: "string", Actual: "number"},
+		},
+		{
+			name:    "Integer config option",
+			jsonStr: `{"config": {"process-timeout": "600"}}`,
+			want:    FieldTypeError{Pointer: "/config/process-timeout", Expected: "integer", Actual: "string"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseString(tt.jsonStr)
+			if !errors.Is(err, ErrUnmarshallingJSON) {
+				t.Fatalf("ParseString() error = %v, want ErrUnmarshallingJSON", err)
+			}
+
+			var typeErr *FieldTypeError
+			if !errors.As(err, &typeErr) {
+				t.Fatalf("ParseString() error = %T, want *FieldTypeError", err)
+			}
+			if *typeErr != tt.want {
+				t.Errorf("FieldTypeError = %+v, want %+v", *typeErr, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOptions_CollectAllErrors(t *testing.T) {
+	jsonStr := `{
+    "name": ["vendor/project"],
+    "require": ["a/b"],
+    "authors": [{"name": 1, "email": 2}],
+    "prefer-stable": "yes",
+    "unknown": 123,
+    "license": 5
+}`
+
+	// 默认在第一个错误处停止
+	_, err := ParseString(jsonStr)
+	var typeErr *FieldTypeError
+	if !errors.As(err, &typeErr) || typeErr.Pointer != "/name" {
+		t.Fatalf("ParseString() error = %v, want first error at /name", err)
+	}
+
+	_, err = ParseStringWithOptions(jsonStr, ParseOptions{CollectAllErrors: true})
+	var typeErrs FieldTypeErrors
+	if !errors.As(err, &typeErrs) {
+		t.Fatalf("ParseStringWithOptions() error = %T, want FieldTypeErrors", err)
+	}
+
+	var pointers []string
+	for _, e := range typeErrs {
+		pointers = append(pointers, e.Pointer)
+	}
+	want := []string{"/name", "/require", "/authors/0/name", "/authors/0/email", "/prefer-stable"}
+	if !reflect.DeepEqual(pointers, want) {
+		t.Errorf("pointers = %v, want %v", pointers, want)
+	}
+
+	if !errors.Is(err, ErrUnmarshallingJSON) {
+		t.Errorf("FieldTypeErrors should match ErrUnmarshallingJSON")
+	}
+	if !errors.As(err, &typeErr) || typeErr.Pointer != "/name" {
+		t.Errorf("errors.As() should return the first FieldTypeError, got %v", typeErr)
+	}
+
+	// 没有错误时正常解析
+	composer, err := ParseStringWithOptions(`{"name": "vendor/project"}`, ParseOptions{CollectAllErrors: true})
+	if err != nil || composer.Name != "vendor/project" {
+		t.Errorf("ParseStringWithOptions() = %v, %v", composer, err)
+	}
+}
+
+func TestConvertToComposerJSON_FieldTypeError(t *testing.T) {
+	_, err := convertToComposerJSON(map[string]interface{}{
+		"authors": map[string]interface{}{},
+	})
+
+	var typeErr *FieldTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("convertToComposerJSON() error = %v, want *FieldTypeError", err)
+	}
+	want := FieldTypeError{Pointer: "/authors", Expected: "array", Actual: "object"}
+	if *typeErr != want {
+		t.Errorf("FieldTypeError = %+v, want %+v", *typeErr, want)
+	}
+}
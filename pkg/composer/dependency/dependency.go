@@ -1,7 +1,7 @@
 // Package dependency 提供与PHP Composer依赖项相关的功能
 //
 // 本包处理Composer依赖项的各种操作，包括：
-// - 依赖项格式验证
+// - 依赖项格式验证，包括平台包（如"php"、"ext-intl"）
 // - 检查依赖项是否存在
 // - 添加和删除依赖项
 // - 合并依赖映射
@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/constraint"
 )
 
 // GetPackageNameParts 将包名分割为供应商和项目部分
@@ -98,6 +100,105 @@ func ValidatePackageName(packageName string) error {
 	return nil
 }
 
+// platformPackageRegex 匹配平台包名，与Composer的PlatformRepository::PLATFORM_PACKAGE_REGEX一致
+var platformPackageRegex = regexp.MustCompile(`(?i)^(?:php(?:-64bit|-ipv6|-zts|-debug)?|hhvm|(?:ext|lib)-[a-z0-9](?:[_.-]?[a-z0-9]+)*|composer(?:-(?:plugin|runtime)-api)?)$`)
+
+// IsPlatformPackage 判断包名是否为平台包
+//
+// 平台包由PHP运行环境提供，不会被安装到vendor目录，也不会出现在composer.lock的packages中，包括：
+//   - php、php-64bit、php-ipv6、php-zts、php-debug和hhvm
+//   - PHP扩展，如"ext-json"、"ext-intl"
+//   - 系统库，如"lib-icu"、"lib-openssl"
+//   - composer、composer-plugin-api和composer-runtime-api
+//
+// 示例:
+//
+//	dependency.IsPlatformPackage("ext-intl")        // true
+//	dependency.IsPlatformPackage("symfony/console") // false
+func IsPlatformPackage(packageName string) bool {
+	return platformPackageRegex.MatchString(packageName)
+}
+
+// ValidatePlatformPackageName 检查名称是否为平台包名
+//
+// 参数:
+//   - packageName: 要验证的包名
+//
+// 返回:
+//   - error: 如果不是平台包名则返回错误，是则返回nil
+//
+// 示例:
+//
+//	err := dependency.ValidatePlatformPackageName("ext-intl") // nil
+//	err = dependency.ValidatePlatformPackageName("symfony/console")
+//	fmt.Println(err)
+//	// 输出: 'symfony/console'不是平台包名，平台包如'php'、'ext-json'、'lib-icu'
+func ValidatePlatformPackageName(packageName string) error {
+	if packageName == "" {
+		return fmt.Errorf("包名不能为空")
+	}
+	if !IsPlatformPackage(packageName) {
+		return fmt.Errorf("'%s'不是平台包名，平台包如'php'、'ext-json'、'lib-icu'", packageName)
+	}
+	return nil
+}
+
+// ValidateRequirementName 检查名称是否可以出现在require、require-dev等依赖中
+//
+// 与ValidatePackageName不同，依赖中除了"vendor/project"格式的包，还可以是平台包，如"php"、"ext-intl"、"lib-icu"、"composer-plugin-api"。
+//
+// 参数:
+//   - packageName: 要验证的包名
+//
+// 返回:
+//   - error: 如果既不是平台包、也不是有效的包名则返回错误，有效则返回nil
+//
+// 示例:
+//
+//	dependency.ValidateRequirementName("php")             // nil
+//	dependency.ValidateRequirementName("symfony/console") // nil
+//	dependency.ValidateRequirementName("console")         // 包名必须符合'vendor/project'格式
+func ValidateRequirementName(packageName string) error {
+	if IsPlatformPackage(packageName) {
+		return nil
+	}
+	return ValidatePackageName(packageName)
+}
+
+// SplitRequirements 将依赖映射拆分为平台包依赖和普通包依赖
+//
+// 参数:
+//   - require: 依赖映射，key为包名，value为版本约束
+//
+// 返回:
+//   - map[string]string: 平台包依赖，如"php"、"ext-json"
+//   - map[string]string: 普通包依赖，如"symfony/console"
+//
+// 注意:
+//   - 返回的是新映射，不会修改输入参数；没有对应依赖时返回空映射而不是nil
+//
+// 示例:
+//
+//	platform, packages := dependency.SplitRequirements(map[string]string{
+//		"php":             ">=8.1",
+//		"ext-intl":        "*",
+//		"symfony/console": "^6.0",
+//	})
+//	fmt.Println(platform) // map[ext-intl:* php:>=8.1]
+//	fmt.Println(packages) // map[symfony/console:^6.0]
+func SplitRequirements(require map[string]string) (map[string]string, map[string]string) {
+	platform := make(map[string]string)
+	packages := make(map[string]string)
+	for name, c := range require {
+		if IsPlatformPackage(name) {
+			platform[name] = c
+		} else {
+			packages[name] = c
+		}
+	}
+	return platform, packages
+}
+
 // DependencyExists 检查依赖项是否存在于require部分
 //
 // 参数:
@@ -137,13 +238,14 @@ func DependencyExists(require map[string]string, packageName string) bool {
 //
 // 参数:
 //   - require: 要修改的依赖映射
-//   - packageName: 要添加的包名，格式为"vendor/project"
+//   - packageName: 要添加的包名，格式为"vendor/project"，或平台包名，如"php"、"ext-intl"
 //   - version: 依赖版本约束，如"^5.4"、">=7.4"
 //
 // 返回:
-//   - error: 如果包名格式无效则返回错误，成功则返回nil
+//   - error: 如果包名格式或版本约束无效则返回错误，成功则返回nil
 //
 // 注意:
+//   - 版本约束按Composer的完整语法检查，如"^7.4 || ^8.0"、"1.0.*"、"dev-main#abc123"
 //   - 如果包已存在，将更新其版本约束
 //   - require映射会被直接修改，无需重新赋值
 //
@@ -173,9 +275,12 @@ func DependencyExists(require map[string]string, packageName string) bool {
 //	fmt.Println(require)
 //	// 输出: map[php:>=8.0 symfony/console:^5.4]
 func AddDependency(require map[string]string, packageName, version string) error {
-	if err := ValidatePackageName(packageName); err != nil {
+	if err := ValidateRequirementName(packageName); err != nil {
 		return err
 	}
+	if _, err := constraint.Parse(version); err != nil {
+		return fmt.Errorf("包'%s'的版本约束无效: %w", packageName, err)
+	}
 
 	require[packageName] = version
 	return nil
@@ -1,6 +1,7 @@
 package dependency
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -240,6 +241,30 @@ func TestAddDependency(t *testing.T) {
 			wantErr:     true,
 			wantRequire: map[string]string{},
 		},
+		{
+			name:        "Composite constraint",
+			require:     map[string]string{},
+			packageName: "vendor/package",
+			version:     "^7.4 || ^8.0",
+			wantErr:     false,
+			wantRequire: map[string]string{"vendor/package": "^7.4 || ^8.0"},
+		},
+		{
+			name:        "Platform package",
+			require:     map[string]string{},
+			packageName: "ext-intl",
+			version:     "*",
+			wantErr:     false,
+			wantRequire: map[string]string{"ext-intl": "*"},
+		},
+		{
+			name:        "Invalid constraint",
+			require:     map[string]string{},
+			packageName: "vendor/package",
+			version:     "~>1.0",
+			wantErr:     true,
+			wantRequire: map[string]string{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -398,6 +423,91 @@ func TestMergeDependencies(t *testing.T) {
 	}
 }
 
+func TestIsPlatformPackage(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"php", true},
+		{"php-64bit", true},
+		{"hhvm", true},
+		{"ext-json", true},
+		{"ext-pdo_mysql", true},
+		{"EXT-Intl", true},
+		{"lib-icu", true},
+		{"composer", true},
+		{"composer-plugin-api", true},
+		{"composer-runtime-api", true},
+		{"symfony/console", false},
+		{"ext-", false},
+		{"phpunit", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPlatformPackage(tt.name); got != tt.want {
+				t.Errorf("IsPlatformPackage(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRequirementName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"php", false},
+		{"ext-intl", false},
+		{"lib-icu", false},
+		{"composer-plugin-api", false},
+		{"symfony/console", false},
+		{"console", true},
+		{"Symfony/Console", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateRequirementName(tt.name); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRequirementName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePlatformPackageName(t *testing.T) {
+	if err := ValidatePlatformPackageName("ext-json"); err != nil {
+		t.Errorf("ValidatePlatformPackageName(ext-json) error = %v", err)
+	}
+	if err := ValidatePlatformPackageName("symfony/console"); err == nil || !contains(err.Error(), "不是平台包名") {
+		t.Errorf("ValidatePlatformPackageName(symfony/console) error = %v, want 不是平台包名", err)
+	}
+	if err := ValidatePlatformPackageName(""); err == nil {
+		t.Error("ValidatePlatformPackageName(\"\") error = nil, want error")
+	}
+}
+
+func TestSplitRequirements(t *testing.T) {
+	platform, packages := SplitRequirements(map[string]string{
+		"php":             ">=8.1",
+		"ext-intl":        "*",
+		"symfony/console": "^6.0",
+	})
+	if !reflect.DeepEqual(platform, map[string]string{"php": ">=8.1", "ext-intl": "*"}) {
+		t.Errorf("SplitRequirements() platform = %v", platform)
+	}
+	if !reflect.DeepEqual(packages, map[string]string{"symfony/console": "^6.0"}) {
+		t.Errorf("SplitRequirements() packages = %v", packages)
+	}
+
+	platform, packages = SplitRequirements(nil)
+	if platform == nil || packages == nil || len(platform)+len(packages) != 0 {
+		t.Errorf("SplitRequirements(nil) = %v, %v, want empty maps", platform, packages)
+	}
+}
+
 // 辅助函数，检查字符串是否包含指定子串
 func contains(s, substring string) bool {
 	return strings.Contains(s, substring)
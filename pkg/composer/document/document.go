@@ -0,0 +1,446 @@
+// Package document 提供保留格式的composer.json文档模型
+//
+// 与parser包返回的map不同，本包解析出的文档会记住：
+// - 对象中键的原始顺序
+// - 缩进风格（空格或制表符及其宽度）
+// - 换行符（LF或CRLF）
+// - 文件末尾是否有换行
+// - 每个值在源文本中的原始字节
+//
+// 保存时通过Merge把修改后的值合并回原始文档，未修改的部分按原样输出，
+// 因此只修改一个依赖时，保存后的文件只会产生一行差异。
+package document
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// ErrSyntax 表示文档不是合法的JSON
+var ErrSyntax = errors.New("invalid JSON document")
+
+// Kind 表示JSON值的类型
+type Kind int
+
+// JSON值类型定义
+const (
+	Null Kind = iota
+	Bool
+	Number
+	String
+	Array
+	Object
+)
+
+// String 返回类型的名称
+func (k Kind) String() string {
+	switch k {
+	case Null:
+		return "null"
+	case Bool:
+		return "boolean"
+	case Number:
+		return "number"
+	case String:
+		return "string"
+	case Array:
+		return "array"
+	case Object:
+		return "object"
+	default:
+		return "unknown"
+	}
+}
+
+// Node 表示文档中的一个JSON值
+type Node struct {
+	// Kind 值的类型
+	Kind Kind
+
+	// Raw 值在源文本中的原始字节（对象和数组包含括号及内部的所有空白）
+	Raw []byte
+
+	// Start 和 End 是值在源文本中的字节偏移，范围为[Start, End)
+	Start, End int
+
+	// Members 对象的成员，按源文本中的顺序排列
+	Members []*Member
+
+	// Elements 数组的元素，按源文本中的顺序排列
+	Elements []*Node
+}
+
+// Member 表示对象中的一个键值对
+type Member struct {
+	// Key 解码后的键名
+	Key string
+
+	// RawKey 键在源文本中的原始字节（包含引号）
+	RawKey []byte
+
+	// KeyStart 键在源文本中的起始字节偏移
+	KeyStart int
+
+	// Value 成员的值
+	Value *Node
+}
+
+// Get 返回对象中指定键的值，如果节点不是对象或键不存在则返回nil
+func (n *Node) Get(key string) *Node {
+	if m := n.Member(key); m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// Member 返回对象中指定键的成员，如果节点不是对象或键不存在则返回nil
+func (n *Node) Member(key string) *Member {
+	if n == nil || n.Kind != Object {
+		return nil
+	}
+	for _, m := range n.Members {
+		if m.Key == key {
+			return m
+		}
+	}
+	return nil
+}
+
+// StringValue 返回字符串节点解码后的值，节点不是字符串时第二个返回值为false
+func (n *Node) StringValue() (string, bool) {
+	if n == nil || n.Kind != String {
+		return "", false
+	}
+	s, err := decodeString(n.Raw)
+	if err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+// MarshalJSON 实现json.Marshaler接口，按原始字节输出节点
+func (n *Node) MarshalJSON() ([]byte, error) {
+	return n.Raw, nil
+}
+
+// Keys 返回对象的键，按源文本中的顺序排列
+func (n *Node) Keys() []string {
+	if n == nil || n.Kind != Object {
+		return nil
+	}
+	keys := make([]string, 0, len(n.Members))
+	for _, m := range n.Members {
+		keys = append(keys, m.Key)
+	}
+	return keys
+}
+
+// Format 描述文档的排版风格
+type Format struct {
+	// Indent 每一级缩进使用的字符串，如"    "或"\t"
+	Indent string
+
+	// Newline 换行符，"\n"或"\r\n"
+	Newline string
+
+	// FinalNewline 文件末尾是否以换行结束
+	FinalNewline bool
+}
+
+// DefaultFormat 返回Composer自身使用的排版风格：4个空格缩进、LF换行、末尾换行
+func DefaultFormat() Format {
+	return Format{
+		Indent:       "    ",
+		Newline:      "\n",
+		FinalNewline: true,
+	}
+}
+
+// Document 表示一个保留了原始格式的JSON文档
+type Document struct {
+	// Root 文档的根值
+	Root *Node
+
+	// Format 从源文本中检测到的排版风格
+	Format Format
+
+	// Source 文档的源文本
+	Source []byte
+}
+
+// Parse 解析JSON文本为保留格式的文档
+//
+// 参数:
+//   - data: JSON源文本
+//
+// 返回:
+//   - *Document: 解析后的文档
+//   - error: 如果文本不是合法的JSON，返回包装了ErrSyntax的错误
+//
+// 示例:
+//
+//	doc, err := document.Parse(data)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(doc.Root.Keys()) // 按文件中的顺序输出顶层键
+func Parse(data []byte) (*Document, error) {
+	s := &scanner{data: data}
+	s.skipSpace()
+	root, err := s.value()
+	if err != nil {
+		return nil, err
+	}
+	s.skipSpace()
+	if s.pos != len(data) {
+		return nil, s.errorf("unexpected data after top-level value")
+	}
+
+	return &Document{
+		Root:   root,
+		Format: DetectFormat(data),
+		Source: data,
+	}, nil
+}
+
+// DetectFormat 从JSON源文本中检测排版风格
+//
+// 缩进取第一处换行后的前导空白，无法检测时使用DefaultFormat的值。
+func DetectFormat(data []byte) Format {
+	f := DefaultFormat()
+
+	if bytes.Contains(data, []byte("\r\n")) {
+		f.Newline = "\r\n"
+	}
+	f.FinalNewline = bytes.HasSuffix(data, []byte("\n"))
+
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		rest := data[i+1:]
+		j := 0
+		for j < len(rest) && (rest[j] == ' ' || rest[j] == '\t') {
+			j++
+		}
+		if j > 0 && j < len(rest) && rest[j] != '}' && rest[j] != ']' {
+			f.Indent = string(rest[:j])
+		}
+	}
+
+	return f
+}
+
+// scanner 是记录字节偏移的JSON递归下降解析器
+type scanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *scanner) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrSyntax, fmt.Sprintf(format, args...), s.pos)
+}
+
+func (s *scanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *scanner) value() (*Node, error) {
+	if s.pos >= len(s.data) {
+		return nil, s.errorf("unexpected end of input")
+	}
+
+	start := s.pos
+	var n *Node
+	var err error
+
+	switch c := s.data[s.pos]; {
+	case c == '{':
+		n, err = s.object()
+	case c == '[':
+		n, err = s.array()
+	case c == '"':
+		if _, err = s.str(); err == nil {
+			n = &Node{Kind: String}
+		}
+	case c == '-' || (c >= '0' && c <= '9'):
+		if err = s.number(); err == nil {
+			n = &Node{Kind: Number}
+		}
+	case s.literal("true") || s.literal("false"):
+		n = &Node{Kind: Bool}
+	case s.literal("null"):
+		n = &Node{Kind: Null}
+	default:
+		return nil, s.errorf("invalid character %q", c)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	n.Start = start
+	n.End = s.pos
+	n.Raw = s.data[start:s.pos]
+	return n, nil
+}
+
+func (s *scanner) literal(lit string) bool {
+	if bytes.HasPrefix(s.data[s.pos:], []byte(lit)) {
+		s.pos += len(lit)
+		return true
+	}
+	return false
+}
+
+func (s *scanner) object() (*Node, error) {
+	n := &Node{Kind: Object}
+	s.pos++ // {
+	s.skipSpace()
+	if s.pos < len(s.data) && s.data[s.pos] == '}' {
+		s.pos++
+		return n, nil
+	}
+
+	for {
+		s.skipSpace()
+		if s.pos >= len(s.data) || s.data[s.pos] != '"' {
+			return nil, s.errorf("expected object key")
+		}
+		keyStart := s.pos
+		key, err := s.str()
+		if err != nil {
+			return nil, err
+		}
+		rawKey := s.data[keyStart:s.pos]
+
+		s.skipSpace()
+		if s.pos >= len(s.data) || s.data[s.pos] != ':' {
+			return nil, s.errorf("expected ':' after object key")
+		}
+		s.pos++
+		s.skipSpace()
+
+		v, err := s.value()
+		if err != nil {
+			return nil, err
+		}
+		n.Members = append(n.Members, &Member{Key: key, RawKey: rawKey, KeyStart: keyStart, Value: v})
+
+		s.skipSpace()
+		if s.pos >= len(s.data) {
+			return nil, s.errorf("unexpected end of input in object")
+		}
+		switch s.data[s.pos] {
+		case ',':
+			s.pos++
+		case '}':
+			s.pos++
+			return n, nil
+		default:
+			return nil, s.errorf("expected ',' or '}' in object")
+		}
+	}
+}
+
+func (s *scanner) array() (*Node, error) {
+	n := &Node{Kind: Array}
+	s.pos++ // [
+	s.skipSpace()
+	if s.pos < len(s.data) && s.data[s.pos] == ']' {
+		s.pos++
+		return n, nil
+	}
+
+	for {
+		s.skipSpace()
+		v, err := s.value()
+		if err != nil {
+			return nil, err
+		}
+		n.Elements = append(n.Elements, v)
+
+		s.skipSpace()
+		if s.pos >= len(s.data) {
+			return nil, s.errorf("unexpected end of input in array")
+		}
+		switch s.data[s.pos] {
+		case ',':
+			s.pos++
+		case ']':
+			s.pos++
+			return n, nil
+		default:
+			return nil, s.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+// str 扫描一个字符串字面量并返回解码后的值
+func (s *scanner) str() (string, error) {
+	start := s.pos
+	s.pos++ // "
+	for s.pos < len(s.data) {
+		switch c := s.data[s.pos]; {
+		case c == '"':
+			s.pos++
+			value, err := decodeString(s.data[start:s.pos])
+			if err != nil {
+				s.pos = start
+				return "", s.errorf("invalid string literal")
+			}
+			return value, nil
+		case c == '\\':
+			s.pos += 2
+		case c < 0x20:
+			return "", s.errorf("control character in string literal")
+		default:
+			s.pos++
+		}
+	}
+	return "", s.errorf("unterminated string literal")
+}
+
+func (s *scanner) number() error {
+	start := s.pos
+	if s.data[s.pos] == '-' {
+		s.pos++
+	}
+	digits := func() int {
+		n := 0
+		for s.pos < len(s.data) && s.data[s.pos] >= '0' && s.data[s.pos] <= '9' {
+			s.pos++
+			n++
+		}
+		return n
+	}
+
+	if s.pos < len(s.data) && s.data[s.pos] == '0' {
+		s.pos++
+	} else if digits() == 0 {
+		return s.errorf("invalid number")
+	}
+	if s.pos < len(s.data) && s.data[s.pos] == '.' {
+		s.pos++
+		if digits() == 0 {
+			return s.errorf("invalid number")
+		}
+	}
+	if s.pos < len(s.data) && (s.data[s.pos] == 'e' || s.data[s.pos] == 'E') {
+		s.pos++
+		if s.pos < len(s.data) && (s.data[s.pos] == '+' || s.data[s.pos] == '-') {
+			s.pos++
+		}
+		if digits() == 0 {
+			return s.errorf("invalid number")
+		}
+	}
+	if s.pos == start {
+		return s.errorf("invalid number")
+	}
+	return nil
+}
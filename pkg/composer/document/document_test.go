@@ -0,0 +1,119 @@
+package document
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	src := `{
+    "name": "vendor/project",
+    "require": {
+        "php": "^8.1",
+        "ext-json": "*"
+    },
+    "keywords": ["a", "b"],
+    "prefer-stable": true,
+    "version": 1.0,
+    "extra": null
+}
+`
+	doc, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatalf("Parse() returned unexpected error: %v", err)
+	}
+
+	wantKeys := []string{"name", "require", "keywords", "prefer-stable", "version", "extra"}
+	if got := doc.Root.Keys(); !reflect.DeepEqual(got, wantKeys) {
+		t.Errorf("Keys() = %v, want %v", got, wantKeys)
+	}
+
+	require := doc.Root.Get("require")
+	if require == nil || require.Kind != Object {
+		t.Fatalf("require should be an object, got %v", require)
+	}
+	if got := require.Keys(); !reflect.DeepEqual(got, []string{"php", "ext-json"}) {
+		t.Errorf("require keys = %v, want [php ext-json]", got)
+	}
+	if got := string(require.Get("php").Raw); got != `"^8.1"` {
+		t.Errorf("php raw = %s, want \"^8.1\"", got)
+	}
+
+	if n := doc.Root.Get("keywords"); n == nil || len(n.Elements) != 2 {
+		t.Errorf("keywords should have 2 elements, got %v", n)
+	}
+
+	kinds := map[string]Kind{"prefer-stable": Bool, "version": Number, "extra": Null, "name": String}
+	for key, want := range kinds {
+		if got := doc.Root.Get(key).Kind; got != want {
+			t.Errorf("%s kind = %v, want %v", key, got, want)
+		}
+	}
+
+	name := doc.Root.Get("name")
+	if got := src[name.Start:name.End]; got != `"vendor/project"` {
+		t.Errorf("name span = %s, want \"vendor/project\"", got)
+	}
+
+	if doc.Root.Get("missing") != nil {
+		t.Errorf("Get() should return nil for missing keys")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		``,
+		`{`,
+		`{"a": 1,}`,
+		`{"a" 1}`,
+		`{"a": tru}`,
+		`[1, 2`,
+		`{"a": "b"} x`,
+		`{"a": -}`,
+		`{"a": "\u0G00"}`,
+	}
+
+	for _, src := range tests {
+		if _, err := Parse([]byte(src)); !errors.Is(err, ErrSyntax) {
+			t.Errorf("Parse(%q) error = %v, want ErrSyntax", src, err)
+		}
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want Format
+	}{
+		{
+			name: "Composer default",
+			src:  "{\n    \"name\": \"a/b\"\n}\n",
+			want: Format{Indent: "    ", Newline: "\n", FinalNewline: true},
+		},
+		{
+			name: "Two spaces without final newline",
+			src:  "{\n  \"name\": \"a/b\"\n}",
+			want: Format{Indent: "  ", Newline: "\n", FinalNewline: false},
+		},
+		{
+			name: "Tabs with CRLF",
+			src:  "{\r\n\t\"name\": \"a/b\"\r\n}\r\n",
+			want: Format{Indent: "\t", Newline: "\r\n", FinalNewline: true},
+		},
+		{
+			name: "Single line falls back to default indent",
+			src:  `{"name": "a/b"}`,
+			want: Format{Indent: "    ", Newline: "\n", FinalNewline: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat([]byte(tt.src)); got != tt.want {
+				t.Errorf("DetectFormat() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
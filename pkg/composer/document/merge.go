@@ -0,0 +1,300 @@
+package document
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// FromValue 将任意可被encoding/json序列化的值转换为文档节点
+//
+// 序列化时不转义HTML字符（<、>、&），与Composer的JSON_UNESCAPED_SLASHES风格保持一致。
+// 结构体字段保持声明顺序，map的键按字母顺序排列。
+func FromValue(v interface{}) (*Node, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("error marshalling to JSON: %v", err)
+	}
+
+	doc, err := Parse(bytes.TrimSpace(buf.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	return doc.Root, nil
+}
+
+// Equal 判断两个节点在JSON语义上是否相等
+//
+// 对象比较时忽略键的顺序，字符串比较解码后的值，数字比较数值。
+func Equal(a, b *Node) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Kind != b.Kind {
+		return false
+	}
+
+	switch a.Kind {
+	case Object:
+		if len(a.Members) != len(b.Members) {
+			return false
+		}
+		for _, m := range a.Members {
+			if !Equal(m.Value, b.Get(m.Key)) {
+				return false
+			}
+		}
+		return true
+	case Array:
+		if len(a.Elements) != len(b.Elements) {
+			return false
+		}
+		for i := range a.Elements {
+			if !Equal(a.Elements[i], b.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case String:
+		as, errA := decodeString(a.Raw)
+		bs, errB := decodeString(b.Raw)
+		return errA == nil && errB == nil && as == bs
+	case Number:
+		if bytes.Equal(a.Raw, b.Raw) {
+			return true
+		}
+		af, errA := strconv.ParseFloat(string(a.Raw), 64)
+		bf, errB := strconv.ParseFloat(string(b.Raw), 64)
+		return errA == nil && errB == nil && af == bf
+	default:
+		return bytes.Equal(a.Raw, b.Raw)
+	}
+}
+
+// Merge 将修改后的值合并回原始文档，返回新的源文本
+//
+// 参数:
+//   - base: 原始文档在修改之前经过数据模型转换后的值，用于区分"被删除的键"和
+//     "数据模型无法表示的键"；为nil时认为原始文档中的所有键都可以被表示
+//   - current: 修改之后的值
+//
+// 返回:
+//   - []byte: 合并后的源文本
+//
+// 合并规则:
+//   - 与原始值语义相等的值按原始字节输出
+//   - 对象保留原有键的顺序，新增的键追加在末尾
+//   - base中存在而current中不存在的键被删除；base中也不存在的键原样保留
+//   - current中新增的、但与base中的值相等的键被忽略（例如结构体中始终输出的空对象）
+//   - 新生成的部分使用文档的缩进和换行风格
+//
+// 示例:
+//
+//	doc, _ := document.Parse(original)
+//	base, _ := document.FromValue(model)
+//	model.Require["monolog/monolog"] = "^3.0"
+//	current, _ := document.FromValue(model)
+//	updated := doc.Merge(base, current)
+func (d *Document) Merge(base, current *Node) []byte {
+	e := &encoder{format: d.Format}
+	e.buf.Write(d.Source[:d.Root.Start])
+	e.merge(d.Root, base, current, 0)
+	e.buf.Write(d.Source[d.Root.End:])
+	return e.buf.Bytes()
+}
+
+// Render 使用指定的排版风格输出节点
+//
+// 如果FinalNewline为true，输出以换行结束。
+func Render(n *Node, f Format) []byte {
+	e := &encoder{format: f}
+	e.write(n, 0)
+	if f.FinalNewline {
+		e.buf.WriteString(f.Newline)
+	}
+	return e.buf.Bytes()
+}
+
+// RenderAt 使用指定的排版风格输出位于第depth层的节点，结果不以换行结束
+//
+// 嵌套的对象和数组按depth计算缩进，用于把新值插入到已有文档的指定位置。
+func RenderAt(n *Node, f Format, depth int) []byte {
+	e := &encoder{format: f}
+	e.write(n, depth)
+	return e.buf.Bytes()
+}
+
+// encoder 负责按文档风格输出节点
+type encoder struct {
+	buf    bytes.Buffer
+	format Format
+}
+
+// piece 表示待输出的一个对象成员或数组元素
+type piece struct {
+	key   []byte
+	write func(depth int)
+}
+
+func (e *encoder) merge(orig, base, cur *Node, depth int) {
+	switch {
+	case Equal(orig, cur), base != nil && Equal(base, cur):
+		e.buf.Write(orig.Raw)
+	case orig.Kind == Object && cur.Kind == Object:
+		e.mergeObject(orig, base, cur, depth)
+	case orig.Kind == Array && cur.Kind == Array:
+		e.mergeArray(orig, base, cur, depth)
+	default:
+		e.write(cur, depth)
+	}
+}
+
+func (e *encoder) mergeObject(orig, base, cur *Node, depth int) {
+	baseKnown := base != nil && base.Kind == Object
+	var pieces []piece
+
+	for _, m := range orig.Members {
+		m := m
+		c := cur.Get(m.Key)
+		if c == nil {
+			// 数据模型无法表示的键保持原样，其余的键视为已删除
+			if baseKnown && base.Get(m.Key) == nil {
+				pieces = append(pieces, piece{key: m.RawKey, write: func(int) { e.buf.Write(m.Value.Raw) }})
+			}
+			continue
+		}
+		var b *Node
+		if baseKnown {
+			b = base.Get(m.Key)
+		}
+		pieces = append(pieces, piece{key: m.RawKey, write: func(d int) { e.merge(m.Value, b, c, d) }})
+	}
+
+	for _, m := range cur.Members {
+		m := m
+		if orig.Member(m.Key) != nil {
+			continue
+		}
+		if baseKnown {
+			if b := base.Get(m.Key); b != nil && Equal(b, m.Value) {
+				continue
+			}
+		}
+		pieces = append(pieces, piece{key: m.RawKey, write: func(d int) { e.write(m.Value, d) }})
+	}
+
+	e.container('{', '}', pieces, isInline(orig), depth)
+}
+
+func (e *encoder) mergeArray(orig, base, cur *Node, depth int) {
+	aligned := base != nil && base.Kind == Array && len(base.Elements) == len(orig.Elements)
+	used := make([]bool, len(orig.Elements))
+	var pieces []piece
+
+	for i, c := range cur.Elements {
+		c := c
+		match := -1
+		for j, o := range orig.Elements {
+			if !used[j] && (Equal(o, c) || aligned && Equal(base.Elements[j], c)) {
+				match = j
+				break
+			}
+		}
+
+		switch {
+		case match >= 0:
+			used[match] = true
+			o := orig.Elements[match]
+			pieces = append(pieces, piece{write: func(int) { e.buf.Write(o.Raw) }})
+		case i < len(orig.Elements) && !used[i] && orig.Elements[i].Kind == c.Kind && (c.Kind == Object || c.Kind == Array):
+			used[i] = true
+			o := orig.Elements[i]
+			var b *Node
+			if aligned {
+				b = base.Elements[i]
+			}
+			pieces = append(pieces, piece{write: func(d int) { e.merge(o, b, c, d) }})
+		default:
+			pieces = append(pieces, piece{write: func(d int) { e.write(c, d) }})
+		}
+	}
+
+	e.container('[', ']', pieces, isInline(orig), depth)
+}
+
+// write 输出新生成的节点
+func (e *encoder) write(n *Node, depth int) {
+	switch n.Kind {
+	case Object:
+		pieces := make([]piece, 0, len(n.Members))
+		for _, m := range n.Members {
+			m := m
+			pieces = append(pieces, piece{key: m.RawKey, write: func(d int) { e.write(m.Value, d) }})
+		}
+		e.container('{', '}', pieces, false, depth)
+	case Array:
+		pieces := make([]piece, 0, len(n.Elements))
+		for _, el := range n.Elements {
+			el := el
+			pieces = append(pieces, piece{write: func(d int) { e.write(el, d) }})
+		}
+		e.container('[', ']', pieces, false, depth)
+	default:
+		e.buf.Write(n.Raw)
+	}
+}
+
+// container 输出对象或数组的括号、分隔符和成员
+func (e *encoder) container(open, close byte, pieces []piece, inline bool, depth int) {
+	e.buf.WriteByte(open)
+	if len(pieces) == 0 {
+		e.buf.WriteByte(close)
+		return
+	}
+
+	for i, p := range pieces {
+		if i > 0 {
+			e.buf.WriteByte(',')
+			if inline {
+				e.buf.WriteByte(' ')
+			}
+		}
+		if !inline {
+			e.newline(depth + 1)
+		}
+		if p.key != nil {
+			e.buf.Write(p.key)
+			e.buf.WriteString(": ")
+		}
+		p.write(depth + 1)
+	}
+
+	if !inline {
+		e.newline(depth)
+	}
+	e.buf.WriteByte(close)
+}
+
+func (e *encoder) newline(depth int) {
+	e.buf.WriteString(e.format.Newline)
+	for i := 0; i < depth; i++ {
+		e.buf.WriteString(e.format.Indent)
+	}
+}
+
+// isInline 判断原始的对象或数组是否写在同一行中
+func isInline(n *Node) bool {
+	return len(n.Raw) > 2 && !bytes.ContainsAny(n.Raw, "\r\n")
+}
+
+// decodeString 解码带引号的JSON字符串字面量
+func decodeString(raw []byte) (string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
@@ -0,0 +1,260 @@
+package document
+
+import (
+	"testing"
+)
+
+func mustParse(t *testing.T, src string) *Document {
+	t.Helper()
+	doc, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatalf("Parse() returned unexpected error: %v", err)
+	}
+	return doc
+}
+
+func mustValue(t *testing.T, v interface{}) *Node {
+	t.Helper()
+	n, err := FromValue(v)
+	if err != nil {
+		t.Fatalf("FromValue() returned unexpected error: %v", err)
+	}
+	return n
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{`{"a": 1, "b": 2}`, `{"b": 2, "a": 1}`, true},
+		{`{"a": 1}`, `{"a": 1, "b": 2}`, false},
+		{`[1, 2]`, `[2, 1]`, false},
+		{`1.0`, `1`, true},
+		{`"a\/b"`, `"a/b"`, true},
+		{`"&"`, `"&"`, true},
+		{`true`, `false`, false},
+		{`null`, `null`, true},
+		{`"1"`, `1`, false},
+	}
+
+	for _, tt := range tests {
+		a := mustParse(t, tt.a).Root
+		b := mustParse(t, tt.b).Root
+		if got := Equal(a, b); got != tt.want {
+			t.Errorf("Equal(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	src := `{
+    "name": "vendor/project",
+    "description": "Escaped \/ slashes & <html>",
+    "keywords": ["cli", "tool"],
+    "require": {
+        "php": "^8.1",
+        "symfony/console": "^6.0"
+    },
+    "unknown": {"kept": true}
+}
+`
+	base := map[string]interface{}{
+		"name":        "vendor/project",
+		"description": "Escaped / slashes & <html>",
+		"keywords":    []string{"cli", "tool"},
+		"require": map[string]string{
+			"php":             "^8.1",
+			"symfony/console": "^6.0",
+		},
+		"support": map[string]string{},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(m map[string]interface{})
+		want   string
+	}{
+		{
+			name:   "Unchanged document is byte-identical",
+			modify: func(m map[string]interface{}) {},
+			want:   src,
+		},
+		{
+			name: "Added dependency is appended",
+			modify: func(m map[string]interface{}) {
+				m["require"] = map[string]string{"php": "^8.1", "symfony/console": "^6.0", "monolog/monolog": "^3.0"}
+			},
+			want: `{
+    "name": "vendor/project",
+    "description": "Escaped \/ slashes & <html>",
+    "keywords": ["cli", "tool"],
+    "require": {
+        "php": "^8.1",
+        "symfony/console": "^6.0",
+        "monolog/monolog": "^3.0"
+    },
+    "unknown": {"kept": true}
+}
+`,
+		},
+		{
+			name: "Updated value keeps its position",
+			modify: func(m map[string]interface{}) {
+				m["require"] = map[string]string{"php": "^8.2", "symfony/console": "^6.0"}
+			},
+			want: `{
+    "name": "vendor/project",
+    "description": "Escaped \/ slashes & <html>",
+    "keywords": ["cli", "tool"],
+    "require": {
+        "php": "^8.2",
+        "symfony/console": "^6.0"
+    },
+    "unknown": {"kept": true}
+}
+`,
+		},
+		{
+			name: "Removed keys are dropped, unknown keys are kept",
+			modify: func(m map[string]interface{}) {
+				delete(m, "description")
+				m["require"] = map[string]string{"php": "^8.1"}
+			},
+			want: `{
+    "name": "vendor/project",
+    "keywords": ["cli", "tool"],
+    "require": {
+        "php": "^8.1"
+    },
+    "unknown": {"kept": true}
+}
+`,
+		},
+		{
+			name: "Inline arrays stay inline",
+			modify: func(m map[string]interface{}) {
+				m["keywords"] = []string{"cli", "tool", "composer"}
+			},
+			want: `{
+    "name": "vendor/project",
+    "description": "Escaped \/ slashes & <html>",
+    "keywords": ["cli", "tool", "composer"],
+    "require": {
+        "php": "^8.1",
+        "symfony/console": "^6.0"
+    },
+    "unknown": {"kept": true}
+}
+`,
+		},
+		{
+			name: "New sections use the document indentation",
+			modify: func(m map[string]interface{}) {
+				m["require-dev"] = map[string]string{"phpunit/phpunit": "^10.0"}
+				m["support"] = map[string]string{"issues": "https://example.com/issues"}
+			},
+			want: `{
+    "name": "vendor/project",
+    "description": "Escaped \/ slashes & <html>",
+    "keywords": ["cli", "tool"],
+    "require": {
+        "php": "^8.1",
+        "symfony/console": "^6.0"
+    },
+    "unknown": {"kept": true},
+    "require-dev": {
+        "phpunit/phpunit": "^10.0"
+    },
+    "support": {
+        "issues": "https://example.com/issues"
+    }
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := mustParse(t, src)
+			baseNode := mustValue(t, base)
+
+			current := make(map[string]interface{}, len(base))
+			for k, v := range base {
+				current[k] = v
+			}
+			tt.modify(current)
+
+			got := string(doc.Merge(baseNode, mustValue(t, current)))
+			if got != tt.want {
+				t.Errorf("Merge() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeCRLFAndTabs(t *testing.T) {
+	src := "{\r\n\t\"require\": {}\r\n}"
+	doc := mustParse(t, src)
+
+	current := mustValue(t, map[string]interface{}{
+		"require": map[string]string{"php": ">=7.4"},
+	})
+
+	want := "{\r\n\t\"require\": {\r\n\t\t\"php\": \">=7.4\"\r\n\t}\r\n}"
+	if got := string(doc.Merge(nil, current)); got != want {
+		t.Errorf("Merge() = %q, want %q", got, want)
+	}
+}
+
+func TestMergeArrayOfObjects(t *testing.T) {
+	src := `{
+    "repositories": [
+        {"type": "vcs", "url": "https://example.com/a", "canonical": false},
+        {"type": "path", "url": "../b"}
+    ]
+}`
+	doc := mustParse(t, src)
+
+	base := mustValue(t, map[string]interface{}{
+		"repositories": []map[string]string{
+			{"type": "vcs", "url": "https://example.com/a"},
+			{"type": "path", "url": "../b"},
+		},
+	})
+	current := mustValue(t, map[string]interface{}{
+		"repositories": []map[string]string{
+			{"type": "vcs", "url": "https://example.com/a"},
+			{"type": "path", "url": "../b"},
+			{"type": "composer", "url": "https://repo.example.com"},
+		},
+	})
+
+	want := `{
+    "repositories": [
+        {"type": "vcs", "url": "https://example.com/a", "canonical": false},
+        {"type": "path", "url": "../b"},
+        {
+            "type": "composer",
+            "url": "https://repo.example.com"
+        }
+    ]
+}`
+	if got := string(doc.Merge(base, current)); got != want {
+		t.Errorf("Merge() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	n := mustValue(t, map[string]interface{}{
+		"name":     "vendor/project",
+		"keywords": []string{},
+		"require":  map[string]string{"php": ">=7.4"},
+	})
+
+	want := "{\n  \"keywords\": [],\n  \"name\": \"vendor/project\",\n  \"require\": {\n    \"php\": \">=7.4\"\n  }\n}\n"
+	got := string(Render(n, Format{Indent: "  ", Newline: "\n", FinalNewline: true}))
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
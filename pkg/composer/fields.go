@@ -0,0 +1,200 @@
+package composer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// knownFields 是ComposerJSON结构体字段对应的顶层键
+var knownFields = func() map[string]bool {
+	fields := make(map[string]bool)
+	t := reflect.TypeOf(ComposerJSON{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}()
+
+// composerJSONFields 与ComposerJSON具有相同的字段，但没有自定义的JSON方法，用于避免递归
+type composerJSONFields ComposerJSON
+
+// UnmarshalJSON 实现json.Unmarshaler接口
+//
+// 与Composer一致，顶层键区分大小写：只有与结构体字段完全相同的键解码到结构体中，
+// 其他键（包括只有大小写不同的键，如"Name"）以原始JSON的形式保存到Unknown中。
+func (c *ComposerJSON) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	// encoding/json不区分大小写地匹配字段，只把已知的键交给它解码
+	known := make(map[string]json.RawMessage, len(raw))
+	c.Unknown = nil
+	for key, value := range raw {
+		if knownFields[key] {
+			known[key] = value
+			continue
+		}
+		if c.Unknown == nil {
+			c.Unknown = make(map[string]json.RawMessage)
+		}
+		c.Unknown[key] = append(json.RawMessage(nil), value...)
+	}
+
+	fields, err := json.Marshal(known)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(fields, (*composerJSONFields)(c))
+}
+
+// MarshalJSON 实现json.Marshaler接口
+//
+// Unknown中的键按字母顺序输出在结构体字段之后，值按原样输出。
+func (c ComposerJSON) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(composerJSONFields(c))
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Unknown) == 0 {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, key := range c.UnknownKeys() {
+		if len(data) > 2 || buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		keyData, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyData)
+		buf.WriteByte(':')
+		if err := json.Compact(&buf, c.Unknown[key]); err != nil {
+			return nil, fmt.Errorf("invalid raw JSON for field '%s': %v", key, err)
+		}
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnknownKeys 返回结构体无法表示的顶层键，按字母顺序排列
+//
+// 示例:
+//
+//	composer, _ := composer.ParseFile("./composer.json")
+//	for _, key := range composer.UnknownKeys() {
+//		fmt.Println("未建模的字段:", key) // 如"funding"、"readme"、"_comment"
+//	}
+func (c *ComposerJSON) UnknownKeys() []string {
+	keys := make([]string, 0, len(c.Unknown))
+	for key := range c.Unknown {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetUnknown 返回未建模字段的原始JSON
+//
+// 参数:
+//   - key: 顶层键名，如"funding"
+//
+// 返回:
+//   - json.RawMessage: 字段的原始JSON
+//   - bool: 字段是否存在
+func (c *ComposerJSON) GetUnknown(key string) (json.RawMessage, bool) {
+	value, ok := c.Unknown[key]
+	return value, ok
+}
+
+// DecodeUnknown 将未建模字段的值解码到v中
+//
+// 参数:
+//   - key: 顶层键名
+//   - v: 解码目标，必须是指针
+//
+// 返回:
+//   - error: 字段不存在或解码失败时返回错误
+//
+// 示例:
+//
+//	var funding []struct {
+//		Type string `json:"type"`
+//		URL  string `json:"url"`
+//	}
+//	if err := composer.DecodeUnknown("funding", &funding); err != nil {
+//		log.Fatal(err)
+//	}
+func (c *ComposerJSON) DecodeUnknown(key string, v interface{}) error {
+	value, ok := c.Unknown[key]
+	if !ok {
+		return fmt.Errorf("field '%s' not found", key)
+	}
+	return json.Unmarshal(value, v)
+}
+
+// SetUnknown 设置一个未建模的顶层字段
+//
+// 参数:
+//   - key: 顶层键名，不能是ComposerJSON结构体已有字段的键
+//   - value: 字段的值，可以是json.RawMessage或任意可序列化的值
+//
+// 返回:
+//   - error: 键已被结构体字段使用或值无法序列化时返回错误
+//
+// 示例:
+//
+//	composer.SetUnknown("readme", "README.md")
+//	composer.SetUnknown("funding", []map[string]string{
+//		{"type": "github", "url": "https://github.com/sponsors/vendor"},
+//	})
+func (c *ComposerJSON) SetUnknown(key string, value interface{}) error {
+	if knownFields[key] {
+		return fmt.Errorf("field '%s' is a known composer.json field", key)
+	}
+
+	var raw json.RawMessage
+	switch v := value.(type) {
+	case json.RawMessage:
+		if !json.Valid(v) {
+			return fmt.Errorf("invalid raw JSON for field '%s'", key)
+		}
+		raw = append(raw, v...)
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("error marshalling field '%s': %v", key, err)
+		}
+		raw = data
+	}
+
+	if c.Unknown == nil {
+		c.Unknown = make(map[string]json.RawMessage)
+	}
+	c.Unknown[key] = raw
+	return nil
+}
+
+// RemoveUnknown 删除一个未建模的顶层字段
+//
+// 返回:
+//   - bool: 如果字段存在并被删除返回true，否则返回false
+func (c *ComposerJSON) RemoveUnknown(key string) bool {
+	if _, ok := c.Unknown[key]; !ok {
+		return false
+	}
+	delete(c.Unknown, key)
+	return true
+}
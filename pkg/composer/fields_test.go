@@ -0,0 +1,190 @@
+package composer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnknownFields_Parse(t *testing.T) {
+	jsonStr := `{
+    "name": "vendor/project",
+    "_comment": "managed by the platform team",
+    "readme": "README.md",
+    "funding": [{"type": "github", "url": "https://github.com/sponsors/vendor"}],
+    "time": 12345678901234567890,
+    "php-ext": {"extension-name": "ext-demo"}
+}`
+
+	composer, err := ParseString(jsonStr)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	wantKeys := []string{"_comment", "funding", "php-ext", "readme", "time"}
+	if got := composer.UnknownKeys(); !reflect.DeepEqual(got, wantKeys) {
+		t.Errorf("UnknownKeys() = %v, want %v", got, wantKeys)
+	}
+
+	if _, ok := composer.GetUnknown("name"); ok {
+		t.Errorf("GetUnknown() should not return known fields")
+	}
+
+	// 大整数保持原始文本，不经过float64转换
+	if raw, _ := composer.GetUnknown("time"); string(raw) != "12345678901234567890" {
+		t.Errorf("GetUnknown(time) = %s, want 12345678901234567890", raw)
+	}
+
+	compact, err := composer.ToJSON(false)
+	if err != nil {
+		t.Fatalf("ToJSON() returned unexpected error: %v", err)
+	}
+	if !strings.Contains(compact, `"time":12345678901234567890`) {
+		t.Errorf("ToJSON(false) changed the time field: %s", compact)
+	}
+
+	var funding []map[string]string
+	if err := composer.DecodeUnknown("funding", &funding); err != nil {
+		t.Fatalf("DecodeUnknown() returned unexpected error: %v", err)
+	}
+	if len(funding) != 1 || funding[0]["type"] != "github" {
+		t.Errorf("DecodeUnknown(funding) = %v", funding)
+	}
+
+	if err := composer.DecodeUnknown("missing", &funding); err == nil {
+		t.Errorf("DecodeUnknown() should fail for missing fields")
+	}
+}
+
+func TestUnknownFields_SetAndRemove(t *testing.T) {
+	composer := &ComposerJSON{Name: "vendor/project"}
+
+	if err := composer.SetUnknown("readme", "README.md"); err != nil {
+		t.Fatalf("SetUnknown() returned unexpected error: %v", err)
+	}
+	if err := composer.SetUnknown("_comment", json.RawMessage(`["line 1", "line 2"]`)); err != nil {
+		t.Fatalf("SetUnknown() returned unexpected error: %v", err)
+	}
+	if err := composer.SetUnknown("require", "x"); err == nil {
+		t.Errorf("SetUnknown() should reject known fields")
+	}
+	if err := composer.SetUnknown("bad", json.RawMessage(`{`)); err == nil {
+		t.Errorf("SetUnknown() should reject invalid raw JSON")
+	}
+	if err := composer.SetUnknown("bad", make(chan int)); err == nil {
+		t.Errorf("SetUnknown() should reject values that can't be marshalled")
+	}
+
+	jsonStr, err := composer.ToJSON(false)
+	if err != nil {
+		t.Fatalf("ToJSON() returned unexpected error: %v", err)
+	}
+	if !strings.Contains(jsonStr, `"readme":"README.md"`) || !strings.Contains(jsonStr, `"_comment":["line 1","line 2"]`) {
+		t.Errorf("ToJSON() does not contain unknown fields: %s", jsonStr)
+	}
+
+	if !composer.RemoveUnknown("readme") {
+		t.Errorf("RemoveUnknown() should return true for existing fields")
+	}
+	if composer.RemoveUnknown("readme") {
+		t.Errorf("RemoveUnknown() should return false for missing fields")
+	}
+	if got := composer.UnknownKeys(); !reflect.DeepEqual(got, []string{"_comment"}) {
+		t.Errorf("UnknownKeys() = %v, want [_comment]", got)
+	}
+}
+
+func TestUnknownFields_SaveRoundTrip(t *testing.T) {
+	original := `{
+    "name": "vendor/project",
+    "funding": [
+        {
+            "type": "github",
+            "url": "https://github.com/sponsors/vendor"
+        }
+    ],
+    "require": {
+        "monolog/monolog": "^3.0"
+    },
+    "readme": "README.md"
+}
+`
+
+	filePath := filepath.Join(t.TempDir(), "composer.json")
+	if err := os.WriteFile(filePath, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	composer, err := ParseFile(filePath)
+	if err != nil {
+		t.Fatalf("ParseFile() returned unexpected error: %v", err)
+	}
+
+	composer.Description = "A project with funding"
+	if err := composer.SetUnknown("readme", "docs/README.md"); err != nil {
+		t.Fatalf("SetUnknown() returned unexpected error: %v", err)
+	}
+	if err := composer.Save(filePath, true); err != nil {
+		t.Fatalf("Save() returned unexpected error: %v", err)
+	}
+
+	want := `{
+    "name": "vendor/project",
+    "funding": [
+        {
+            "type": "github",
+            "url": "https://github.com/sponsors/vendor"
+        }
+    ],
+    "require": {
+        "monolog/monolog": "^3.0"
+    },
+    "readme": "docs/README.md",
+    "description": "A project with funding"
+}
+`
+	content, _ := os.ReadFile(filePath)
+	if string(content) != want {
+		t.Errorf("Save() =\n%s\nwant\n%s", content, want)
+	}
+
+	// 不保留格式的输出同样包含未建模字段
+	compact, err := composer.ToJSON(false)
+	if err != nil {
+		t.Fatalf("ToJSON() returned unexpected error: %v", err)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(compact), &data); err != nil {
+		t.Fatalf("ToJSON() returned invalid JSON: %v", err)
+	}
+	if _, ok := data["funding"]; !ok {
+		t.Errorf("ToJSON(false) dropped the funding field: %s", compact)
+	}
+}
+
+func TestUnknownFields_MixedCaseKey(t *testing.T) {
+	composer, err := ParseString(`{"Name": "a/b", "Description": "hello world"}`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	// 与Composer一致，顶层键区分大小写，"Name"不是name字段
+	if composer.Name != "" || composer.Description != "" {
+		t.Errorf("Name = %q, Description = %q, want both empty", composer.Name, composer.Description)
+	}
+	if got, want := composer.UnknownKeys(), []string{"Description", "Name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UnknownKeys() = %v, want %v", got, want)
+	}
+
+	composer.Name = "x/y"
+	compact, err := composer.ToJSON(false)
+	if err != nil {
+		t.Fatalf("ToJSON() returned unexpected error: %v", err)
+	}
+	if strings.Count(compact, `"Name":"a/b"`) != 1 || strings.Count(compact, `"name":"x/y"`) != 1 || strings.Contains(compact, `"name":"a/b"`) {
+		t.Errorf("ToJSON(false) = %s, want Name kept as an unknown key and name set once", compact)
+	}
+}
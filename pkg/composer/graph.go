@@ -0,0 +1,40 @@
+package composer
+
+import (
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/graph"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+)
+
+// DependencyGraph 根据composer.json和composer.lock构建依赖图
+//
+// 参数:
+//   - l: 解析后的composer.lock，为nil时依赖图只包含根包及其直接依赖
+//
+// 返回:
+//   - *graph.Graph: 依赖图，根包为composer.json描述的项目
+//
+// 示例:
+//
+//	project, _ := composer.ParseDir(".")
+//	l, _ := composer.ParseLockDir(".")
+//
+//	g := project.DependencyGraph(l)
+//	for _, n := range g.TransitiveDependencies(g.Root.Name, false) {
+//		fmt.Println(n.Name, n.Version)
+//	}
+func (c *ComposerJSON) DependencyGraph(l *lock.Lock) *graph.Graph {
+	return graph.New(c.rootPackage(), l)
+}
+
+// rootPackage 返回以锁定包形式描述的根包，用于依赖图等需要把根包和锁定包统一处理的场景
+func (c *ComposerJSON) rootPackage() lock.LockedPackage {
+	return lock.LockedPackage{
+		Name:       c.Name,
+		Version:    c.Version,
+		Require:    c.Require,
+		RequireDev: c.RequireDev,
+		Conflict:   c.Conflict,
+		Replace:    c.Replace,
+		Provide:    c.Provide,
+	}
+}
@@ -0,0 +1,331 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExportOptions 控制依赖图导出的内容和分组
+type ExportOptions struct {
+	// HideDev 隐藏根包require-dev的边、锁定在packages-dev中的包，以及只能通过这些边到达的平台包和缺失的包
+	HideDev bool
+
+	// HidePlatform 隐藏平台包，如"php"、"ext-json"
+	HidePlatform bool
+
+	// ClusterVendors 按供应商（包名中"/"之前的部分）把包分组，根包和平台包不参与分组
+	ClusterVendors bool
+}
+
+// view 是按导出选项过滤后的依赖图
+type view struct {
+	nodes    []*Node
+	edges    []Edge
+	clusters []cluster
+}
+
+// cluster 是同一供应商的一组节点
+type cluster struct {
+	vendor string
+	nodes  []*Node
+}
+
+// view 按导出选项过滤节点和边
+func (g *Graph) view(opts ExportOptions) view {
+	var v view
+
+	// 隐藏开发依赖时，平台包和缺失的包只有在生产依赖中可以到达时才保留
+	production := make(map[string]bool)
+	if opts.HideDev {
+		for _, n := range g.TransitiveDependencies(g.Root.Name, false) {
+			production[strings.ToLower(n.Name)] = true
+		}
+	}
+
+	visible := make(map[string]bool)
+	for _, n := range g.Nodes() {
+		if opts.HideDev && (n.Dev || (n.Package == nil && n.Kind != KindRoot && !production[strings.ToLower(n.Name)])) {
+			continue
+		}
+		if opts.HidePlatform && n.Kind == KindPlatform {
+			continue
+		}
+		visible[strings.ToLower(n.Name)] = true
+		v.nodes = append(v.nodes, n)
+	}
+
+	for _, e := range g.Edges() {
+		if opts.HideDev && e.Dev {
+			continue
+		}
+		if visible[strings.ToLower(e.From)] && visible[strings.ToLower(e.To)] {
+			v.edges = append(v.edges, e)
+		}
+	}
+
+	if opts.ClusterVendors {
+		byVendor := make(map[string]*cluster)
+		for _, n := range v.nodes {
+			vendor := n.vendor()
+			if vendor == "" {
+				continue
+			}
+			c, ok := byVendor[vendor]
+			if !ok {
+				c = &cluster{vendor: vendor}
+				byVendor[vendor] = c
+			}
+			c.nodes = append(c.nodes, n)
+		}
+		for _, c := range byVendor {
+			v.clusters = append(v.clusters, *c)
+		}
+		sort.Slice(v.clusters, func(i, j int) bool {
+			return v.clusters[i].vendor < v.clusters[j].vendor
+		})
+	}
+	return v
+}
+
+// vendor 返回参与分组的供应商名，根包、平台包和没有供应商的包返回空字符串
+func (n *Node) vendor() string {
+	if n.Kind == KindRoot || n.Kind == KindPlatform {
+		return ""
+	}
+	if i := strings.Index(n.Name, "/"); i > 0 {
+		return strings.ToLower(n.Name[:i])
+	}
+	return ""
+}
+
+// label 返回节点在图中显示的文字，锁定的包附带版本
+func (n *Node) label() string {
+	if n.Version == "" {
+		return n.Name
+	}
+	return n.Name + " " + n.Version
+}
+
+// DOT 以Graphviz DOT格式导出依赖图
+//
+// 根包加粗显示，平台包为六边形，缺失的包和开发依赖的边为虚线，边上标注版本约束。
+//
+// 示例:
+//
+//	dot := g.DOT(graph.ExportOptions{HideDev: true, ClusterVendors: true})
+//	os.WriteFile("deps.dot", []byte(dot), 0644)
+//	// dot -Tsvg deps.dot -o deps.svg
+func (g *Graph) DOT(opts ExportOptions) string {
+	v := g.view(opts)
+
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("    rankdir=LR;\n")
+	b.WriteString("    node [shape=box];\n")
+
+	clustered := make(map[*Node]bool)
+	for _, c := range v.clusters {
+		fmt.Fprintf(&b, "    subgraph %s {\n", dotQuote("cluster_"+c.vendor))
+		fmt.Fprintf(&b, "        label=%s;\n", dotQuote(c.vendor))
+		for _, n := range c.nodes {
+			b.WriteString("        ")
+			writeDOTNode(&b, n)
+			clustered[n] = true
+		}
+		b.WriteString("    }\n")
+	}
+	for _, n := range v.nodes {
+		if !clustered[n] {
+			b.WriteString("    ")
+			writeDOTNode(&b, n)
+		}
+	}
+
+	for _, e := range v.edges {
+		attrs := []string{"label=" + dotQuote(edgeLabel(e))}
+		if e.Dev {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(&b, "    %s -> %s [%s];\n", dotQuote(e.From), dotQuote(e.To), strings.Join(attrs, ", "))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// writeDOTNode 输出一个DOT节点声明
+func writeDOTNode(b *strings.Builder, n *Node) {
+	attrs := []string{"label=" + dotQuote(n.label())}
+	switch n.Kind {
+	case KindRoot:
+		attrs = append(attrs, "style=bold")
+	case KindPlatform:
+		attrs = append(attrs, "shape=hexagon")
+	case KindMissing:
+		attrs = append(attrs, "style=dashed")
+	}
+	fmt.Fprintf(b, "%s [%s];\n", dotQuote(n.Name), strings.Join(attrs, ", "))
+}
+
+// dotQuote 返回带引号的DOT标识符
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Mermaid 以Mermaid流程图格式导出依赖图
+//
+// 节点使用n0、n1等编号作为标识，根包为n0；平台包为六边形，开发依赖的边为虚线，缺失的包使用missing样式。
+//
+// 示例:
+//
+//	fmt.Println("```mermaid")
+//	fmt.Print(g.Mermaid(graph.ExportOptions{HidePlatform: true}))
+//	fmt.Println("```")
+func (g *Graph) Mermaid(opts ExportOptions) string {
+	v := g.view(opts)
+
+	ids := make(map[string]string, len(v.nodes))
+	for i, n := range v.nodes {
+		ids[strings.ToLower(n.Name)] = fmt.Sprintf("n%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	clustered := make(map[*Node]bool)
+	for i, c := range v.clusters {
+		fmt.Fprintf(&b, "    subgraph c%d[%s]\n", i, mermaidQuote(c.vendor))
+		for _, n := range c.nodes {
+			b.WriteString("        ")
+			writeMermaidNode(&b, ids[strings.ToLower(n.Name)], n)
+			clustered[n] = true
+		}
+		b.WriteString("    end\n")
+	}
+	for _, n := range v.nodes {
+		if !clustered[n] {
+			b.WriteString("    ")
+			writeMermaidNode(&b, ids[strings.ToLower(n.Name)], n)
+		}
+	}
+
+	for _, e := range v.edges {
+		from, to := ids[strings.ToLower(e.From)], ids[strings.ToLower(e.To)]
+		if e.Dev {
+			fmt.Fprintf(&b, "    %s -. %s .-> %s\n", from, mermaidQuote(edgeLabel(e)), to)
+		} else {
+			fmt.Fprintf(&b, "    %s -- %s --> %s\n", from, mermaidQuote(edgeLabel(e)), to)
+		}
+	}
+
+	var missing []string
+	for _, n := range v.nodes {
+		if n.Kind == KindMissing {
+			missing = append(missing, ids[strings.ToLower(n.Name)])
+		}
+	}
+	if len(missing) > 0 {
+		b.WriteString("    classDef missing stroke-dasharray: 5 5\n")
+		fmt.Fprintf(&b, "    class %s missing\n", strings.Join(missing, ","))
+	}
+	return b.String()
+}
+
+// writeMermaidNode 输出一个Mermaid节点声明
+func writeMermaidNode(b *strings.Builder, id string, n *Node) {
+	label := mermaidQuote(n.label())
+	switch n.Kind {
+	case KindRoot:
+		fmt.Fprintf(b, "%s([%s])\n", id, label)
+	case KindPlatform:
+		fmt.Fprintf(b, "%s{{%s}}\n", id, label)
+	default:
+		fmt.Fprintf(b, "%s[%s]\n", id, label)
+	}
+}
+
+// mermaidQuote 返回带引号的Mermaid文字，引号使用实体转义
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+// edgeLabel 返回边上显示的文字：版本约束，虚拟包依赖附带require中的包名
+func edgeLabel(e Edge) string {
+	if e.Virtual() {
+		return e.Target + " " + e.Constraint
+	}
+	return e.Constraint
+}
+
+// jsonNode 是JSON导出中的节点
+type jsonNode struct {
+	ID      string   `json:"id"`
+	Kind    NodeKind `json:"kind"`
+	Version string   `json:"version,omitempty"`
+	Dev     bool     `json:"dev"`
+	Vendor  string   `json:"vendor,omitempty"`
+}
+
+// jsonEdge 是JSON导出中的边
+type jsonEdge struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Target     string `json:"target"`
+	Constraint string `json:"constraint"`
+	Dev        bool   `json:"dev"`
+}
+
+// jsonCluster 是JSON导出中的供应商分组
+type jsonCluster struct {
+	Vendor string   `json:"vendor"`
+	Nodes  []string `json:"nodes"`
+}
+
+// jsonGraph 是JSON导出的文档结构
+type jsonGraph struct {
+	Root     string        `json:"root"`
+	Nodes    []jsonNode    `json:"nodes"`
+	Edges    []jsonEdge    `json:"edges"`
+	Clusters []jsonCluster `json:"clusters,omitempty"`
+}
+
+// JSON 以节点和边的JSON文档导出依赖图
+//
+// 示例:
+//
+//	data, err := g.JSON(graph.ExportOptions{ClusterVendors: true})
+//	// {"root":"acme/app","nodes":[{"id":"acme/app","kind":"root","dev":false,"vendor":"acme"},...],
+//	//  "edges":[{"from":"acme/app","to":"monolog/monolog","target":"monolog/monolog","constraint":"^3.0","dev":false},...],
+//	//  "clusters":[{"vendor":"monolog","nodes":["monolog/monolog"]},...]}
+func (g *Graph) JSON(opts ExportOptions) ([]byte, error) {
+	v := g.view(opts)
+
+	doc := jsonGraph{Root: g.Root.Name, Nodes: []jsonNode{}, Edges: []jsonEdge{}}
+	for _, n := range v.nodes {
+		vendor := ""
+		if i := strings.Index(n.Name, "/"); i > 0 && n.Kind != KindPlatform {
+			vendor = strings.ToLower(n.Name[:i])
+		}
+		doc.Nodes = append(doc.Nodes, jsonNode{ID: n.Name, Kind: n.Kind, Version: n.Version, Dev: n.Dev, Vendor: vendor})
+	}
+	for _, e := range v.edges {
+		doc.Edges = append(doc.Edges, jsonEdge{From: e.From, To: e.To, Target: e.Target, Constraint: e.Constraint, Dev: e.Dev})
+	}
+	for _, c := range v.clusters {
+		jc := jsonCluster{Vendor: c.vendor}
+		for _, n := range c.nodes {
+			jc.Nodes = append(jc.Nodes, n.Name)
+		}
+		doc.Clusters = append(doc.Clusters, jc)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("error marshalling to JSON: %v", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+)
+
+func newExportGraph() *Graph {
+	return New(lock.LockedPackage{
+		Name:       "acme/app",
+		Require:    map[string]string{"php": ">=8.1", "monolog/monolog": "^3.0", "psr/log-implementation": "^3.0"},
+		RequireDev: map[string]string{"acme/tool": "^1.0"},
+	}, &lock.Lock{
+		Packages: []lock.LockedPackage{
+			{Name: "monolog/monolog", Version: "3.5.0", Require: map[string]string{"psr/log": "^2.0 || ^3.0"},
+				Provide: map[string]string{"psr/log-implementation": "3.0.0"}},
+			{Name: "psr/log", Version: "3.0.0"},
+		},
+		PackagesDev: []lock.LockedPackage{
+			{Name: "acme/tool", Version: "1.2.0", Require: map[string]string{"vendor/missing": "*"}},
+		},
+	})
+}
+
+func TestDOT(t *testing.T) {
+	g := newExportGraph()
+
+	want := `digraph dependencies {
+    rankdir=LR;
+    node [shape=box];
+    "acme/app" [label="acme/app", style=bold];
+    "acme/tool" [label="acme/tool 1.2.0"];
+    "monolog/monolog" [label="monolog/monolog 3.5.0"];
+    "php" [label="php", shape=hexagon];
+    "psr/log" [label="psr/log 3.0.0"];
+    "vendor/missing" [label="vendor/missing", style=dashed];
+    "acme/app" -> "monolog/monolog" [label="^3.0"];
+    "acme/app" -> "php" [label=">=8.1"];
+    "acme/app" -> "monolog/monolog" [label="psr/log-implementation ^3.0"];
+    "acme/app" -> "acme/tool" [label="^1.0", style=dashed];
+    "acme/tool" -> "vendor/missing" [label="*"];
+    "monolog/monolog" -> "psr/log" [label="^2.0 || ^3.0"];
+}
+`
+	if got := g.DOT(ExportOptions{}); got != want {
+		t.Errorf("DOT() =\n%s\nwant\n%s", got, want)
+	}
+
+	want = `digraph dependencies {
+    rankdir=LR;
+    node [shape=box];
+    subgraph "cluster_monolog" {
+        label="monolog";
+        "monolog/monolog" [label="monolog/monolog 3.5.0"];
+    }
+    subgraph "cluster_psr" {
+        label="psr";
+        "psr/log" [label="psr/log 3.0.0"];
+    }
+    "acme/app" [label="acme/app", style=bold];
+    "acme/app" -> "monolog/monolog" [label="^3.0"];
+    "acme/app" -> "monolog/monolog" [label="psr/log-implementation ^3.0"];
+    "monolog/monolog" -> "psr/log" [label="^2.0 || ^3.0"];
+}
+`
+	if got := g.DOT(ExportOptions{HideDev: true, HidePlatform: true, ClusterVendors: true}); got != want {
+		t.Errorf("DOT() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	g := newExportGraph()
+
+	want := `flowchart LR
+    subgraph c0["acme"]
+        n1["acme/tool 1.2.0"]
+    end
+    subgraph c1["monolog"]
+        n2["monolog/monolog 3.5.0"]
+    end
+    subgraph c2["psr"]
+        n4["psr/log 3.0.0"]
+    end
+    subgraph c3["vendor"]
+        n5["vendor/missing"]
+    end
+    n0(["acme/app"])
+    n3{{"php"}}
+    n0 -- "^3.0" --> n2
+    n0 -- ">=8.1" --> n3
+    n0 -- "psr/log-implementation ^3.0" --> n2
+    n0 -. "^1.0" .-> n1
+    n1 -- "*" --> n5
+    n2 -- "^2.0 || ^3.0" --> n4
+    classDef missing stroke-dasharray: 5 5
+    class n5 missing
+`
+	if got := g.Mermaid(ExportOptions{ClusterVendors: true}); got != want {
+		t.Errorf("Mermaid() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	g := newExportGraph()
+
+	data, err := g.JSON(ExportOptions{HideDev: true, ClusterVendors: true})
+	if err != nil {
+		t.Fatalf("JSON() returned unexpected error: %v", err)
+	}
+
+	var doc struct {
+		Root  string `json:"root"`
+		Nodes []struct {
+			ID     string `json:"id"`
+			Kind   string `json:"kind"`
+			Vendor string `json:"vendor"`
+		} `json:"nodes"`
+		Edges []struct {
+			From   string `json:"from"`
+			To     string `json:"to"`
+			Target string `json:"target"`
+		} `json:"edges"`
+		Clusters []struct {
+			Vendor string   `json:"vendor"`
+			Nodes  []string `json:"nodes"`
+		} `json:"clusters"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("JSON() returned invalid JSON: %v", err)
+	}
+
+	if doc.Root != "acme/app" || len(doc.Nodes) != 4 || len(doc.Edges) != 4 || len(doc.Clusters) != 2 {
+		t.Errorf("JSON() = %s", data)
+	}
+	if doc.Nodes[0].Kind != "root" || doc.Nodes[0].Vendor != "acme" || doc.Nodes[2].Kind != "platform" || doc.Nodes[2].Vendor != "" {
+		t.Errorf("JSON() nodes = %+v", doc.Nodes)
+	}
+	if doc.Edges[2].Target != "psr/log-implementation" || doc.Edges[2].To != "monolog/monolog" {
+		t.Errorf("JSON() edges = %+v", doc.Edges)
+	}
+}
@@ -0,0 +1,367 @@
+// Package graph 提供基于composer.json和composer.lock的依赖图
+//
+// 本包把根包和锁文件中的每个包作为节点，把require中的依赖作为边，包括：
+// - 根包require和require-dev的边（后者标记为开发依赖）
+// - 锁定包require的边，锁定包的require-dev不参与
+// - 通过replace和provide把虚拟包解析到实际提供它的锁定包
+// - 平台包（如"php"、"ext-json"）和锁文件中缺失的包作为单独类型的节点
+//
+// 在此基础上提供直接依赖、传递依赖、被依赖关系和深度等查询，以及与`composer why`和`composer why-not`对应的Why和WhyNot。
+// 依赖图可以导出为Graphviz DOT、Mermaid流程图和JSON。
+package graph
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/dependency"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+)
+
+// RootName 是根包没有名称时使用的节点名，与Composer一致
+const RootName = "__root__"
+
+// NodeKind 表示节点类型
+type NodeKind string
+
+// 节点类型定义
+const (
+	// KindRoot 根包，即composer.json描述的项目
+	KindRoot NodeKind = "root"
+
+	// KindPackage 锁文件中锁定的包
+	KindPackage NodeKind = "package"
+
+	// KindPlatform 平台包，如"php"、"ext-json"
+	KindPlatform NodeKind = "platform"
+
+	// KindMissing 被依赖但锁文件中既没有该包、也没有包replace或provide它
+	KindMissing NodeKind = "missing"
+)
+
+// Node 表示依赖图中的一个包
+type Node struct {
+	// Name 包名，保持composer.json或锁文件中的写法
+	Name string
+
+	// Kind 节点类型
+	Kind NodeKind
+
+	// Version 锁定的版本；根包为composer.json中的version，平台包和缺失的包为空
+	Version string
+
+	// Dev 锁定的包是否位于packages-dev中
+	Dev bool
+
+	// Package 锁定的包；根包节点为传入New的根包，平台包和缺失的包为nil
+	Package *lock.LockedPackage
+}
+
+// Edge 表示一条依赖
+type Edge struct {
+	// From 依赖方的节点名
+	From string
+
+	// To 被依赖方的节点名；通过replace或provide解析时为实际提供的包
+	To string
+
+	// Target require中写的包名；与To不同时表示依赖的是虚拟包，如"psr/log-implementation"
+	Target string
+
+	// Constraint require中的版本约束
+	Constraint string
+
+	// Dev 是否来自根包的require-dev
+	Dev bool
+}
+
+// Virtual 判断依赖是否通过replace或provide解析
+func (e Edge) Virtual() bool {
+	return !strings.EqualFold(e.Target, e.To)
+}
+
+// Graph 是由根包和锁定包构成的依赖图
+type Graph struct {
+	// Root 根包节点
+	Root *Node
+
+	nodes    map[string]*Node
+	edges    map[string][]Edge
+	incoming map[string][]Edge
+}
+
+// New 根据根包和锁文件构建依赖图
+//
+// 参数:
+//   - root: 根包，使用其Name、Version、Require、RequireDev、Conflict、Replace和Provide
+//   - l: 解析后的锁文件，为nil时只包含根包及其直接依赖
+//
+// 返回:
+//   - *Graph: 依赖图
+//
+// 解析规则:
+//   - 包名比较不区分大小写
+//   - 依赖的包被锁定时直接指向该包；否则指向所有replace或provide它的锁定包
+//   - 根包自身replace或provide的包不产生边
+//   - 以上都不满足时，平台包指向KindPlatform节点，其他包指向KindMissing节点
+//
+// 示例:
+//
+//	l, _ := lock.ParseFile("composer.lock")
+//	g := graph.New(lock.LockedPackage{
+//		Name:       "acme/app",
+//		Require:    map[string]string{"monolog/monolog": "^3.0"},
+//		RequireDev: map[string]string{"phpunit/phpunit": "^10.0"},
+//	}, l)
+//
+//	depth, _ := g.Depth("psr/log")
+//	fmt.Println(depth) // 2
+func New(root lock.LockedPackage, l *lock.Lock) *Graph {
+	name := root.Name
+	if name == "" {
+		name = RootName
+	}
+
+	g := &Graph{
+		nodes:    make(map[string]*Node),
+		edges:    make(map[string][]Edge),
+		incoming: make(map[string][]Edge),
+	}
+	g.Root = &Node{Name: name, Kind: KindRoot, Version: root.Version, Package: &root}
+	g.nodes[strings.ToLower(name)] = g.Root
+
+	// 虚拟包名到提供它的锁定包
+	providers := make(map[string][]string)
+	var packages []*lock.LockedPackage
+	if l != nil {
+		for _, section := range []struct {
+			pkgs []lock.LockedPackage
+			dev  bool
+		}{{l.Packages, false}, {l.PackagesDev, true}} {
+			for i := range section.pkgs {
+				pkg := &section.pkgs[i]
+				key := strings.ToLower(pkg.Name)
+				if _, ok := g.nodes[key]; ok {
+					continue
+				}
+				g.nodes[key] = &Node{Name: pkg.Name, Kind: KindPackage, Version: pkg.Version, Dev: section.dev, Package: pkg}
+				packages = append(packages, pkg)
+			}
+		}
+		for _, pkg := range packages {
+			for _, links := range []map[string]string{pkg.Replace, pkg.Provide} {
+				for target := range links {
+					key := strings.ToLower(target)
+					if !containsFold(providers[key], pkg.Name) {
+						providers[key] = append(providers[key], pkg.Name)
+					}
+				}
+			}
+		}
+		for key := range providers {
+			sort.Strings(providers[key])
+		}
+	}
+
+	rootProvides := make(map[string]bool)
+	for _, links := range []map[string]string{root.Replace, root.Provide} {
+		for target := range links {
+			rootProvides[strings.ToLower(target)] = true
+		}
+	}
+
+	g.addEdges(name, root.Require, false, providers, rootProvides)
+	g.addEdges(name, root.RequireDev, true, providers, rootProvides)
+	for _, pkg := range packages {
+		g.addEdges(pkg.Name, pkg.Require, false, providers, rootProvides)
+	}
+	return g
+}
+
+// addEdges 为一组require添加从from出发的边
+func (g *Graph) addEdges(from string, require map[string]string, dev bool, providers map[string][]string, rootProvides map[string]bool) {
+	names := make([]string, 0, len(require))
+	for target := range require {
+		names = append(names, target)
+	}
+	sort.Strings(names)
+
+	for _, target := range names {
+		key := strings.ToLower(target)
+		var to []string
+		switch {
+		case g.nodes[key] != nil && g.nodes[key].Kind != KindMissing && g.nodes[key].Kind != KindPlatform:
+			to = []string{g.nodes[key].Name}
+		case len(providers[key]) > 0:
+			to = providers[key]
+		case rootProvides[key]:
+			continue
+		default:
+			node, ok := g.nodes[key]
+			if !ok {
+				kind := KindMissing
+				if dependency.IsPlatformPackage(target) {
+					kind = KindPlatform
+				}
+				node = &Node{Name: target, Kind: kind}
+				g.nodes[key] = node
+			}
+			to = []string{node.Name}
+		}
+
+		for _, name := range to {
+			e := Edge{From: from, To: name, Target: target, Constraint: require[target], Dev: dev}
+			g.edges[strings.ToLower(from)] = append(g.edges[strings.ToLower(from)], e)
+			g.incoming[strings.ToLower(name)] = append(g.incoming[strings.ToLower(name)], e)
+		}
+	}
+}
+
+// Node 按包名（不区分大小写）查找节点
+func (g *Graph) Node(name string) (*Node, bool) {
+	n, ok := g.nodes[strings.ToLower(name)]
+	return n, ok
+}
+
+// Nodes 返回所有节点，根包在前，其余按小写包名排序
+func (g *Graph) Nodes() []*Node {
+	nodes := make([]*Node, 0, len(g.nodes))
+	for _, n := range g.nodes {
+		if n != g.Root {
+			nodes = append(nodes, n)
+		}
+	}
+	sortNodes(nodes)
+	return append([]*Node{g.Root}, nodes...)
+}
+
+// Edges 返回所有边，按依赖方的顺序（与Nodes一致）排列，同一依赖方的边按require中的包名排序
+func (g *Graph) Edges() []Edge {
+	var edges []Edge
+	for _, n := range g.Nodes() {
+		edges = append(edges, g.edges[strings.ToLower(n.Name)]...)
+	}
+	return edges
+}
+
+// Dependencies 返回包的直接依赖（出边）
+func (g *Graph) Dependencies(name string) []Edge {
+	return g.edges[strings.ToLower(name)]
+}
+
+// Dependents 返回直接依赖该包的边（入边），按依赖方的包名排序
+func (g *Graph) Dependents(name string) []Edge {
+	edges := append([]Edge(nil), g.incoming[strings.ToLower(name)]...)
+	sort.SliceStable(edges, func(i, j int) bool {
+		return strings.ToLower(edges[i].From) < strings.ToLower(edges[j].From)
+	})
+	return edges
+}
+
+// IsDirect 判断包是否是根包的直接依赖（包括require-dev以及通过replace、provide解析的依赖）
+func (g *Graph) IsDirect(name string) bool {
+	for _, e := range g.Dependencies(g.Root.Name) {
+		if strings.EqualFold(e.To, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// DirectDependencies 返回根包的直接依赖节点，按包名排序
+//
+// 参数:
+//   - includeDev: 是否包括require-dev中的依赖
+func (g *Graph) DirectDependencies(includeDev bool) []*Node {
+	return g.reachable(g.Root.Name, includeDev, 1)
+}
+
+// TransitiveDependencies 返回从包出发可以到达的所有节点（不包括包自身），按包名排序
+//
+// 只有根包有require-dev的边；name为根包时，includeDev决定是否沿这些边继续查找。
+//
+// 示例:
+//
+//	// 生产环境会安装的所有包
+//	for _, n := range g.TransitiveDependencies(g.Root.Name, false) {
+//		fmt.Println(n.Name, n.Version)
+//	}
+func (g *Graph) TransitiveDependencies(name string, includeDev bool) []*Node {
+	return g.reachable(name, includeDev, -1)
+}
+
+// Depth 返回包到根包的最短距离，根包为0，直接依赖为1
+//
+// 返回:
+//   - int: 最短距离
+//   - bool: 包不存在或从根包无法到达时返回false
+func (g *Graph) Depth(name string) (int, bool) {
+	depths := g.depths()
+	d, ok := depths[strings.ToLower(name)]
+	return d, ok
+}
+
+// depths 从根包出发广度优先遍历，返回每个可到达节点的最短距离
+func (g *Graph) depths() map[string]int {
+	rootKey := strings.ToLower(g.Root.Name)
+	depths := map[string]int{rootKey: 0}
+	queue := []string{rootKey}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		for _, e := range g.edges[key] {
+			to := strings.ToLower(e.To)
+			if _, ok := depths[to]; !ok {
+				depths[to] = depths[key] + 1
+				queue = append(queue, to)
+			}
+		}
+	}
+	return depths
+}
+
+// reachable 返回从name出发在maxDepth步内（-1表示不限）可以到达的节点
+func (g *Graph) reachable(name string, includeDev bool, maxDepth int) []*Node {
+	start := strings.ToLower(name)
+	if _, ok := g.nodes[start]; !ok {
+		return nil
+	}
+
+	seen := map[string]bool{start: true}
+	var result []*Node
+	frontier := []string{start}
+	for depth := 0; len(frontier) > 0 && (maxDepth < 0 || depth < maxDepth); depth++ {
+		var next []string
+		for _, key := range frontier {
+			for _, e := range g.edges[key] {
+				to := strings.ToLower(e.To)
+				if (e.Dev && !includeDev) || seen[to] {
+					continue
+				}
+				seen[to] = true
+				result = append(result, g.nodes[to])
+				next = append(next, to)
+			}
+		}
+		frontier = next
+	}
+	sortNodes(result)
+	return result
+}
+
+// sortNodes 按小写包名排序节点
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return strings.ToLower(nodes[i].Name) < strings.ToLower(nodes[j].Name)
+	})
+}
+
+// containsFold 不区分大小写地判断列表中是否包含指定的名称
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+)
+
+func newTestGraph() *Graph {
+	l := &lock.Lock{
+		Packages: []lock.LockedPackage{
+			{Name: "monolog/monolog", Version: "3.5.0", Require: map[string]string{"php": ">=8.1", "psr/log": "^2.0 || ^3.0"},
+				Provide: map[string]string{"psr/log-implementation": "3.0.0"}},
+			{Name: "psr/log", Version: "3.0.0", Require: map[string]string{"php": ">=8.0"}},
+			{Name: "symfony/polyfill-mbstring", Version: "v1.28.0", Provide: map[string]string{"ext-mbstring": "*"}},
+			{Name: "acme/orphan", Version: "1.0.0"},
+		},
+		PackagesDev: []lock.LockedPackage{
+			{Name: "phpunit/phpunit", Version: "10.5.0", Require: map[string]string{"sebastian/diff": "^5.0", "ext-dom": "*"},
+				RequireDev: map[string]string{"vendor/ignored": "*"}},
+			{Name: "sebastian/diff", Version: "5.1.0"},
+		},
+	}
+	return New(lock.LockedPackage{
+		Name: "acme/app",
+		Require: map[string]string{
+			"php":                    ">=8.1",
+			"Monolog/Monolog":        "^3.0",
+			"psr/log-implementation": "^3.0",
+			"ext-mbstring":           "*",
+			"vendor/missing":         "^1.0",
+			"acme/legacy":            "*",
+		},
+		RequireDev: map[string]string{"phpunit/phpunit": "^10.0"},
+		Replace:    map[string]string{"acme/legacy": "self.version"},
+	}, l)
+}
+
+func nodeNames(nodes []*Node) []string {
+	names := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		names = append(names, n.Name)
+	}
+	return names
+}
+
+func TestNew(t *testing.T) {
+	g := newTestGraph()
+
+	if g.Root.Name != "acme/app" || g.Root.Kind != KindRoot {
+		t.Errorf("Root = %+v", g.Root)
+	}
+
+	wantNodes := []string{"acme/app", "acme/orphan", "ext-dom", "monolog/monolog", "php", "phpunit/phpunit", "psr/log", "sebastian/diff", "symfony/polyfill-mbstring", "vendor/missing"}
+	if got := nodeNames(g.Nodes()); !reflect.DeepEqual(got, wantNodes) {
+		t.Errorf("Nodes() = %v, want %v", got, wantNodes)
+	}
+
+	kinds := map[string]NodeKind{"php": KindPlatform, "ext-dom": KindPlatform, "vendor/missing": KindMissing, "psr/log": KindPackage}
+	for name, want := range kinds {
+		if n, ok := g.Node(name); !ok || n.Kind != want {
+			t.Errorf("Node(%q) = %+v, want kind %s", name, n, want)
+		}
+	}
+	if n, _ := g.Node("PHPUNIT/phpunit"); n == nil || !n.Dev || n.Version != "10.5.0" || n.Package == nil {
+		t.Errorf("Node(phpunit/phpunit) = %+v", n)
+	}
+	if _, ok := g.Node("acme/legacy"); ok {
+		t.Errorf("packages replaced by the root should not become nodes")
+	}
+	if _, ok := g.Node("vendor/ignored"); ok {
+		t.Errorf("require-dev of locked packages should be ignored")
+	}
+
+	wantRoot := []Edge{
+		{From: "acme/app", To: "monolog/monolog", Target: "Monolog/Monolog", Constraint: "^3.0"},
+		{From: "acme/app", To: "symfony/polyfill-mbstring", Target: "ext-mbstring", Constraint: "*"},
+		{From: "acme/app", To: "php", Target: "php", Constraint: ">=8.1"},
+		{From: "acme/app", To: "monolog/monolog", Target: "psr/log-implementation", Constraint: "^3.0"},
+		{From: "acme/app", To: "vendor/missing", Target: "vendor/missing", Constraint: "^1.0"},
+		{From: "acme/app", To: "phpunit/phpunit", Target: "phpunit/phpunit", Constraint: "^10.0", Dev: true},
+	}
+	if got := g.Dependencies("acme/app"); !reflect.DeepEqual(got, wantRoot) {
+		t.Errorf("Dependencies(root) =\n%+v\nwant\n%+v", got, wantRoot)
+	}
+	if !wantRoot[1].Virtual() || wantRoot[0].Virtual() {
+		t.Errorf("Virtual() returned unexpected results")
+	}
+	if got := len(g.Edges()); got != 11 {
+		t.Errorf("Edges() returned %d edges, want 11", got)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	g := newTestGraph()
+
+	if got := nodeNames(g.DirectDependencies(false)); !reflect.DeepEqual(got, []string{"monolog/monolog", "php", "symfony/polyfill-mbstring", "vendor/missing"}) {
+		t.Errorf("DirectDependencies(false) = %v", got)
+	}
+	if got := nodeNames(g.DirectDependencies(true)); len(got) != 5 {
+		t.Errorf("DirectDependencies(true) = %v", got)
+	}
+
+	if got := nodeNames(g.TransitiveDependencies("acme/app", false)); !reflect.DeepEqual(got, []string{"monolog/monolog", "php", "psr/log", "symfony/polyfill-mbstring", "vendor/missing"}) {
+		t.Errorf("TransitiveDependencies(root, false) = %v", got)
+	}
+	if got := nodeNames(g.TransitiveDependencies("acme/app", true)); len(got) != 8 {
+		t.Errorf("TransitiveDependencies(root, true) = %v", got)
+	}
+	if got := nodeNames(g.TransitiveDependencies("monolog/monolog", false)); !reflect.DeepEqual(got, []string{"php", "psr/log"}) {
+		t.Errorf("TransitiveDependencies(monolog) = %v", got)
+	}
+	if got := g.TransitiveDependencies("vendor/unknown", true); got != nil {
+		t.Errorf("TransitiveDependencies(unknown) = %v, want nil", got)
+	}
+
+	if !g.IsDirect("monolog/monolog") || g.IsDirect("psr/log") {
+		t.Errorf("IsDirect() returned unexpected results")
+	}
+
+	depths := map[string]int{"acme/app": 0, "monolog/monolog": 1, "psr/log": 2, "sebastian/diff": 2, "ext-dom": 2}
+	for name, want := range depths {
+		if got, ok := g.Depth(name); !ok || got != want {
+			t.Errorf("Depth(%q) = %d, %v, want %d", name, got, ok, want)
+		}
+	}
+	if _, ok := g.Depth("acme/orphan"); ok {
+		t.Errorf("Depth() of an unreachable package should fail")
+	}
+
+	dependents := g.Dependents("php")
+	if len(dependents) != 3 || dependents[0].From != "acme/app" || dependents[2].From != "psr/log" {
+		t.Errorf("Dependents(php) = %+v", dependents)
+	}
+}
+
+func TestNewWithoutLock(t *testing.T) {
+	g := New(lock.LockedPackage{Require: map[string]string{"psr/log": "^3.0"}}, nil)
+	if g.Root.Name != RootName {
+		t.Errorf("Root.Name = %q, want %q", g.Root.Name, RootName)
+	}
+	if n, ok := g.Node("psr/log"); !ok || n.Kind != KindMissing {
+		t.Errorf("Node(psr/log) = %+v", n)
+	}
+}
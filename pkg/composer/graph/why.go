@@ -0,0 +1,295 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/constraint"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/version"
+)
+
+// Path 表示从根包到某个包的一条依赖路径，每条边带有对应的版本约束
+type Path []Edge
+
+// String 返回路径的描述
+//
+// 示例:
+//
+//	acme/app -> monolog/monolog (^3.0) -> psr/log (^2.0 || ^3.0)
+func (p Path) String() string {
+	if len(p) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(p[0].From)
+	for _, e := range p {
+		fmt.Fprintf(&b, " -> %s (%s)", e.Target, e.Constraint)
+		if e.Virtual() {
+			fmt.Fprintf(&b, " via %s", e.To)
+		}
+		if e.Dev {
+			b.WriteString(" [dev]")
+		}
+	}
+	return b.String()
+}
+
+// Dev 判断路径是否经过根包的require-dev
+func (p Path) Dev() bool {
+	for _, e := range p {
+		if e.Dev {
+			return true
+		}
+	}
+	return false
+}
+
+// Why 返回从根包到指定包的所有路径，对应`composer depends`（`composer why`）
+//
+// 参数:
+//   - name: 包名，不区分大小写
+//
+// 返回:
+//   - []Path: 所有不含环的路径，按长度排序，长度相同时按描述排序；包不存在、无法到达或是根包时为空
+//
+// 路径的数量随依赖的层数指数增长，依赖关系密集的大型锁文件应使用WhyShortest。
+//
+// 示例:
+//
+//	for _, path := range g.Why("psr/log") {
+//		fmt.Println(path)
+//		// acme/app -> monolog/monolog (^3.0) -> psr/log (^2.0 || ^3.0)
+//	}
+func (g *Graph) Why(name string) []Path {
+	target := strings.ToLower(name)
+	rootKey := strings.ToLower(g.Root.Name)
+	if _, ok := g.nodes[target]; !ok || target == rootKey {
+		return nil
+	}
+
+	var paths []Path
+	onPath := map[string]bool{rootKey: true}
+	var current Path
+	var walk func(key string)
+	walk = func(key string) {
+		for _, e := range g.edges[key] {
+			to := strings.ToLower(e.To)
+			if onPath[to] {
+				continue
+			}
+			current = append(current, e)
+			if to == target {
+				paths = append(paths, append(Path(nil), current...))
+			} else {
+				onPath[to] = true
+				walk(to)
+				onPath[to] = false
+			}
+			current = current[:len(current)-1]
+		}
+	}
+	walk(rootKey)
+
+	sortPaths(paths)
+	return paths
+}
+
+// WhyShortest 返回根包经由每个直接依赖方到达指定包的一条最短路径，是Why在大型依赖图上的替代
+//
+// 参数:
+//   - name: 包名，不区分大小写
+//
+// 返回:
+//   - []Path: 每条指向该包的依赖（入边）对应一条路径，由根包到依赖方的一条最短路径和这条依赖组成；
+//     按长度排序，长度相同时按描述排序；包不存在、无法到达或是根包时为空
+//
+// 与Composer的getDependents一样，每个包只展开一次，耗时与图的大小成线性关系。在彼此大量依赖的锁文件中
+// （如symfony/*和polyfill），Why列出的所有路径的数量随依赖的层数指数增长，这时应使用WhyShortest。
+//
+// 示例:
+//
+//	for _, path := range g.WhyShortest("psr/log") {
+//		fmt.Println(path)
+//		// acme/app -> monolog/monolog (^3.0) -> psr/log (^2.0 || ^3.0)
+//	}
+func (g *Graph) WhyShortest(name string) []Path {
+	target := strings.ToLower(name)
+	rootKey := strings.ToLower(g.Root.Name)
+	if _, ok := g.nodes[target]; !ok || target == rootKey {
+		return nil
+	}
+
+	// 从根包出发广度优先遍历，不经过目标包，记录到达每个节点的第一条边
+	parent := map[string]Edge{}
+	seen := map[string]bool{rootKey: true, target: true}
+	queue := []string{rootKey}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		for _, e := range g.edges[key] {
+			to := strings.ToLower(e.To)
+			if !seen[to] {
+				seen[to] = true
+				parent[to] = e
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	var paths []Path
+	for _, e := range g.incoming[target] {
+		from := strings.ToLower(e.From)
+		if from == target || !seen[from] {
+			continue
+		}
+		path := Path{e}
+		for key := from; key != rootKey; key = strings.ToLower(path[0].From) {
+			path = append(Path{parent[key]}, path...)
+		}
+		paths = append(paths, path)
+	}
+
+	sortPaths(paths)
+	return paths
+}
+
+// sortPaths 按长度排序路径，长度相同时按描述排序
+func sortPaths(paths []Path) {
+	sort.SliceStable(paths, func(i, j int) bool {
+		if len(paths[i]) != len(paths[j]) {
+			return len(paths[i]) < len(paths[j])
+		}
+		return paths[i].String() < paths[j].String()
+	})
+}
+
+// BlockerKind 表示阻止某个版本的原因类型
+type BlockerKind string
+
+// 阻止原因类型定义
+const (
+	// BlockedByRequire 某个包的require约束不接受该版本
+	BlockedByRequire BlockerKind = "require"
+
+	// BlockedByConflict 某个包的conflict约束排除了该版本
+	BlockedByConflict BlockerKind = "conflict"
+)
+
+// Blocker 描述阻止某个包使用指定版本的一条require或conflict
+type Blocker struct {
+	// Kind 阻止原因类型
+	Kind BlockerKind
+
+	// Package 声明该约束的包，根包或锁定的包
+	Package string
+
+	// Version 声明该约束的包的版本
+	Version string
+
+	// Target 约束中写的包名
+	Target string
+
+	// Constraint 不接受该版本的约束
+	Constraint string
+
+	// Dev 约束是否来自根包的require-dev
+	Dev bool
+}
+
+// String 返回阻止原因的描述
+//
+// 示例:
+//
+//	acme/app requires monolog/monolog (^3.0)
+//	acme/legacy 1.0.0 conflicts with monolog/monolog (>=3.0)
+func (b Blocker) String() string {
+	pkg := b.Package
+	if b.Version != "" {
+		pkg += " " + b.Version
+	}
+	verb := "requires"
+	if b.Kind == BlockedByConflict {
+		verb = "conflicts with"
+	}
+	s := fmt.Sprintf("%s %s %s (%s)", pkg, verb, b.Target, b.Constraint)
+	if b.Dev {
+		s += " [dev]"
+	}
+	return s
+}
+
+// WhyNot 返回阻止指定包使用某个版本的所有require和conflict，对应`composer prohibits`（`composer why-not`）
+//
+// 参数:
+//   - name: 包名，不区分大小写
+//   - v: 要检查的版本，如"2.0.0"、"dev-main"
+//
+// 返回:
+//   - []Blocker: 所有阻止该版本的约束，按声明约束的包排序；为空时表示没有已知的require或conflict阻止该版本
+//   - error: 如果版本无效，返回错误
+//
+// 检查规则:
+//   - 直接依赖该包名的require约束不匹配该版本时视为阻止，通过replace或provide解析的虚拟包依赖不参与
+//   - 根包和锁定包的conflict约束匹配该版本时视为阻止
+//   - 约束为"self.version"时使用声明约束的包的版本
+//   - 只检查已有的约束，不考虑该版本自身的依赖
+//
+// 示例:
+//
+//	blockers, err := g.WhyNot("monolog/monolog", "4.0.0")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, b := range blockers {
+//		fmt.Println(b) // acme/app requires monolog/monolog (^3.0)
+//	}
+func (g *Graph) WhyNot(name, v string) ([]Blocker, error) {
+	if _, err := version.Normalize(v); err != nil {
+		return nil, err
+	}
+
+	var blockers []Blocker
+	// 虚拟包的每个提供者对应一条边，同一个require只报告一次
+	seen := map[Edge]bool{}
+	for _, e := range g.Edges() {
+		if !strings.EqualFold(e.Target, name) {
+			continue
+		}
+		key := Edge{From: e.From, Target: e.Target, Constraint: e.Constraint, Dev: e.Dev}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if !g.accepts(e.From, e.Constraint, v) {
+			from := g.nodes[strings.ToLower(e.From)]
+			blockers = append(blockers, Blocker{Kind: BlockedByRequire, Package: from.Name, Version: from.Version, Target: e.Target, Constraint: e.Constraint, Dev: e.Dev})
+		}
+	}
+
+	for _, n := range g.Nodes() {
+		if n.Package == nil {
+			continue
+		}
+		for target, c := range n.Package.Conflict {
+			if strings.EqualFold(target, name) && g.accepts(n.Name, c, v) {
+				blockers = append(blockers, Blocker{Kind: BlockedByConflict, Package: n.Name, Version: n.Version, Target: target, Constraint: c})
+			}
+		}
+	}
+
+	sort.SliceStable(blockers, func(i, j int) bool {
+		return strings.ToLower(blockers[i].Package) < strings.ToLower(blockers[j].Package)
+	})
+	return blockers, nil
+}
+
+// accepts 判断from声明的约束是否匹配版本v，无效的约束视为不匹配
+func (g *Graph) accepts(from, c, v string) bool {
+	if c == "self.version" {
+		c = g.nodes[strings.ToLower(from)].Version
+	}
+	con, err := constraint.Parse(c)
+	return err == nil && con.Matches(v)
+}
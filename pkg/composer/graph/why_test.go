@@ -0,0 +1,200 @@
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+)
+
+func TestWhy(t *testing.T) {
+	g := New(lock.LockedPackage{
+		Name:       "acme/app",
+		Require:    map[string]string{"monolog/monolog": "^3.0", "psr/log": "^3.0", "psr/log-implementation": "^3.0"},
+		RequireDev: map[string]string{"acme/tool": "^1.0"},
+	}, &lock.Lock{
+		Packages: []lock.LockedPackage{
+			{Name: "monolog/monolog", Version: "3.5.0", Require: map[string]string{"psr/log": "^2.0 || ^3.0"},
+				Provide: map[string]string{"psr/log-implementation": "3.0.0"}},
+			{Name: "psr/log", Version: "3.0.0", Require: map[string]string{"monolog/monolog": "*"}},
+		},
+		PackagesDev: []lock.LockedPackage{
+			{Name: "acme/tool", Version: "1.2.0", Require: map[string]string{"psr/log": "^3.0"}},
+		},
+	})
+
+	var got []string
+	for _, p := range g.Why("PSR/LOG") {
+		got = append(got, p.String())
+	}
+	want := []string{
+		"acme/app -> psr/log (^3.0)",
+		"acme/app -> acme/tool (^1.0) [dev] -> psr/log (^3.0)",
+		"acme/app -> monolog/monolog (^3.0) -> psr/log (^2.0 || ^3.0)",
+		"acme/app -> psr/log-implementation (^3.0) via monolog/monolog -> psr/log (^2.0 || ^3.0)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Why() =\n%v\nwant\n%v", got, want)
+	}
+
+	paths := g.Why("psr/log")
+	if !paths[1].Dev() || paths[0].Dev() {
+		t.Errorf("Path.Dev() returned unexpected results")
+	}
+	if got := g.Why("acme/app"); got != nil {
+		t.Errorf("Why(root) = %v, want nil", got)
+	}
+	if got := g.Why("vendor/unknown"); got != nil {
+		t.Errorf("Why(unknown) = %v, want nil", got)
+	}
+}
+
+func TestWhyShortest(t *testing.T) {
+	g := New(lock.LockedPackage{
+		Name:    "acme/app",
+		Require: map[string]string{"monolog/monolog": "^3.0", "psr/log": "^3.0", "psr/log-implementation": "^3.0"},
+	}, &lock.Lock{
+		Packages: []lock.LockedPackage{
+			{Name: "monolog/monolog", Version: "3.5.0", Require: map[string]string{"psr/log": "^2.0 || ^3.0"},
+				Provide: map[string]string{"psr/log-implementation": "3.0.0"}},
+			{Name: "psr/log", Version: "3.0.0", Require: map[string]string{"monolog/monolog": "*"}},
+		},
+	})
+
+	var got []string
+	for _, p := range g.WhyShortest("monolog/monolog") {
+		got = append(got, p.String())
+	}
+	want := []string{
+		"acme/app -> monolog/monolog (^3.0)",
+		"acme/app -> psr/log-implementation (^3.0) via monolog/monolog",
+		"acme/app -> psr/log (^3.0) -> monolog/monolog (*)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WhyShortest() =\n%v\nwant\n%v", got, want)
+	}
+	if got := g.WhyShortest("acme/app"); got != nil {
+		t.Errorf("WhyShortest(root) = %v, want nil", got)
+	}
+}
+
+func TestWhyShortest_Dense(t *testing.T) {
+	// 12层，每层4个包，每个包依赖下一层的所有包：所有路径有4^12条
+	const layers, width = 12, 4
+	name := func(layer, i int) string { return fmt.Sprintf("acme/l%02d-%d", layer, i) }
+	layer := func(n int) map[string]string {
+		require := map[string]string{}
+		for i := 0; i < width; i++ {
+			require[name(n, i)] = "^1.0"
+		}
+		return require
+	}
+
+	l := &lock.Lock{Packages: []lock.LockedPackage{{Name: "psr/log", Version: "3.0.0"}}}
+	for n := 0; n < layers; n++ {
+		require := map[string]string{"psr/log": "^3.0"}
+		if n+1 < layers {
+			require = layer(n + 1)
+		}
+		for i := 0; i < width; i++ {
+			l.Packages = append(l.Packages, lock.LockedPackage{Name: name(n, i), Version: "1.0.0", Require: require})
+		}
+	}
+	g := New(lock.LockedPackage{Name: "acme/app", Require: layer(0)}, l)
+
+	paths := g.WhyShortest("psr/log")
+	if len(paths) != width {
+		t.Fatalf("WhyShortest() returned %d paths, want %d", len(paths), width)
+	}
+	for i, p := range paths {
+		if len(p) != layers+1 || p[len(p)-1].From != name(layers-1, i) {
+			t.Errorf("WhyShortest()[%d] = %v, want a shortest path through %s", i, p, name(layers-1, i))
+		}
+	}
+}
+
+func TestWhyNot(t *testing.T) {
+	g := New(lock.LockedPackage{
+		Name:       "acme/app",
+		Require:    map[string]string{"monolog/monolog": "^3.0"},
+		RequireDev: map[string]string{"acme/tool": "^1.0"},
+		Conflict:   map[string]string{"psr/log": "3.0.1"},
+	}, &lock.Lock{
+		Packages: []lock.LockedPackage{
+			{Name: "monolog/monolog", Version: "3.5.0", Require: map[string]string{"psr/log": "^2.0 || ^3.0"}},
+			{Name: "psr/log", Version: "3.0.0"},
+			{Name: "acme/bundle", Version: "2.1.0", Require: map[string]string{"acme/tool": "self.version"}},
+		},
+		PackagesDev: []lock.LockedPackage{
+			{Name: "acme/tool", Version: "1.2.0", Require: map[string]string{"psr/log": "~3.0.0"},
+				Conflict: map[string]string{"monolog/monolog": ">=4.0"}},
+		},
+	})
+
+	tests := []struct {
+		name    string
+		version string
+		want    []string
+	}{
+		{"psr/log", "3.0.2", nil},
+		{"psr/log", "3.0.1", []string{"acme/app conflicts with psr/log (3.0.1)"}},
+		{"psr/log", "3.1.0", []string{"acme/tool 1.2.0 requires psr/log (~3.0.0)"}},
+		{"psr/log", "4.0.0", []string{
+			"acme/tool 1.2.0 requires psr/log (~3.0.0)",
+			"monolog/monolog 3.5.0 requires psr/log (^2.0 || ^3.0)",
+		}},
+		{"monolog/monolog", "4.0.0", []string{
+			"acme/app requires monolog/monolog (^3.0)",
+			"acme/tool 1.2.0 conflicts with monolog/monolog (>=4.0)",
+		}},
+		{"acme/tool", "1.3.0", []string{"acme/bundle 2.1.0 requires acme/tool (self.version)"}},
+		{"acme/tool", "2.0.0", []string{
+			"acme/app requires acme/tool (^1.0) [dev]",
+			"acme/bundle 2.1.0 requires acme/tool (self.version)",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.version, func(t *testing.T) {
+			blockers, err := g.WhyNot(tt.name, tt.version)
+			if err != nil {
+				t.Fatalf("WhyNot() returned unexpected error: %v", err)
+			}
+			var got []string
+			for _, b := range blockers {
+				got = append(got, b.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WhyNot() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := g.WhyNot("psr/log", "not a version"); err == nil {
+		t.Errorf("WhyNot() should fail for an invalid version")
+	}
+}
+
+func TestWhyNot_MultipleProviders(t *testing.T) {
+	g := New(lock.LockedPackage{
+		Name:    "acme/app",
+		Require: map[string]string{"psr/log-implementation": "^3.0"},
+	}, &lock.Lock{
+		Packages: []lock.LockedPackage{
+			{Name: "monolog/monolog", Version: "3.5.0", Provide: map[string]string{"psr/log-implementation": "3.0.0"}},
+			{Name: "acme/logger", Version: "1.0.0", Provide: map[string]string{"psr/log-implementation": "3.0.0"}},
+		},
+	})
+
+	blockers, err := g.WhyNot("psr/log-implementation", "2.0.0")
+	if err != nil {
+		t.Fatalf("WhyNot() returned unexpected error: %v", err)
+	}
+	var got []string
+	for _, b := range blockers {
+		got = append(got, b.String())
+	}
+	if want := []string{"acme/app requires psr/log-implementation (^3.0)"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WhyNot() = %v, want %v", got, want)
+	}
+}
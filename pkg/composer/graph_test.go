@@ -0,0 +1,40 @@
+package composer
+
+import (
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+)
+
+func TestComposerJSON_DependencyGraph(t *testing.T) {
+	project, err := ParseString(`{
+		"name": "acme/app",
+		"require": {"php": ">=8.1", "monolog/monolog": "^3.0"},
+		"require-dev": {"phpunit/phpunit": "^10.0"}
+	}`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	l := &lock.Lock{
+		Packages: []lock.LockedPackage{
+			{Name: "monolog/monolog", Version: "3.5.0", Require: map[string]string{"psr/log": "^3.0"}},
+			{Name: "psr/log", Version: "3.0.0"},
+		},
+		PackagesDev: []lock.LockedPackage{{Name: "phpunit/phpunit", Version: "10.5.0"}},
+	}
+
+	g := project.DependencyGraph(l)
+	if g.Root.Name != "acme/app" {
+		t.Errorf("Root.Name = %q, want acme/app", g.Root.Name)
+	}
+	if depth, ok := g.Depth("psr/log"); !ok || depth != 2 {
+		t.Errorf("Depth(psr/log) = %d, %v, want 2", depth, ok)
+	}
+	if got := len(g.TransitiveDependencies(g.Root.Name, false)); got != 3 {
+		t.Errorf("TransitiveDependencies() returned %d nodes, want 3", got)
+	}
+	if edges := g.Dependencies(g.Root.Name); len(edges) != 3 || !edges[2].Dev {
+		t.Errorf("Dependencies(root) = %+v", edges)
+	}
+}
@@ -0,0 +1,148 @@
+package composer
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/spdx"
+)
+
+// LicenseExpression 返回license字段解析后的SPDX许可证表达式
+//
+// 与Composer一致，数组形式的license表示可以任选其一的多个许可证，各项用OR连接，
+// 如["MIT", "GPL-3.0-or-later"]等同于"MIT OR GPL-3.0-or-later"。除SPDX标识符外还接受"proprietary"。
+//
+// 返回:
+//   - spdx.Expression: 解析后的表达式，没有license字段或license为空数组时为nil
+//   - error: license不是字符串或字符串数组时返回错误；表达式无效时返回包装了spdx.ErrInvalidExpression的错误
+//
+// 示例:
+//
+//	composer, _ := composer.ParseString(`{"license": "(MIT or GPL-3.0-or-later)"}`)
+//
+//	expr, err := composer.LicenseExpression()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, alt := range spdx.Alternatives(expr) {
+//		fmt.Println(alt) // 输出: MIT、GPL-3.0-or-later
+//	}
+func (c *ComposerJSON) LicenseExpression() (spdx.Expression, error) {
+	values, _, err := c.licenseValues()
+	if err != nil {
+		return nil, err
+	}
+
+	var expr spdx.Expression
+	for _, value := range values {
+		parsed, err := spdx.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("license %q: %w", value, err)
+		}
+		if expr == nil {
+			expr = parsed
+		} else {
+			expr = &spdx.Compound{Operator: spdx.Or, Left: expr, Right: parsed}
+		}
+	}
+	return expr, nil
+}
+
+// LicenseDeprecations 返回license中已被SPDX废弃的许可证和例外
+//
+// 返回:
+//   - []spdx.Deprecation: 已废弃的标识符和建议的替代标识符；license无效时返回nil，错误由LicenseExpression报告
+//
+// 示例:
+//
+//	composer, _ := composer.ParseString(`{"license": ["GPL-2.0", "MIT"]}`)
+//	for _, d := range composer.LicenseDeprecations() {
+//		fmt.Println(d) // 输出: "GPL-2.0" is a deprecated SPDX license identifier, use "GPL-2.0-only" or "GPL-2.0-or-later" instead
+//	}
+func (c *ComposerJSON) LicenseDeprecations() []spdx.Deprecation {
+	expr, err := c.LicenseExpression()
+	if err != nil || expr == nil {
+		return nil
+	}
+	return spdx.Deprecations(expr)
+}
+
+// SetLicense 解析并设置license字段，保持原来的字符串或数组写法
+//
+// 参数:
+//   - expression: 许可证表达式，如"MIT"、"(MIT or GPL-3.0-or-later)"、"proprietary"
+//
+// 返回:
+//   - error: 表达式无效时返回包装了spdx.ErrInvalidExpression的错误，license不变
+//
+// 写入的规则见SetLicenseExpression。
+//
+// 示例:
+//
+//	composer, _ := composer.ParseFile("./composer.json") // "license": ["MIT"]
+//
+//	if err := composer.SetLicense("mit or apache-2.0"); err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(composer.License) // 输出: [MIT Apache-2.0]
+//	composer.Save("./composer.json", true)
+func (c *ComposerJSON) SetLicense(expression string) error {
+	expr, err := spdx.Parse(expression)
+	if err != nil {
+		return err
+	}
+	c.SetLicenseExpression(expr)
+	return nil
+}
+
+// SetLicenseExpression 设置license字段，保持原来的字符串或数组写法
+//
+// 原来是数组时按顶层的OR拆分为多项，如"MIT OR GPL-3.0-or-later"写为["MIT", "GPL-3.0-or-later"]；
+// 原来是字符串或没有license字段时写为一个字符串。表达式按spdx.Expression.String的规范写法写入。
+//
+// 参数:
+//   - expr: 许可证表达式，为nil时删除license字段
+//
+// 示例:
+//
+//	expr, _ := spdx.Parse("GPL-2.0-or-later WITH Classpath-exception-2.0")
+//	composer.SetLicenseExpression(expr)
+func (c *ComposerJSON) SetLicenseExpression(expr spdx.Expression) {
+	if expr == nil {
+		c.License = nil
+		return
+	}
+	if _, list, _ := c.licenseValues(); !list {
+		c.License = expr.String()
+		return
+	}
+
+	var licenses []string
+	for _, alt := range spdx.Alternatives(expr) {
+		licenses = append(licenses, alt.String())
+	}
+	c.License = licenses
+}
+
+// licenseValues 返回license字段中的表达式，以及它是否写成数组
+func (c *ComposerJSON) licenseValues() ([]string, bool, error) {
+	switch license := c.License.(type) {
+	case nil:
+		return nil, false, nil
+	case string:
+		return []string{license}, false, nil
+	case []string:
+		return license, true, nil
+	case []interface{}:
+		values := make([]string, len(license))
+		for i, v := range license {
+			s, ok := v.(string)
+			if !ok {
+				return nil, true, fmt.Errorf("license[%d] must be a string, %T given", i, v)
+			}
+			values[i] = s
+		}
+		return values, true, nil
+	default:
+		return nil, false, fmt.Errorf("license must be a string or an array of strings, %T given", c.License)
+	}
+}
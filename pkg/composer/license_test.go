@@ -0,0 +1,144 @@
+package composer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/spdx"
+)
+
+func TestComposerJSON_LicenseExpression(t *testing.T) {
+	tests := []struct {
+		json string
+		want string
+	}{
+		{`{}`, ""},
+		{`{"license": []}`, ""},
+		{`{"license": "MIT"}`, "MIT"},
+		{`{"license": "proprietary"}`, "proprietary"},
+		{`{"license": "(MIT or GPL-3.0-or-later)"}`, "MIT OR GPL-3.0-or-later"},
+		{`{"license": "GPL-2.0-or-later WITH Classpath-exception-2.0"}`, "GPL-2.0-or-later WITH Classpath-exception-2.0"},
+		{`{"license": ["MIT", "Apache-2.0 AND ISC"]}`, "MIT OR Apache-2.0 AND ISC"},
+	}
+	for _, tt := range tests {
+		c, err := ParseString(tt.json)
+		if err != nil {
+			t.Fatalf("ParseString(%s) returned unexpected error: %v", tt.json, err)
+		}
+		expr, err := c.LicenseExpression()
+		if err != nil {
+			t.Errorf("%s: LicenseExpression() returned unexpected error: %v", tt.json, err)
+			continue
+		}
+		got := ""
+		if expr != nil {
+			got = expr.String()
+		}
+		if got != tt.want {
+			t.Errorf("%s: LicenseExpression() = %q, want %q", tt.json, got, tt.want)
+		}
+	}
+
+	c, _ := ParseString(`{"license": ["MIT", "MIT-2"]}`)
+	if _, err := c.LicenseExpression(); !errors.Is(err, spdx.ErrInvalidExpression) {
+		t.Errorf("LicenseExpression() error = %v, want spdx.ErrInvalidExpression", err)
+	}
+	for _, json := range []string{`{"license": 1}`, `{"license": ["MIT", 1]}`} {
+		c, _ := ParseString(json)
+		if _, err := c.LicenseExpression(); err == nil {
+			t.Errorf("%s: LicenseExpression() returned no error", json)
+		}
+	}
+}
+
+func TestComposerJSON_LicenseDeprecations(t *testing.T) {
+	c, _ := ParseString(`{"license": ["GPL-2.0", "MIT"]}`)
+	got := c.LicenseDeprecations()
+	want := []spdx.Deprecation{{ID: "GPL-2.0", Replacements: []string{"GPL-2.0-only", "GPL-2.0-or-later"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LicenseDeprecations() = %v, want %v", got, want)
+	}
+
+	c, _ = ParseString(`{"license": "MIT"}`)
+	if got := c.LicenseDeprecations(); got != nil {
+		t.Errorf("LicenseDeprecations() = %v, want nil", got)
+	}
+}
+
+func TestComposerJSON_SetLicense(t *testing.T) {
+	tests := []struct {
+		name       string
+		json       string
+		expression string
+		want       string
+	}{
+		{
+			name:       "string stays a string",
+			json:       "{\n    \"name\": \"acme/app\",\n    \"license\": \"MIT\"\n}\n",
+			expression: "(mit or GPL-3.0-or-later)",
+			want:       "{\n    \"name\": \"acme/app\",\n    \"license\": \"MIT OR GPL-3.0-or-later\"\n}\n",
+		},
+		{
+			name:       "array stays an array",
+			json:       "{\n    \"name\": \"acme/app\",\n    \"license\": [\"MIT\"]\n}\n",
+			expression: "proprietary",
+			want:       "{\n    \"name\": \"acme/app\",\n    \"license\": [\"proprietary\"]\n}\n",
+		},
+		{
+			name:       "array is split on OR",
+			json:       "{\n    \"license\": [\n        \"MIT\"\n    ]\n}\n",
+			expression: "MIT OR (Apache-2.0 AND ISC)",
+			want:       "{\n    \"license\": [\n        \"MIT\",\n        \"Apache-2.0 AND ISC\"\n    ]\n}\n",
+		},
+		{
+			name:       "missing license is written as a string",
+			json:       "{\n    \"name\": \"acme/app\"\n}\n",
+			expression: "GPL-2.0-or-later WITH Classpath-exception-2.0",
+			want:       "{\n    \"name\": \"acme/app\",\n    \"license\": \"GPL-2.0-or-later WITH Classpath-exception-2.0\"\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "composer.json")
+			if err := os.WriteFile(path, []byte(tt.json), 0644); err != nil {
+				t.Fatal(err)
+			}
+			c, err := ParseFile(path)
+			if err != nil {
+				t.Fatalf("ParseFile() returned unexpected error: %v", err)
+			}
+			if err := c.SetLicense(tt.expression); err != nil {
+				t.Fatalf("SetLicense() returned unexpected error: %v", err)
+			}
+			if err := c.Save(path, true); err != nil {
+				t.Fatalf("Save() returned unexpected error: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("saved composer.json = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestComposerJSON_SetLicense_Invalid(t *testing.T) {
+	c, _ := ParseString(`{"license": "MIT"}`)
+	if err := c.SetLicense("MIT-2"); !errors.Is(err, spdx.ErrInvalidExpression) {
+		t.Errorf("SetLicense() error = %v, want spdx.ErrInvalidExpression", err)
+	}
+	if c.License != "MIT" {
+		t.Errorf("License = %v, want it unchanged", c.License)
+	}
+
+	c.SetLicenseExpression(nil)
+	out, err := c.ToJSON(true)
+	if err != nil || out != `{}` {
+		t.Errorf("ToJSON() after removing the license = %q, %v", out, err)
+	}
+}
@@ -0,0 +1,123 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// DefaultConfigFile 是约定的配置文件名，放在composer.json旁边
+const DefaultConfigFile = ".composer-lint.json"
+
+// Config 是Linter的配置
+//
+// 配置文件示例:
+//
+//	{
+//	    "rules": {
+//	        "allowed-vendor": {"options": {"vendors": ["acme"]}},
+//	        "php-constraint": {"severity": "warning", "options": {"constraint": "^8.2"}},
+//	        "no-wildcard-require": "off",
+//	        "no-dev-master": "error"
+//	    }
+//	}
+type Config struct {
+	// Rules 按规则ID设置规则，未出现的规则使用默认设置，默认不启用的规则保持禁用
+	Rules map[string]RuleConfig `json:"rules"`
+}
+
+// RuleConfig 是一条规则的设置
+//
+// 在配置文件中可以写成对象，也可以简写为字符串："off"表示禁用，
+// "error"、"warning"、"info"表示覆盖严重程度。
+type RuleConfig struct {
+	// Enabled 是否启用规则，为nil时启用；默认不启用的规则（见Registry.RegisterOptIn）只要出现在配置中并且没有禁用就会启用
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Severity 覆盖规则的默认严重程度，为空时使用默认值
+	Severity Severity `json:"severity,omitempty"`
+
+	// Options 规则的选项，只有实现了Configurable的规则接受选项
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+// UnmarshalJSON 解析对象形式或字符串简写形式的规则设置
+func (rc *RuleConfig) UnmarshalJSON(data []byte) error {
+	var short string
+	if err := json.Unmarshal(data, &short); err == nil {
+		switch severity := Severity(short); {
+		case short == "off":
+			enabled := false
+			*rc = RuleConfig{Enabled: &enabled}
+		case severity.valid():
+			*rc = RuleConfig{Severity: severity}
+		default:
+			return fmt.Errorf("%w: unknown rule setting %q", ErrInvalidConfig, short)
+		}
+		return nil
+	}
+
+	type plain RuleConfig
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var p plain
+	if err := dec.Decode(&p); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	*rc = RuleConfig(p)
+	return nil
+}
+
+// ParseConfig 解析配置文件的内容
+//
+// 参数:
+//   - data: JSON格式的配置
+//
+// 返回:
+//   - *Config: 解析后的配置
+//   - error: 如果配置不是有效的JSON、包含未知的字段或无效的严重程度，返回包装了ErrInvalidConfig的错误
+func ParseConfig(data []byte) (*Config, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		if errors.Is(err, ErrInvalidConfig) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	for id, rc := range cfg.Rules {
+		if rc.Severity != "" && !rc.Severity.valid() {
+			return nil, fmt.Errorf("%w: rule %s: unknown severity %q", ErrInvalidConfig, id, rc.Severity)
+		}
+	}
+	return &cfg, nil
+}
+
+// LoadConfig 读取并解析配置文件
+//
+// 参数:
+//   - filePath: 配置文件路径，如DefaultConfigFile
+//
+// 返回:
+//   - *Config: 解析后的配置
+//   - error: 如果读取文件失败或配置无效，返回错误
+func LoadConfig(filePath string) (*Config, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(data)
+}
+
+// decodeOptions 严格地解析规则选项，不允许未知的字段
+func decodeOptions(options json.RawMessage, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(options))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%w: invalid options: %v", ErrInvalidConfig, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package lint
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{
+    "rules": {
+        "allowed-vendor": {"enabled": true, "severity": "warning", "options": {"vendors": ["acme"]}},
+        "no-dev-master": "off",
+        "no-wildcard-require": "info"
+    }
+}`))
+	if err != nil {
+		t.Fatalf("ParseConfig() returned unexpected error: %v", err)
+	}
+
+	vendor := cfg.Rules["allowed-vendor"]
+	if vendor.Enabled == nil || !*vendor.Enabled || vendor.Severity != SeverityWarning || string(vendor.Options) != `{"vendors": ["acme"]}` {
+		t.Errorf("allowed-vendor = %+v", vendor)
+	}
+	if off := cfg.Rules["no-dev-master"]; off.Enabled == nil || *off.Enabled {
+		t.Errorf("no-dev-master = %+v, want disabled", off)
+	}
+	if info := cfg.Rules["no-wildcard-require"]; info.Enabled != nil || info.Severity != SeverityInfo {
+		t.Errorf("no-wildcard-require = %+v, want severity info", info)
+	}
+}
+
+func TestParseConfig_Errors(t *testing.T) {
+	tests := []string{
+		`not json`,
+		`{"rule": {}}`,
+		`{"rules": {"no-dev-master": "disabled"}}`,
+		`{"rules": {"no-dev-master": {"severity": "fatal"}}}`,
+		`{"rules": {"no-dev-master": {"enable": false}}}`,
+		`{"rules": {"no-dev-master": 1}}`,
+	}
+	for _, data := range tests {
+		if _, err := ParseConfig([]byte(data)); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("ParseConfig(%s) error = %v, want ErrInvalidConfig", data, err)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultConfigFile)
+	if err := os.WriteFile(path, []byte(`{"rules": {"no-dev-master": "warning"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() returned unexpected error: %v", err)
+	}
+	if cfg.Rules["no-dev-master"].Severity != SeverityWarning {
+		t.Errorf("LoadConfig() = %+v", cfg)
+	}
+
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadConfig() with a missing file expected error, got nil")
+	}
+}
@@ -0,0 +1,50 @@
+package lint
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{
+			name:   "equal",
+			before: "a\nb\n",
+			after:  "a\nb\n",
+			want:   "",
+		},
+		{
+			name:   "change",
+			before: "a\nb\nc\n",
+			after:  "a\nB\nc\n",
+			want:   "--- a/composer.json\n+++ b/composer.json\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:   "insert into empty",
+			before: "",
+			after:  "a\n",
+			want:   "--- a/composer.json\n+++ b/composer.json\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			name:   "separate hunks",
+			before: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			after:  "x\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n",
+			want: "--- a/composer.json\n+++ b/composer.json\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n" +
+				"@@ -9,4 +9,3 @@\n 9\n 10\n 11\n-12\n",
+		},
+		{
+			name:   "merged hunks",
+			before: "1\n2\n3\n4\n5\n6\n7\n8\n",
+			after:  "x\n2\n3\n4\n5\n6\n7\ny\n",
+			want:   "--- a/composer.json\n+++ b/composer.json\n@@ -1,8 +1,8 @@\n-1\n+x\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+y\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := unifiedDiff("composer.json", tt.before, tt.after); got != tt.want {
+			t.Errorf("%s: unifiedDiff() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer"
+)
+
+// Fix 是问题的自动修复
+//
+// 实现修复时:
+//   - Apply应该通过ComposerJSON的方法（如AddDependency、SortLinks）修改，保存时才能保留原始格式
+//   - Apply应该在应用时重新检查条件，问题已经不存在时什么都不做，这样多个修复可以按任意顺序应用
+type Fix struct {
+	// Description 修复说明，如`move phpunit/phpunit to require-dev`
+	Description string
+
+	// Apply 修改composer.json
+	Apply func(c *composer.ComposerJSON) error
+}
+
+// ApplyFixes 依次应用问题的自动修复，跳过没有修复的问题
+//
+// 参数:
+//   - c: 要修改的composer.json
+//   - findings: Linter.Lint返回的问题
+//
+// 返回:
+//   - Findings: 已应用修复的问题
+//   - error: 修复失败时返回错误，之前的修复已经应用
+func ApplyFixes(c *composer.ComposerJSON, findings Findings) (Findings, error) {
+	var applied Findings
+	for _, f := range findings {
+		if f.Fix == nil {
+			continue
+		}
+		if err := f.Fix.Apply(c); err != nil {
+			return applied, fmt.Errorf("%s: %s: %w", f.RuleID, f.Fix.Description, err)
+		}
+		applied = append(applied, f)
+	}
+	return applied, nil
+}
+
+// DryRun 在composer.json的副本上应用自动修复，返回修复前后的统一格式差异，不修改c
+//
+// 参数:
+//   - c: 要检查的composer.json
+//   - findings: Linter.Lint返回的问题
+//
+// 返回:
+//   - string: 统一格式的差异，修复不会改变文件时为空字符串
+//   - error: 序列化或修复失败时返回错误
+//
+// 示例:
+//
+//	diff, err := lint.DryRun(project, findings)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Print(diff)
+//	// --- a/composer.json
+//	// +++ b/composer.json
+//	// @@ -1,5 +1,5 @@
+//	//  {
+//	// -    "name": "Acme/App",
+//	// +    "name": "acme/app",
+//	// ...
+func DryRun(c *composer.ComposerJSON, findings Findings) (string, error) {
+	before, err := c.ToJSON(true)
+	if err != nil {
+		return "", err
+	}
+	clone, err := composer.ParseString(before)
+	if err != nil {
+		return "", err
+	}
+	if _, err := ApplyFixes(clone, findings); err != nil {
+		return "", err
+	}
+	after, err := clone.ToJSON(true)
+	if err != nil {
+		return "", err
+	}
+	return unifiedDiff("composer.json", before, after), nil
+}
+
+// Apply 应用自动修复并用ComposerJSON.Save保存到文件
+//
+// 参数:
+//   - c: 要修改的composer.json
+//   - findings: Linter.Lint返回的问题
+//   - filePath: 保存的路径，通常是"composer.json"
+//
+// 返回:
+//   - Findings: 已应用修复的问题；没有可以修复的问题时不写文件
+//   - error: 修复或保存失败时返回错误
+//
+// 示例:
+//
+//	applied, err := lint.Apply(project, findings, "composer.json")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("已修复%d个问题\n", len(applied))
+func Apply(c *composer.ComposerJSON, findings Findings, filePath string) (Findings, error) {
+	applied, err := ApplyFixes(c, findings)
+	if err != nil || len(applied) == 0 {
+		return applied, err
+	}
+	if err := c.Save(filePath, true); err != nil {
+		return applied, err
+	}
+	return applied, nil
+}
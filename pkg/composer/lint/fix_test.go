@@ -0,0 +1,166 @@
+package lint
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer"
+)
+
+const fixInput = `{
+    "name": "Acme/App",
+    "license": "MIT",
+    "require": {
+        "php": "^8.2",
+        "phpunit/phpunit": "^10.5",
+        "psr/log": "^3.0"
+    },
+    "require-dev": {}
+}
+`
+
+// fixLinter 创建启用了所有默认不启用的内置规则的Linter
+func fixLinter(t *testing.T) *Linter {
+	t.Helper()
+	cfg, err := ParseConfig([]byte(`{
+    "rules": {
+        "lowercase-name": "warning",
+        "dev-package-in-require": "warning",
+        "sorted-packages": "info",
+        "no-empty-sections": "info",
+        "license-list": "info"
+    }
+}`))
+	if err != nil {
+		t.Fatalf("ParseConfig() returned unexpected error: %v", err)
+	}
+	linter, err := New(DefaultRegistry(), cfg)
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+	return linter
+}
+
+func TestDryRun(t *testing.T) {
+	c := parse(t, fixInput)
+	findings := fixLinter(t).Lint(c)
+
+	diff, err := DryRun(c, findings)
+	if err != nil {
+		t.Fatalf("DryRun() returned unexpected error: %v", err)
+	}
+	want := `--- a/composer.json
++++ b/composer.json
+@@ -1,10 +1,13 @@
+ {
+-    "name": "Acme/App",
+-    "license": "MIT",
++    "name": "acme/app",
++    "license": [
++        "MIT"
++    ],
+     "require": {
+         "php": "^8.2",
+-        "phpunit/phpunit": "^10.5",
+         "psr/log": "^3.0"
+     },
+-    "require-dev": {}
++    "require-dev": {
++        "phpunit/phpunit": "^10.5"
++    }
+ }
+`
+	if diff != want {
+		t.Errorf("DryRun() = %s, want %s", diff, want)
+	}
+	if c.Name != "Acme/App" || !c.DependencyExists("phpunit/phpunit") {
+		t.Error("DryRun() modified the original composer.json")
+	}
+
+	diff, err = DryRun(parse(t, `{"name": "acme/app"}`), nil)
+	if err != nil || diff != "" {
+		t.Errorf("DryRun() without fixes = %q, %v, want no diff", diff, err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "composer.json")
+	if err := os.WriteFile(path, []byte(fixInput), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := composer.ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile() returned unexpected error: %v", err)
+	}
+	linter := fixLinter(t)
+
+	applied, err := Apply(c, linter.Lint(c), path)
+	if err != nil {
+		t.Fatalf("Apply() returned unexpected error: %v", err)
+	}
+	var ids []string
+	for _, f := range applied {
+		ids = append(ids, f.RuleID)
+	}
+	if len(ids) != 4 {
+		t.Errorf("Apply() applied %v, want 4 fixes", ids)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+    "name": "acme/app",
+    "license": [
+        "MIT"
+    ],
+    "require": {
+        "php": "^8.2",
+        "psr/log": "^3.0"
+    },
+    "require-dev": {
+        "phpunit/phpunit": "^10.5"
+    }
+}
+`
+	if string(data) != want {
+		t.Errorf("saved composer.json = %s, want %s", data, want)
+	}
+
+	if got := linter.Lint(c); len(got) != 0 {
+		t.Errorf("Lint() after Apply() = %v, want no findings", got)
+	}
+}
+
+func TestApply_NothingToFix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "composer.json")
+	c := parse(t, `{"name": "acme/app"}`)
+	applied, err := Apply(c, Findings{{RuleID: "no-dev-master", Message: "not fixable"}}, path)
+	if err != nil || applied != nil {
+		t.Errorf("Apply() = %v, %v, want nothing applied", applied, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Apply() without fixes wrote the file")
+	}
+}
+
+func TestApplyFixes_Error(t *testing.T) {
+	errFix := errors.New("fix failed")
+	findings := Findings{
+		{RuleID: "a", Fix: &Fix{Description: "first", Apply: func(*composer.ComposerJSON) error { return nil }}},
+		{RuleID: "b", Fix: &Fix{Description: "second", Apply: func(*composer.ComposerJSON) error { return errFix }}},
+	}
+	applied, err := ApplyFixes(parse(t, `{}`), findings)
+	if !errors.Is(err, errFix) || err.Error() != "b: second: fix failed" {
+		t.Errorf("ApplyFixes() error = %v, want the wrapped fix error", err)
+	}
+	if len(applied) != 1 || applied[0].RuleID != "a" {
+		t.Errorf("ApplyFixes() applied = %v, want only the first fix", applied)
+	}
+	if got := findings.Fixable(); len(got) != 2 {
+		t.Errorf("Fixable() = %v, want both findings", got)
+	}
+}
@@ -0,0 +1,309 @@
+// Package lint 提供可扩展的composer.json规则检查，用于执行团队自己的约定
+//
+// validation包检查的是Composer本身的规则；本包检查的是组织内部的约定，例如"vendor必须是acme"、
+// "不允许依赖dev-master"、"php必须是^8.2"。每条规则实现Rule接口并注册到Registry，
+// Linter按配置文件启用或禁用规则、覆盖严重程度并传入规则选项，然后对ComposerJSON运行所有启用的规则。
+//
+// 内置规则见DefaultRegistry。
+//
+// 示例:
+//
+//	cfg, err := lint.LoadConfig(".composer-lint.json")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	linter, err := lint.New(lint.DefaultRegistry(), cfg)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	project, _ := composer.ParseDir(".")
+//	findings := linter.Lint(project)
+//	for _, f := range findings {
+//		fmt.Println(f) // error /name: vendor "foo" is not allowed, expected acme (allowed-vendor)
+//	}
+//	if findings.HasErrors() {
+//		os.Exit(1)
+//	}
+//
+// 部分问题带有自动修复，DryRun显示修复会产生的差异，Apply应用修复并保存:
+//
+//	diff, _ := lint.DryRun(project, findings)
+//	fmt.Print(diff)
+//	lint.Apply(project, findings, "composer.json")
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+)
+
+// Severity 是检查结果的严重程度
+type Severity string
+
+// 严重程度
+const (
+	// SeverityError 违反了必须遵守的约定，通常应使CI失败
+	SeverityError Severity = "error"
+
+	// SeverityWarning 违反了建议遵守的约定
+	SeverityWarning Severity = "warning"
+
+	// SeverityInfo 仅供参考
+	SeverityInfo Severity = "info"
+)
+
+// valid 判断是否是已知的严重程度
+func (s Severity) valid() bool {
+	return s == SeverityError || s == SeverityWarning || s == SeverityInfo
+}
+
+// rank 按从严重到轻微的顺序排列严重程度
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 0
+	case SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Finding 是规则发现的一个问题
+type Finding struct {
+	// RuleID 发现问题的规则，由Linter填写
+	RuleID string
+
+	// Severity 严重程度，由Linter按规则的默认值或配置填写
+	Severity Severity
+
+	// Pointer 问题所在值的JSON指针，如"/require/acme~1lib"；可以指向缺失的键，如"/require/php"
+	Pointer string
+
+	// Message 问题说明
+	Message string
+
+	// Fix 自动修复，规则无法自动修复时为nil
+	Fix *Fix
+}
+
+// String 把问题格式化为"severity pointer: message (rule)"
+func (f Finding) String() string {
+	if f.Pointer == "" {
+		return fmt.Sprintf("%s: %s (%s)", f.Severity, f.Message, f.RuleID)
+	}
+	return fmt.Sprintf("%s %s: %s (%s)", f.Severity, f.Pointer, f.Message, f.RuleID)
+}
+
+// Findings 是检查结果列表，按错误、警告、提示的顺序排列
+type Findings []Finding
+
+// Filter 返回指定严重程度的问题
+func (fs Findings) Filter(severity Severity) Findings {
+	var result Findings
+	for _, f := range fs {
+		if f.Severity == severity {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// Fixable 返回可以自动修复的问题
+func (fs Findings) Fixable() Findings {
+	var result Findings
+	for _, f := range fs {
+		if f.Fix != nil {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// HasErrors 判断是否有错误级别的问题
+func (fs Findings) HasErrors() bool {
+	return len(fs.Filter(SeverityError)) > 0
+}
+
+// Rule 是一条检查规则
+//
+// 实现规则时:
+//   - ID应该是短横线分隔的小写单词，如"no-dev-master"，在Registry中唯一
+//   - Check只需要填写Finding的Pointer和Message，RuleID和Severity由Linter填写
+//   - Check不能修改传入的ComposerJSON，并且应该按确定的顺序返回问题，例如按包名排序
+//   - 可以自动修复的问题应该填写Finding.Fix，见Fix
+//
+// 示例:
+//
+//	type noAbandoned struct{}
+//
+//	func (noAbandoned) ID() string              { return "no-abandoned" }
+//	func (noAbandoned) Severity() lint.Severity { return lint.SeverityWarning }
+//	func (noAbandoned) Check(c *composer.ComposerJSON) []lint.Finding {
+//		if c.Abandoned != nil {
+//			return []lint.Finding{{Pointer: "/abandoned", Message: "package is marked as abandoned"}}
+//		}
+//		return nil
+//	}
+type Rule interface {
+	// ID 返回规则的唯一标识
+	ID() string
+
+	// Severity 返回规则的默认严重程度，可以被配置覆盖
+	Severity() Severity
+
+	// Check 检查composer.json，返回发现的问题
+	Check(c *composer.ComposerJSON) []Finding
+}
+
+// Configurable 由可以从配置文件读取选项的规则实现
+type Configurable interface {
+	Rule
+
+	// Configure 返回应用了选项的规则副本，不能修改规则本身
+	//
+	// options是配置文件中规则的"options"值；选项无效时返回包装了ErrInvalidConfig的错误。
+	Configure(options json.RawMessage) (Rule, error)
+}
+
+// LockAware 由需要读取composer.lock的规则实现，例如NoWildcardRequire用锁定的版本生成修复
+type LockAware interface {
+	Rule
+
+	// WithLock 返回使用锁文件l的规则副本，不能修改规则本身
+	WithLock(l *lock.Lock) Rule
+}
+
+// activeRule 是启用的规则和它生效的严重程度
+type activeRule struct {
+	rule     Rule
+	severity Severity
+}
+
+// Linter 按配置运行一组规则
+type Linter struct {
+	rules []activeRule
+}
+
+// New 按配置从注册表中选出启用的规则
+//
+// 参数:
+//   - registry: 可用的规则，通常是DefaultRegistry()
+//   - cfg: 配置，为nil时使用所有规则的默认设置：启用除RegisterOptIn注册的规则外的所有规则
+//
+// 返回:
+//   - *Linter: 创建的Linter
+//   - error: 配置引用了未注册的规则时返回包装了ErrUnknownRule的错误；严重程度无效、
+//     为不支持选项的规则设置了选项或选项无效时返回包装了ErrInvalidConfig的错误
+func New(registry *Registry, cfg *Config) (*Linter, error) {
+	var settings map[string]RuleConfig
+	if cfg != nil {
+		settings = cfg.Rules
+	}
+	var unknown []string
+	for id := range settings {
+		if _, ok := registry.Rule(id); !ok {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownRule, strings.Join(unknown, ", "))
+	}
+
+	l := &Linter{}
+	for _, rule := range registry.Rules() {
+		rc, configured := settings[rule.ID()]
+		if rc.Enabled != nil && !*rc.Enabled {
+			continue
+		}
+		if !configured && registry.OptIn(rule.ID()) {
+			continue
+		}
+
+		severity := rule.Severity()
+		if rc.Severity != "" {
+			if !rc.Severity.valid() {
+				return nil, fmt.Errorf("%w: rule %s: unknown severity %q", ErrInvalidConfig, rule.ID(), rc.Severity)
+			}
+			severity = rc.Severity
+		}
+
+		if len(rc.Options) > 0 {
+			configurable, ok := rule.(Configurable)
+			if !ok {
+				return nil, fmt.Errorf("%w: rule %s does not accept options", ErrInvalidConfig, rule.ID())
+			}
+			configured, err := configurable.Configure(rc.Options)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: %w", rule.ID(), err)
+			}
+			rule = configured
+		}
+
+		l.rules = append(l.rules, activeRule{rule: rule, severity: severity})
+	}
+	return l, nil
+}
+
+// Rules 返回启用的规则的ID，按注册顺序排列
+func (l *Linter) Rules() []string {
+	ids := make([]string, len(l.rules))
+	for i, r := range l.rules {
+		ids[i] = r.rule.ID()
+	}
+	return ids
+}
+
+// WithLock 返回使用锁文件的Linter副本，实现了LockAware的规则会绑定到锁文件
+//
+// 参数:
+//   - lk: 解析后的composer.lock
+//
+// 返回:
+//   - *Linter: 新的Linter，原Linter不受影响
+//
+// 示例:
+//
+//	lk, err := lock.ParseFile("composer.lock")
+//	if err == nil {
+//		linter = linter.WithLock(lk)
+//	}
+func (l *Linter) WithLock(lk *lock.Lock) *Linter {
+	bound := &Linter{rules: make([]activeRule, len(l.rules))}
+	for i, r := range l.rules {
+		if aware, ok := r.rule.(LockAware); ok {
+			r.rule = aware.WithLock(lk)
+		}
+		bound.rules[i] = r
+	}
+	return bound
+}
+
+// Lint 对composer.json运行所有启用的规则
+//
+// 参数:
+//   - c: 要检查的composer.json
+//
+// 返回:
+//   - Findings: 发现的问题，按严重程度排列；同一严重程度内按规则的注册顺序和规则返回的顺序排列
+func (l *Linter) Lint(c *composer.ComposerJSON) Findings {
+	var findings Findings
+	for _, r := range l.rules {
+		for _, f := range r.rule.Check(c) {
+			f.RuleID = r.rule.ID()
+			f.Severity = r.severity
+			findings = append(findings, f)
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity.rank() < findings[j].Severity.rank()
+	})
+	return findings
+}
@@ -0,0 +1,138 @@
+package lint
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer"
+)
+
+// parse 解析测试用的composer.json
+func parse(t *testing.T, s string) *composer.ComposerJSON {
+	t.Helper()
+	c, err := composer.ParseString(s)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+	return c
+}
+
+// noDescription 是测试用的自定义规则
+type noDescription struct{}
+
+func (noDescription) ID() string         { return "require-description" }
+func (noDescription) Severity() Severity { return SeverityInfo }
+func (noDescription) Check(c *composer.ComposerJSON) []Finding {
+	if c.Description == "" {
+		return []Finding{{Pointer: "/description", Message: "description is missing"}}
+	}
+	return nil
+}
+
+func TestLinter_Lint(t *testing.T) {
+	registry := DefaultRegistry()
+	registry.MustRegister(noDescription{})
+
+	cfg, err := ParseConfig([]byte(`{
+    "rules": {
+        "allowed-vendor": {"options": {"vendors": ["acme"]}},
+        "php-constraint": {"severity": "warning", "options": {"constraint": "^8.2"}},
+        "no-dev-master": "off"
+    }
+}`))
+	if err != nil {
+		t.Fatalf("ParseConfig() returned unexpected error: %v", err)
+	}
+	linter, err := New(registry, cfg)
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+	if got, want := linter.Rules(), []string{"allowed-vendor", "php-constraint", "no-wildcard-require", "require-description"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Rules() = %v, want %v", got, want)
+	}
+
+	findings := linter.Lint(parse(t, `{
+    "name": "foo/app",
+    "require": {
+        "php": "^8.1",
+        "psr/log": "*",
+        "acme/lib": "dev-master"
+    }
+}`))
+	want := Findings{
+		{RuleID: "allowed-vendor", Severity: SeverityError, Pointer: "/name", Message: `vendor "foo" is not allowed, expected acme`},
+		{RuleID: "no-wildcard-require", Severity: SeverityError, Pointer: "/require/psr~1log", Message: `psr/log uses the wildcard constraint "*", require a version range instead`},
+		{RuleID: "php-constraint", Severity: SeverityWarning, Pointer: "/require/php", Message: `php constraint "^8.1" allows versions outside ^8.2`},
+		{RuleID: "require-description", Severity: SeverityInfo, Pointer: "/description", Message: "description is missing"},
+	}
+	if !reflect.DeepEqual(findings, want) {
+		t.Errorf("Lint() = %v, want %v", findings, want)
+	}
+	if !findings.HasErrors() || len(findings.Filter(SeverityWarning)) != 1 {
+		t.Errorf("HasErrors() = %v, Filter(warning) = %v", findings.HasErrors(), findings.Filter(SeverityWarning))
+	}
+	if got := findings[0].String(); got != `error /name: vendor "foo" is not allowed, expected acme (allowed-vendor)` {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestNew_Defaults(t *testing.T) {
+	linter, err := New(DefaultRegistry(), nil)
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+	findings := linter.Lint(parse(t, `{"name": "foo/app", "require": {"php": ">=7.4", "ext-json": "*", "acme/lib": "dev-master"}}`))
+	if len(findings) != 1 || findings[0].RuleID != "no-dev-master" {
+		t.Errorf("Lint() = %v, want only the no-dev-master finding", findings)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name string
+		cfg  *Config
+		want error
+	}{
+		{"unknown rule", &Config{Rules: map[string]RuleConfig{"no-such-rule": {}}}, ErrUnknownRule},
+		{"invalid severity", &Config{Rules: map[string]RuleConfig{"no-dev-master": {Severity: "fatal"}}}, ErrInvalidConfig},
+		{"options for a rule without options", &Config{Rules: map[string]RuleConfig{"no-dev-master": {Options: []byte(`{}`)}}}, ErrInvalidConfig},
+		{"unknown option", &Config{Rules: map[string]RuleConfig{"allowed-vendor": {Options: []byte(`{"vendor": "acme"}`)}}}, ErrInvalidConfig},
+		{"invalid constraint", &Config{Rules: map[string]RuleConfig{"php-constraint": {Options: []byte(`{"constraint": "^^8"}`)}}}, ErrInvalidConfig},
+		{"disabled rule with bad options", &Config{Rules: map[string]RuleConfig{"no-dev-master": {Enabled: &disabled, Options: []byte(`{}`)}}}, nil},
+	}
+	for _, tt := range tests {
+		_, err := New(DefaultRegistry(), tt.cfg)
+		if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("%s: New() error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestNew_OptInRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   []string
+	}{
+		{"not configured", `{}`, []string{"allowed-vendor", "no-dev-master", "php-constraint", "no-wildcard-require"}},
+		{"severity shorthand", `{"rules": {"sorted-packages": "info"}}`, []string{"allowed-vendor", "no-dev-master", "php-constraint", "no-wildcard-require", "sorted-packages"}},
+		{"options", `{"rules": {"dev-package-in-require": {"options": {"packages": ["phpunit/phpunit"]}}}}`, []string{"allowed-vendor", "no-dev-master", "php-constraint", "no-wildcard-require", "dev-package-in-require"}},
+		{"enabled", `{"rules": {"license-list": {"enabled": true}}}`, []string{"allowed-vendor", "no-dev-master", "php-constraint", "no-wildcard-require", "license-list"}},
+		{"off", `{"rules": {"lowercase-name": "off"}}`, []string{"allowed-vendor", "no-dev-master", "php-constraint", "no-wildcard-require"}},
+	}
+	for _, tt := range tests {
+		cfg, err := ParseConfig([]byte(tt.config))
+		if err != nil {
+			t.Fatalf("%s: ParseConfig() returned unexpected error: %v", tt.name, err)
+		}
+		linter, err := New(DefaultRegistry(), cfg)
+		if err != nil {
+			t.Fatalf("%s: New() returned unexpected error: %v", tt.name, err)
+		}
+		if got := linter.Rules(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Rules() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 错误定义
+var (
+	// ErrDuplicateRule 表示注册的规则ID已经存在
+	ErrDuplicateRule = errors.New("duplicate lint rule")
+
+	// ErrUnknownRule 表示配置引用了未注册的规则
+	ErrUnknownRule = errors.New("unknown lint rule")
+
+	// ErrInvalidConfig 表示配置文件或规则选项无效
+	ErrInvalidConfig = errors.New("invalid lint config")
+)
+
+// Registry 是可用规则的注册表，按注册顺序保存规则
+type Registry struct {
+	rules []Rule
+	index map[string]Rule
+
+	// optIn 默认不启用的规则
+	optIn map[string]bool
+}
+
+// NewRegistry 创建空的注册表
+//
+// 示例:
+//
+//	registry := lint.NewRegistry()
+//	registry.MustRegister(lint.NoDevMaster{})
+func NewRegistry() *Registry {
+	return &Registry{index: make(map[string]Rule), optIn: make(map[string]bool)}
+}
+
+// DefaultRegistry 创建包含所有内置规则的注册表
+//
+// 内置规则:
+//   - allowed-vendor: 包名的vendor必须在允许的列表中，选项{"vendors": ["acme"]}
+//   - no-dev-master: require和require-dev中不能使用dev-master约束
+//   - php-constraint: require中的php约束必须落在指定的约束内，选项{"constraint": "^8.2"}
+//   - no-wildcard-require: require中不能使用"*"约束，选项{"include-dev": true, "include-platform": true}；
+//     绑定composer.lock后可以修复为锁定版本的^约束
+//
+// 以下规则默认不启用，需要在配置文件中设置（如"sorted-packages": "info"）:
+//   - lowercase-name: 包名必须小写，可以修复
+//   - dev-package-in-require: phpunit等开发包必须放在require-dev中，选项{"packages": ["phpunit/phpunit"]}，可以修复
+//   - sorted-packages: require和require-dev中的包必须排序，可以修复
+//   - no-empty-sections: 不能有空对象或空数组的顶层键，可以修复
+//   - license-list: license必须写成数组，可以修复
+//
+// 每次调用返回新的注册表，可以继续注册自定义规则。
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.MustRegister(AllowedVendor{})
+	r.MustRegister(NoDevMaster{})
+	r.MustRegister(PHPConstraint{})
+	r.MustRegister(NoWildcardRequire{})
+	r.MustRegisterOptIn(LowercaseName{})
+	r.MustRegisterOptIn(DevPackageInRequire{})
+	r.MustRegisterOptIn(SortedPackages{})
+	r.MustRegisterOptIn(NoEmptySections{})
+	r.MustRegisterOptIn(LicenseList{})
+	return r
+}
+
+// Register 注册规则
+//
+// 参数:
+//   - rule: 要注册的规则
+//
+// 返回:
+//   - error: 如果已经注册了相同ID的规则，返回包装了ErrDuplicateRule的错误
+func (r *Registry) Register(rule Rule) error {
+	if _, ok := r.index[rule.ID()]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateRule, rule.ID())
+	}
+	r.rules = append(r.rules, rule)
+	r.index[rule.ID()] = rule
+	return nil
+}
+
+// MustRegister 注册规则，ID重复时panic
+func (r *Registry) MustRegister(rule Rule) {
+	if err := r.Register(rule); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterOptIn 注册默认不启用的规则，配置中设置了该规则并且没有禁用时才启用
+//
+// 适用于团队之间做法不一的规则，如包的排序，避免升级后在没有修改配置的情况下出现新的问题。
+//
+// 返回:
+//   - error: 如果已经注册了相同ID的规则，返回包装了ErrDuplicateRule的错误
+func (r *Registry) RegisterOptIn(rule Rule) error {
+	if err := r.Register(rule); err != nil {
+		return err
+	}
+	r.optIn[rule.ID()] = true
+	return nil
+}
+
+// MustRegisterOptIn 注册默认不启用的规则，ID重复时panic
+func (r *Registry) MustRegisterOptIn(rule Rule) {
+	if err := r.RegisterOptIn(rule); err != nil {
+		panic(err)
+	}
+}
+
+// OptIn 判断规则是否默认不启用
+func (r *Registry) OptIn(id string) bool {
+	return r.optIn[id]
+}
+
+// Rule 按ID查找规则
+func (r *Registry) Rule(id string) (Rule, bool) {
+	rule, ok := r.index[id]
+	return rule, ok
+}
+
+// Rules 返回所有规则，按注册顺序排列
+func (r *Registry) Rules() []Rule {
+	return append([]Rule(nil), r.rules...)
+}
@@ -0,0 +1,81 @@
+package lint
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(NoDevMaster{}); err != nil {
+		t.Fatalf("Register() returned unexpected error: %v", err)
+	}
+	if err := r.Register(NoDevMaster{}); !errors.Is(err, ErrDuplicateRule) {
+		t.Errorf("Register() duplicate error = %v, want ErrDuplicateRule", err)
+	}
+	if rule, ok := r.Rule("no-dev-master"); !ok || rule.ID() != "no-dev-master" {
+		t.Errorf("Rule() = %v, %v", rule, ok)
+	}
+	if _, ok := r.Rule("allowed-vendor"); ok {
+		t.Error("Rule() found an unregistered rule")
+	}
+
+	rules := r.Rules()
+	rules[0] = nil
+	if r.Rules()[0] == nil {
+		t.Error("Rules() must return a copy")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustRegister() with a duplicate rule did not panic")
+		}
+	}()
+	r.MustRegister(NoDevMaster{})
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	var ids []string
+	for _, rule := range DefaultRegistry().Rules() {
+		ids = append(ids, rule.ID())
+	}
+	want := []string{
+		"allowed-vendor", "no-dev-master", "php-constraint", "no-wildcard-require",
+		"lowercase-name", "dev-package-in-require", "sorted-packages", "no-empty-sections", "license-list",
+	}
+	if len(ids) != len(want) {
+		t.Fatalf("DefaultRegistry() rules = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("DefaultRegistry() rules = %v, want %v", ids, want)
+		}
+	}
+}
+
+func TestRegistry_OptIn(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(NoDevMaster{})
+	if err := r.RegisterOptIn(SortedPackages{}); err != nil {
+		t.Fatalf("RegisterOptIn() returned unexpected error: %v", err)
+	}
+	if err := r.RegisterOptIn(NoDevMaster{}); !errors.Is(err, ErrDuplicateRule) {
+		t.Errorf("RegisterOptIn() error = %v, want ErrDuplicateRule", err)
+	}
+	if r.OptIn("no-dev-master") || !r.OptIn("sorted-packages") {
+		t.Errorf("OptIn() = %v, %v, want false, true", r.OptIn("no-dev-master"), r.OptIn("sorted-packages"))
+	}
+
+	var optIn []string
+	registry := DefaultRegistry()
+	for _, rule := range registry.Rules() {
+		if registry.OptIn(rule.ID()) {
+			optIn = append(optIn, rule.ID())
+		}
+	}
+	want := []string{"lowercase-name", "dev-package-in-require", "sorted-packages", "no-empty-sections", "license-list"}
+	if !reflect.DeepEqual(optIn, want) {
+		t.Errorf("DefaultRegistry() opt-in rules = %v, want %v", optIn, want)
+	}
+}
@@ -0,0 +1,512 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/constraint"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/dependency"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/validation"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/version"
+)
+
+// AllowedVendor 要求包名的vendor在允许的列表中，不区分大小写
+//
+// 配置文件中的选项: {"vendors": ["acme"]}。没有设置Vendors或composer.json没有name时不检查。
+type AllowedVendor struct {
+	// Vendors 允许的vendor，如["acme"]
+	Vendors []string `json:"vendors"`
+}
+
+// ID 实现Rule接口
+func (AllowedVendor) ID() string { return "allowed-vendor" }
+
+// Severity 实现Rule接口
+func (AllowedVendor) Severity() Severity { return SeverityError }
+
+// Configure 实现Configurable接口
+func (r AllowedVendor) Configure(options json.RawMessage) (Rule, error) {
+	configured := AllowedVendor{Vendors: append([]string(nil), r.Vendors...)}
+	if err := decodeOptions(options, &configured); err != nil {
+		return nil, err
+	}
+	return configured, nil
+}
+
+// Check 实现Rule接口
+func (r AllowedVendor) Check(c *composer.ComposerJSON) []Finding {
+	if len(r.Vendors) == 0 || c.Name == "" {
+		return nil
+	}
+	vendor := strings.SplitN(c.Name, "/", 2)[0]
+	for _, allowed := range r.Vendors {
+		if strings.EqualFold(vendor, allowed) {
+			return nil
+		}
+	}
+	return []Finding{{
+		Pointer: "/name",
+		Message: fmt.Sprintf("vendor %q is not allowed, expected %s", vendor, strings.Join(r.Vendors, " or ")),
+	}}
+}
+
+// NoDevMaster 禁止在require和require-dev中依赖master分支，如"dev-master"、"dev-master#abc123"、
+// "dev-master as 1.0.x-dev"
+type NoDevMaster struct{}
+
+// ID 实现Rule接口
+func (NoDevMaster) ID() string { return "no-dev-master" }
+
+// Severity 实现Rule接口
+func (NoDevMaster) Severity() Severity { return SeverityError }
+
+// Check 实现Rule接口
+func (NoDevMaster) Check(c *composer.ComposerJSON) []Finding {
+	var findings []Finding
+	for _, section := range requireSections(c, true) {
+		for _, name := range sortedKeys(section.require) {
+			if referencesDevMaster(section.require[name]) {
+				findings = append(findings, Finding{
+					Pointer: validation.Pointer(section.key, name),
+					Message: fmt.Sprintf("%s requires the master branch (%s), require a tagged release instead", name, section.require[name]),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// referencesDevMaster 判断约束中是否有dev-master，忽略#ref和@稳定性标志
+func referencesDevMaster(c string) bool {
+	fields := strings.FieldsFunc(c, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ',' || r == '|'
+	})
+	for _, field := range fields {
+		field = strings.SplitN(field, "#", 2)[0]
+		field = strings.SplitN(field, "@", 2)[0]
+		if strings.EqualFold(field, "dev-master") {
+			return true
+		}
+	}
+	return false
+}
+
+// PHPConstraint 要求require中的php约束存在并且落在指定的约束内
+//
+// 例如Constraint为"^8.2"时，"^8.3"和"~8.2.1"符合，"^8.1"和">=8.2"不符合。
+// 配置文件中的选项: {"constraint": "^8.2"}。没有设置Constraint时不检查。
+type PHPConstraint struct {
+	// Constraint php约束必须满足的范围，如"^8.2"
+	Constraint string `json:"constraint"`
+}
+
+// ID 实现Rule接口
+func (PHPConstraint) ID() string { return "php-constraint" }
+
+// Severity 实现Rule接口
+func (PHPConstraint) Severity() Severity { return SeverityError }
+
+// Configure 实现Configurable接口，约束无效时返回错误
+func (r PHPConstraint) Configure(options json.RawMessage) (Rule, error) {
+	configured := r
+	if err := decodeOptions(options, &configured); err != nil {
+		return nil, err
+	}
+	if _, err := constraint.Parse(configured.Constraint); configured.Constraint != "" && err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return configured, nil
+}
+
+// Check 实现Rule接口
+func (r PHPConstraint) Check(c *composer.ComposerJSON) []Finding {
+	if r.Constraint == "" {
+		return nil
+	}
+	expected, err := constraint.Parse(r.Constraint)
+	if err != nil {
+		return []Finding{{Message: fmt.Sprintf("invalid configured php constraint: %v", err)}}
+	}
+
+	pointer := validation.Pointer("require", "php")
+	actual, ok := c.Require["php"]
+	if !ok {
+		return []Finding{{Pointer: pointer, Message: fmt.Sprintf("php requirement is missing, expected %s", r.Constraint)}}
+	}
+	parsed, err := constraint.Parse(actual)
+	if err != nil {
+		return []Finding{{Pointer: pointer, Message: fmt.Sprintf("invalid php constraint %q", actual)}}
+	}
+	if !constraint.IsSubsetOf(parsed, expected) {
+		return []Finding{{Pointer: pointer, Message: fmt.Sprintf("php constraint %q allows versions outside %s", actual, r.Constraint)}}
+	}
+	return nil
+}
+
+// NoWildcardRequire 禁止在require中使用匹配所有版本的约束，如"*"、"*@dev"
+//
+// 默认不检查require-dev和平台包（"ext-json": "*"是声明扩展依赖的惯用写法）。
+// 配置文件中的选项: {"include-dev": true, "include-platform": true}。
+//
+// 通过Linter.WithLock绑定composer.lock后，锁定了稳定版本的包带有修复：把"*"替换为锁定版本的^约束，
+// 如锁定v2.4.1时替换为"^2.4.1"。
+type NoWildcardRequire struct {
+	// IncludeDev 同时检查require-dev
+	IncludeDev bool `json:"include-dev"`
+
+	// IncludePlatform 同时检查php、ext-*、lib-*等平台包
+	IncludePlatform bool `json:"include-platform"`
+
+	// lock 用于生成修复的锁文件
+	lock *lock.Lock
+}
+
+// ID 实现Rule接口
+func (NoWildcardRequire) ID() string { return "no-wildcard-require" }
+
+// Severity 实现Rule接口
+func (NoWildcardRequire) Severity() Severity { return SeverityError }
+
+// Configure 实现Configurable接口
+func (r NoWildcardRequire) Configure(options json.RawMessage) (Rule, error) {
+	configured := r
+	if err := decodeOptions(options, &configured); err != nil {
+		return nil, err
+	}
+	return configured, nil
+}
+
+// WithLock 实现LockAware接口
+func (r NoWildcardRequire) WithLock(l *lock.Lock) Rule {
+	r.lock = l
+	return r
+}
+
+// Check 实现Rule接口
+func (r NoWildcardRequire) Check(c *composer.ComposerJSON) []Finding {
+	var findings []Finding
+	for _, section := range requireSections(c, r.IncludeDev) {
+		for _, name := range sortedKeys(section.require) {
+			if !r.IncludePlatform && dependency.IsPlatformPackage(name) {
+				continue
+			}
+			if isWildcard(section.require[name]) {
+				findings = append(findings, Finding{
+					Pointer: validation.Pointer(section.key, name),
+					Message: fmt.Sprintf("%s uses the wildcard constraint %q, require a version range instead", name, section.require[name]),
+					Fix:     r.fix(section.key, name),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// fix 返回把包的约束替换为锁定版本的^约束的修复，没有锁文件或包没有锁定稳定版本时返回nil
+func (r NoWildcardRequire) fix(key, name string) *Fix {
+	if r.lock == nil {
+		return nil
+	}
+	pkg, _ := r.lock.FindPackage(name)
+	if pkg == nil {
+		return nil
+	}
+	caret := caretConstraint(pkg.Version)
+	if caret == "" {
+		return nil
+	}
+	return &Fix{
+		Description: fmt.Sprintf("require %s %s", name, caret),
+		Apply: func(c *composer.ComposerJSON) error {
+			if key == "require-dev" {
+				if !isWildcard(c.RequireDev[name]) {
+					return nil
+				}
+				return c.AddDevDependency(name, caret)
+			}
+			if !isWildcard(c.Require[name]) {
+				return nil
+			}
+			return c.AddDependency(name, caret)
+		},
+	}
+}
+
+// caretConstraint 返回锁定版本对应的^约束，如"v2.4.1"返回"^2.4.1"；分支和不稳定版本返回空字符串
+func caretConstraint(locked string) string {
+	normalized, err := version.Normalize(locked)
+	if err != nil || version.IsBranch(normalized) || version.ParseStability(normalized) != version.StabilityStable {
+		return ""
+	}
+	parts := strings.Split(normalized, ".")
+	for len(parts) > 3 && parts[len(parts)-1] == "0" {
+		parts = parts[:len(parts)-1]
+	}
+	return "^" + strings.Join(parts, ".")
+}
+
+// isWildcard 判断约束是否匹配所有版本
+func isWildcard(c string) bool {
+	parsed, err := constraint.Parse(c)
+	if err != nil {
+		return false
+	}
+	_, ok := parsed.(constraint.MatchAll)
+	return ok
+}
+
+// LowercaseName 要求包名全部小写，Packagist和Composer 2都不接受大写的包名
+type LowercaseName struct{}
+
+// ID 实现Rule接口
+func (LowercaseName) ID() string { return "lowercase-name" }
+
+// Severity 实现Rule接口
+func (LowercaseName) Severity() Severity { return SeverityWarning }
+
+// Check 实现Rule接口，带有把包名改为小写的修复
+func (LowercaseName) Check(c *composer.ComposerJSON) []Finding {
+	lower := strings.ToLower(c.Name)
+	if c.Name == lower {
+		return nil
+	}
+	return []Finding{{
+		Pointer: "/name",
+		Message: fmt.Sprintf("package name %q should be lowercase", c.Name),
+		Fix: &Fix{
+			Description: fmt.Sprintf("rename package to %s", lower),
+			Apply: func(c *composer.ComposerJSON) error {
+				c.Name = strings.ToLower(c.Name)
+				return nil
+			},
+		},
+	}}
+}
+
+// defaultDevPackages 是DevPackageInRequire默认识别的只用于开发和测试的包
+var defaultDevPackages = []string{
+	"phpunit/phpunit",
+	"brianium/paratest",
+	"pestphp/pest",
+	"pestphp/pest-plugin-*",
+	"mockery/mockery",
+	"phpspec/*",
+	"behat/behat",
+	"codeception/codeception",
+	"fakerphp/faker",
+	"mikey179/vfsstream",
+	"symfony/phpunit-bridge",
+	"infection/infection",
+	"phpstan/*",
+	"vimeo/psalm",
+	"squizlabs/php_codesniffer",
+	"friendsofphp/php-cs-fixer",
+	"rector/rector",
+	"roave/security-advisories",
+}
+
+// DevPackageInRequire 要求测试框架、静态分析工具等只用于开发的包放在require-dev中
+//
+// 配置文件中的选项: {"packages": ["phpunit/phpunit", "phpstan/*"]}，支持path.Match的通配符，
+// 设置后替换默认列表defaultDevPackages。
+type DevPackageInRequire struct {
+	// Packages 只用于开发的包名或通配符，为空时使用默认列表
+	Packages []string `json:"packages"`
+}
+
+// ID 实现Rule接口
+func (DevPackageInRequire) ID() string { return "dev-package-in-require" }
+
+// Severity 实现Rule接口
+func (DevPackageInRequire) Severity() Severity { return SeverityWarning }
+
+// Configure 实现Configurable接口，通配符无效时返回错误
+func (r DevPackageInRequire) Configure(options json.RawMessage) (Rule, error) {
+	configured := DevPackageInRequire{Packages: append([]string(nil), r.Packages...)}
+	if err := decodeOptions(options, &configured); err != nil {
+		return nil, err
+	}
+	for _, pattern := range configured.Packages {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%w: invalid package pattern %q", ErrInvalidConfig, pattern)
+		}
+	}
+	return configured, nil
+}
+
+// Check 实现Rule接口，带有把包移到require-dev的修复
+func (r DevPackageInRequire) Check(c *composer.ComposerJSON) []Finding {
+	var findings []Finding
+	for _, name := range sortedKeys(c.Require) {
+		if !r.matches(name) {
+			continue
+		}
+		name := name
+		findings = append(findings, Finding{
+			Pointer: validation.Pointer("require", name),
+			Message: fmt.Sprintf("%s is a development package, require it in require-dev instead", name),
+			Fix: &Fix{
+				Description: fmt.Sprintf("move %s to require-dev", name),
+				Apply: func(c *composer.ComposerJSON) error {
+					return moveToDev(c, name)
+				},
+			},
+		})
+	}
+	return findings
+}
+
+// matches 判断包名是否匹配开发包列表，不区分大小写
+func (r DevPackageInRequire) matches(name string) bool {
+	patterns := r.Packages
+	if len(patterns) == 0 {
+		patterns = defaultDevPackages
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// moveToDev 把包从require移到require-dev，包名改为小写；require-dev中已有该包时保留require-dev中的约束
+func moveToDev(c *composer.ComposerJSON, name string) error {
+	version, ok := c.Require[name]
+	if !ok {
+		return nil
+	}
+	c.RemoveDependency(name)
+	for existing := range c.RequireDev {
+		if strings.EqualFold(existing, name) {
+			return nil
+		}
+	}
+	if c.RequireDev == nil {
+		c.RequireDev = make(map[string]string)
+	}
+	return c.AddDevDependency(strings.ToLower(name), version)
+}
+
+// SortedPackages 要求require和require-dev中的包按Composer的sort-packages顺序排列：
+// php、平台包、普通包，各组内按名称排序
+type SortedPackages struct{}
+
+// ID 实现Rule接口
+func (SortedPackages) ID() string { return "sorted-packages" }
+
+// Severity 实现Rule接口
+func (SortedPackages) Severity() Severity { return SeverityInfo }
+
+// Check 实现Rule接口，带有排序的修复
+func (SortedPackages) Check(c *composer.ComposerJSON) []Finding {
+	var findings []Finding
+	for _, key := range []string{"require", "require-dev"} {
+		if c.LinksSorted(key) {
+			continue
+		}
+		key := key
+		findings = append(findings, Finding{
+			Pointer: validation.Pointer(key),
+			Message: fmt.Sprintf("packages in %s are not sorted", key),
+			Fix: &Fix{
+				Description: fmt.Sprintf("sort %s", key),
+				Apply: func(c *composer.ComposerJSON) error {
+					c.SortLinks(key)
+					return nil
+				},
+			},
+		})
+	}
+	return findings
+}
+
+// NoEmptySections 禁止值为空对象或空数组的顶层键，如"require-dev": {}
+type NoEmptySections struct{}
+
+// ID 实现Rule接口
+func (NoEmptySections) ID() string { return "no-empty-sections" }
+
+// Severity 实现Rule接口
+func (NoEmptySections) Severity() Severity { return SeverityInfo }
+
+// Check 实现Rule接口，带有删除空键的修复
+func (NoEmptySections) Check(c *composer.ComposerJSON) []Finding {
+	var findings []Finding
+	for _, key := range c.EmptySections() {
+		key := key
+		findings = append(findings, Finding{
+			Pointer: validation.Pointer(key),
+			Message: fmt.Sprintf("%s is empty", key),
+			Fix: &Fix{
+				Description: fmt.Sprintf("remove %s", key),
+				Apply: func(c *composer.ComposerJSON) error {
+					c.RemoveEmptySection(key)
+					return nil
+				},
+			},
+		})
+	}
+	return findings
+}
+
+// LicenseList 要求license写成数组，如["MIT"]，使单个和多个许可证的写法一致
+type LicenseList struct{}
+
+// ID 实现Rule接口
+func (LicenseList) ID() string { return "license-list" }
+
+// Severity 实现Rule接口
+func (LicenseList) Severity() Severity { return SeverityInfo }
+
+// Check 实现Rule接口，带有把字符串改为数组的修复
+func (LicenseList) Check(c *composer.ComposerJSON) []Finding {
+	license, ok := c.License.(string)
+	if !ok {
+		return nil
+	}
+	return []Finding{{
+		Pointer: "/license",
+		Message: fmt.Sprintf("license %q should be a list", license),
+		Fix: &Fix{
+			Description: fmt.Sprintf("change license to [%q]", license),
+			Apply: func(c *composer.ComposerJSON) error {
+				if license, ok := c.License.(string); ok {
+					c.License = []string{license}
+				}
+				return nil
+			},
+		},
+	}}
+}
+
+// requireSection 是require或require-dev以及它在composer.json中的键名
+type requireSection struct {
+	key     string
+	require map[string]string
+}
+
+// requireSections 返回require，includeDev为true时还返回require-dev
+func requireSections(c *composer.ComposerJSON, includeDev bool) []requireSection {
+	sections := []requireSection{{"require", c.Require}}
+	if includeDev {
+		sections = append(sections, requireSection{"require-dev", c.RequireDev})
+	}
+	return sections
+}
+
+// sortedKeys 返回排序后的map键
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,343 @@
+package lint
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+)
+
+func TestAllowedVendor(t *testing.T) {
+	tests := []struct {
+		rule AllowedVendor
+		json string
+		want []Finding
+	}{
+		{AllowedVendor{}, `{"name": "foo/app"}`, nil},
+		{AllowedVendor{Vendors: []string{"acme"}}, `{"description": "no name"}`, nil},
+		{AllowedVendor{Vendors: []string{"acme"}}, `{"name": "acme/app"}`, nil},
+		{AllowedVendor{Vendors: []string{"acme"}}, `{"name": "ACME/app"}`, nil},
+		{AllowedVendor{Vendors: []string{"acme", "acme-labs"}}, `{"name": "foo/app"}`,
+			[]Finding{{Pointer: "/name", Message: `vendor "foo" is not allowed, expected acme or acme-labs`}}},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Check(parse(t, tt.json)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v.Check(%s) = %v, want %v", tt.rule, tt.json, got, tt.want)
+		}
+	}
+
+	original := AllowedVendor{Vendors: []string{"acme"}}
+	configured, err := original.Configure([]byte(`{"vendors": ["other"]}`))
+	if err != nil {
+		t.Fatalf("Configure() returned unexpected error: %v", err)
+	}
+	if configured.(AllowedVendor).Vendors[0] != "other" || original.Vendors[0] != "acme" {
+		t.Errorf("Configure() = %+v, original = %+v", configured, original)
+	}
+}
+
+func TestNoDevMaster(t *testing.T) {
+	c := parse(t, `{
+    "require": {
+        "acme/a": "dev-master",
+        "acme/b": "dev-master#abc123",
+        "acme/c": "dev-master as 1.0.x-dev",
+        "acme/d": "^1.0 || DEV-MASTER@dev",
+        "acme/e": "dev-main",
+        "acme/f": "dev-mastery"
+    },
+    "require-dev": {
+        "acme/g": "dev-master"
+    }
+}`)
+	var pointers []string
+	for _, f := range (NoDevMaster{}).Check(c) {
+		pointers = append(pointers, f.Pointer)
+	}
+	want := []string{"/require/acme~1a", "/require/acme~1b", "/require/acme~1c", "/require/acme~1d", "/require-dev/acme~1g"}
+	if !reflect.DeepEqual(pointers, want) {
+		t.Errorf("Check() pointers = %v, want %v", pointers, want)
+	}
+}
+
+func TestPHPConstraint(t *testing.T) {
+	tests := []struct {
+		json string
+		want string
+	}{
+		{`{"require": {"php": "^8.2"}}`, ""},
+		{`{"require": {"php": "^8.3"}}`, ""},
+		{`{"require": {"php": "~8.2.1"}}`, ""},
+		{`{"require": {"php": "^8.1"}}`, `php constraint "^8.1" allows versions outside ^8.2`},
+		{`{"require": {"php": ">=8.2"}}`, `php constraint ">=8.2" allows versions outside ^8.2`},
+		{`{"require": {"php": "^^8"}}`, `invalid php constraint "^^8"`},
+		{`{"require": {"psr/log": "^3.0"}}`, "php requirement is missing, expected ^8.2"},
+	}
+	rule := PHPConstraint{Constraint: "^8.2"}
+	for _, tt := range tests {
+		got := rule.Check(parse(t, tt.json))
+		switch {
+		case tt.want == "" && len(got) != 0:
+			t.Errorf("Check(%s) = %v, want no findings", tt.json, got)
+		case tt.want != "" && (len(got) != 1 || got[0].Message != tt.want || got[0].Pointer != "/require/php"):
+			t.Errorf("Check(%s) = %v, want %q", tt.json, got, tt.want)
+		}
+	}
+
+	if got := (PHPConstraint{}).Check(parse(t, `{}`)); got != nil {
+		t.Errorf("Check() without a constraint = %v, want nil", got)
+	}
+}
+
+func TestNoWildcardRequire(t *testing.T) {
+	c := parse(t, `{
+    "require": {
+        "php": "*",
+        "ext-json": "*",
+        "psr/log": "*",
+        "acme/lib": "*@dev",
+        "acme/other": "^1.0"
+    },
+    "require-dev": {
+        "phpunit/phpunit": "*"
+    }
+}`)
+	tests := []struct {
+		rule NoWildcardRequire
+		want []string
+	}{
+		{NoWildcardRequire{}, []string{"/require/acme~1lib", "/require/psr~1log"}},
+		{NoWildcardRequire{IncludeDev: true}, []string{"/require/acme~1lib", "/require/psr~1log", "/require-dev/phpunit~1phpunit"}},
+		{NoWildcardRequire{IncludePlatform: true}, []string{"/require/acme~1lib", "/require/ext-json", "/require/php", "/require/psr~1log"}},
+	}
+	for _, tt := range tests {
+		var pointers []string
+		for _, f := range tt.rule.Check(c) {
+			pointers = append(pointers, f.Pointer)
+		}
+		if !reflect.DeepEqual(pointers, tt.want) {
+			t.Errorf("%+v.Check() pointers = %v, want %v", tt.rule, pointers, tt.want)
+		}
+	}
+}
+
+// applyAll 应用规则返回的所有修复并返回修复后的JSON
+func applyAll(t *testing.T, c *composer.ComposerJSON, findings []Finding) string {
+	t.Helper()
+	if _, err := ApplyFixes(c, findings); err != nil {
+		t.Fatalf("ApplyFixes() returned unexpected error: %v", err)
+	}
+	out, err := c.ToJSON(true)
+	if err != nil {
+		t.Fatalf("ToJSON() returned unexpected error: %v", err)
+	}
+	return out
+}
+
+func TestNoWildcardRequire_Fix(t *testing.T) {
+	c := parse(t, `{
+    "require": {
+        "acme/branch": "*",
+        "acme/missing": "*",
+        "psr/log": "*"
+    },
+    "require-dev": {
+        "phpunit/phpunit": "*"
+    }
+}`)
+	lk := &lock.Lock{
+		Packages: []lock.LockedPackage{
+			{Name: "acme/branch", Version: "dev-main"},
+			{Name: "PSR/Log", Version: "v3.0.2"},
+		},
+		PackagesDev: []lock.LockedPackage{
+			{Name: "phpunit/phpunit", Version: "10.5.1.0"},
+		},
+	}
+
+	if findings := (NoWildcardRequire{}).Check(c); len(Findings(findings).Fixable()) != 0 {
+		t.Errorf("Check() without a lock returned fixes: %v", findings)
+	}
+
+	rule := NoWildcardRequire{IncludeDev: true}.WithLock(lk)
+	findings := Findings(rule.Check(c)).Fixable()
+	var descriptions []string
+	for _, f := range findings {
+		descriptions = append(descriptions, f.Fix.Description)
+	}
+	want := []string{"require psr/log ^3.0.2", "require phpunit/phpunit ^10.5.1"}
+	if !reflect.DeepEqual(descriptions, want) {
+		t.Fatalf("fixes = %v, want %v", descriptions, want)
+	}
+
+	got := applyAll(t, c, findings)
+	wantJSON := `{
+    "require": {
+        "acme/branch": "*",
+        "acme/missing": "*",
+        "psr/log": "^3.0.2"
+    },
+    "require-dev": {
+        "phpunit/phpunit": "^10.5.1"
+    }
+}`
+	if got != wantJSON {
+		t.Errorf("fixed JSON = %s, want %s", got, wantJSON)
+	}
+}
+
+func TestCaretConstraint(t *testing.T) {
+	tests := map[string]string{
+		"v2.4.1":        "^2.4.1",
+		"1.0":           "^1.0.0",
+		"1.2.3.4":       "^1.2.3.4",
+		"2.0.0-beta1":   "",
+		"dev-main":      "",
+		"1.x-dev":       "",
+		"not a version": "",
+	}
+	for locked, want := range tests {
+		if got := caretConstraint(locked); got != want {
+			t.Errorf("caretConstraint(%q) = %q, want %q", locked, got, want)
+		}
+	}
+}
+
+func TestLowercaseName(t *testing.T) {
+	if got := (LowercaseName{}).Check(parse(t, `{"name": "acme/app"}`)); got != nil {
+		t.Errorf("Check() = %v, want nil", got)
+	}
+
+	c := parse(t, `{"name": "Acme/App"}`)
+	findings := LowercaseName{}.Check(c)
+	if len(findings) != 1 || findings[0].Pointer != "/name" || findings[0].Fix == nil {
+		t.Fatalf("Check() = %v, want a fixable /name finding", findings)
+	}
+	applyAll(t, c, findings)
+	if c.Name != "acme/app" {
+		t.Errorf("Name after fix = %q, want acme/app", c.Name)
+	}
+}
+
+func TestDevPackageInRequire(t *testing.T) {
+	c := parse(t, `{
+    "require": {
+        "php": "^8.2",
+        "PHPUnit/PHPUnit": "^10.5",
+        "phpstan/phpstan": "^1.10",
+        "psr/log": "^3.0"
+    },
+    "require-dev": {
+        "phpstan/phpstan": "^1.11"
+    }
+}`)
+	findings := DevPackageInRequire{}.Check(c)
+	var pointers []string
+	for _, f := range findings {
+		pointers = append(pointers, f.Pointer)
+	}
+	if want := []string{"/require/PHPUnit~1PHPUnit", "/require/phpstan~1phpstan"}; !reflect.DeepEqual(pointers, want) {
+		t.Fatalf("Check() pointers = %v, want %v", pointers, want)
+	}
+
+	got := applyAll(t, c, findings)
+	want := `{
+    "require": {
+        "php": "^8.2",
+        "psr/log": "^3.0"
+    },
+    "require-dev": {
+        "phpstan/phpstan": "^1.11",
+        "phpunit/phpunit": "^10.5"
+    }
+}`
+	if got != want {
+		t.Errorf("fixed JSON = %s, want %s", got, want)
+	}
+
+	custom, err := DevPackageInRequire{}.Configure([]byte(`{"packages": ["psr/*"]}`))
+	if err != nil {
+		t.Fatalf("Configure() returned unexpected error: %v", err)
+	}
+	if findings := custom.Check(parse(t, `{"require": {"psr/log": "^3.0", "phpunit/phpunit": "^10.5"}}`)); len(findings) != 1 || findings[0].Pointer != "/require/psr~1log" {
+		t.Errorf("configured Check() = %v, want only /require/psr~1log", findings)
+	}
+	if _, err := (DevPackageInRequire{}).Configure([]byte(`{"packages": ["["]}`)); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Configure() with an invalid pattern error = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestDevPackageInRequire_NoRequireDev(t *testing.T) {
+	c := parse(t, `{"require": {"mockery/mockery": "^1.6"}}`)
+	got := applyAll(t, c, DevPackageInRequire{}.Check(c))
+	want := `{
+    "require-dev": {
+        "mockery/mockery": "^1.6"
+    }
+}`
+	if got != want {
+		t.Errorf("fixed JSON = %s, want %s", got, want)
+	}
+}
+
+func TestSortedPackages(t *testing.T) {
+	if got := (SortedPackages{}).Check(parse(t, `{"require": {"php": "^8.2", "ext-json": "*", "acme/lib": "^1.0"}}`)); got != nil {
+		t.Errorf("Check() = %v, want nil", got)
+	}
+
+	c := parse(t, `{"require": {"psr/log": "^3.0", "php": "^8.2"}, "require-dev": {"b/b": "^1.0", "a/a": "^1.0"}}`)
+	findings := SortedPackages{}.Check(c)
+	if len(findings) != 2 || findings[0].Pointer != "/require" || findings[1].Pointer != "/require-dev" {
+		t.Fatalf("Check() = %v, want /require and /require-dev findings", findings)
+	}
+	got := applyAll(t, c, findings)
+	want := `{"require": {"php": "^8.2", "psr/log": "^3.0"}, "require-dev": {"a/a": "^1.0", "b/b": "^1.0"}}`
+	if got != want {
+		t.Errorf("fixed JSON = %s, want %s", got, want)
+	}
+}
+
+func TestNoEmptySections(t *testing.T) {
+	c := parse(t, `{
+    "name": "acme/app",
+    "keywords": [],
+    "require-dev": {},
+    "extra": {}
+}`)
+	findings := NoEmptySections{}.Check(c)
+	var pointers []string
+	for _, f := range findings {
+		pointers = append(pointers, f.Pointer)
+	}
+	if want := []string{"/keywords", "/require-dev", "/extra"}; !reflect.DeepEqual(pointers, want) {
+		t.Fatalf("Check() pointers = %v, want %v", pointers, want)
+	}
+	got := applyAll(t, c, findings)
+	want := `{
+    "name": "acme/app"
+}`
+	if got != want {
+		t.Errorf("fixed JSON = %s, want %s", got, want)
+	}
+}
+
+func TestLicenseList(t *testing.T) {
+	if got := (LicenseList{}).Check(parse(t, `{"license": ["MIT"]}`)); got != nil {
+		t.Errorf("Check() = %v, want nil", got)
+	}
+
+	c := parse(t, `{
+    "license": "MIT"
+}`)
+	got := applyAll(t, c, LicenseList{}.Check(c))
+	want := `{
+    "license": [
+        "MIT"
+    ]
+}`
+	if got != want {
+		t.Errorf("fixed JSON = %s, want %s", got, want)
+	}
+}
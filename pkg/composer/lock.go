@@ -0,0 +1,120 @@
+package composer
+
+import (
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+)
+
+// ParseLockFile 从文件路径解析composer.lock文件
+//
+// 参数:
+//   - filePath: composer.lock文件路径
+//
+// 返回:
+//   - *lock.Lock: 解析后的锁文件
+//   - error: 如果解析失败，返回错误；文件不存在时返回lock.ErrFileNotFound
+//
+// 示例:
+//
+//	l, err := composer.ParseLockFile("./composer.lock")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, pkg := range l.Packages {
+//		fmt.Printf("%s %s\n", pkg.Name, pkg.Version)
+//	}
+func ParseLockFile(filePath string) (*lock.Lock, error) {
+	return lock.ParseFile(filePath)
+}
+
+// ParseLockDir 在指定目录中查找并解析composer.lock文件
+//
+// 参数:
+//   - dir: 要查找composer.lock的目录路径
+//
+// 返回:
+//   - *lock.Lock: 解析后的锁文件
+//   - error: 如果解析失败，返回错误
+//
+// 示例:
+//
+//	l, err := composer.ParseLockDir("/path/to/php/project")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println("content-hash:", l.ContentHash)
+func ParseLockDir(dir string) (*lock.Lock, error) {
+	return lock.ParseDir(dir)
+}
+
+// ContentHash 计算composer.json当前内容的content-hash，算法与Composer一致
+//
+// 对于解析得到的结构体，嵌套对象中键的顺序保持原始文件中的顺序，未修改时结果与Composer对原文件的计算结果相同。
+//
+// 返回:
+//   - string: 32位十六进制的content-hash
+//   - error: 如果序列化失败，返回错误
+//
+// 示例:
+//
+//	hash, err := composer.ContentHash()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(hash)
+func (c *ComposerJSON) ContentHash() (string, error) {
+	data, err := c.ToJSON(true)
+	if err != nil {
+		return "", err
+	}
+	return lock.ContentHash([]byte(data))
+}
+
+// IsLockFresh 检查composer.lock是否与composer.json一致
+//
+// 参数:
+//   - composer: composer.json结构体
+//   - l: 解析后的composer.lock
+//
+// 返回:
+//   - *lock.Freshness: 检查结果，包括计算出的content-hash和可以推断出的发生变化的键
+//   - error: 如果序列化composer.json失败，返回错误
+//
+// 示例:
+//
+//	project, _ := composer.ParseDir(".")
+//	l, _ := composer.ParseLockDir(".")
+//
+//	freshness, err := composer.IsLockFresh(project, l)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if !freshness.Fresh {
+//		fmt.Println("请运行composer update，变化的键:", freshness.ChangedKeys)
+//	}
+func IsLockFresh(composer *ComposerJSON, l *lock.Lock) (*lock.Freshness, error) {
+	data, err := composer.ToJSON(true)
+	if err != nil {
+		return nil, err
+	}
+	return lock.CheckFreshness([]byte(data), l)
+}
+
+// VerifyLock 校验锁文件是否满足require、require-dev中的依赖以及conflict中的冲突
+//
+// 参数:
+//   - l: 解析后的composer.lock
+//
+// 返回:
+//   - []lock.Finding: 发现的问题，锁文件满足要求时为空；规则见lock.Verify
+//
+// 示例:
+//
+//	project, _ := composer.ParseDir(".")
+//	l, _ := composer.ParseLockDir(".")
+//
+//	for _, finding := range project.VerifyLock(l) {
+//		fmt.Println(finding) // require: monolog/monolog "^3.0" is not satisfied by locked monolog/monolog 2.9.1
+//	}
+func (c *ComposerJSON) VerifyLock(l *lock.Lock) []lock.Finding {
+	return lock.Verify(l, c.Require, c.RequireDev, c.Conflict)
+}
@@ -0,0 +1,339 @@
+package lock
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/version"
+)
+
+// ChangeKind 表示两个锁文件之间一个包的变化类型
+type ChangeKind string
+
+// 变化类型定义
+const (
+	// ChangeAdded 新增的包
+	ChangeAdded ChangeKind = "added"
+
+	// ChangeRemoved 删除的包
+	ChangeRemoved ChangeKind = "removed"
+
+	// ChangeUpgraded 版本升高的包
+	ChangeUpgraded ChangeKind = "upgraded"
+
+	// ChangeDowngraded 版本降低的包
+	ChangeDowngraded ChangeKind = "downgraded"
+
+	// ChangeReference 版本不变但源码引用（提交）变化的包，通常是dev分支
+	ChangeReference ChangeKind = "reference-changed"
+
+	// ChangeMoved 版本和引用都不变，但在packages和packages-dev之间移动的包
+	ChangeMoved ChangeKind = "moved"
+)
+
+// PackageChange 描述一个包在两个锁文件之间的变化
+type PackageChange struct {
+	// Name 包名，使用新锁文件中的写法
+	Name string `json:"name"`
+
+	// Kind 变化类型；版本变化的同时发生移动时，Kind为升级或降级，移动由OldDev和NewDev体现
+	Kind ChangeKind `json:"kind"`
+
+	// OldVersion 旧锁文件中的版本，新增时为空
+	OldVersion string `json:"old-version,omitempty"`
+
+	// NewVersion 新锁文件中的版本，删除时为空
+	NewVersion string `json:"new-version,omitempty"`
+
+	// OldReference 旧锁文件中的源码引用
+	OldReference string `json:"old-reference,omitempty"`
+
+	// NewReference 新锁文件中的源码引用
+	NewReference string `json:"new-reference,omitempty"`
+
+	// OldDev 旧锁文件中是否位于packages-dev
+	OldDev bool `json:"old-dev"`
+
+	// NewDev 新锁文件中是否位于packages-dev
+	NewDev bool `json:"new-dev"`
+}
+
+// Moved 判断包是否在packages和packages-dev之间移动
+func (c PackageChange) Moved() bool {
+	return c.Kind != ChangeAdded && c.Kind != ChangeRemoved && c.OldDev != c.NewDev
+}
+
+// String 返回变化的描述，格式与Composer安装时的输出类似
+//
+// 示例:
+//
+//	Upgrading monolog/monolog (3.4.0 => 3.5.0)
+//	Installing psr/log (3.0.0)
+func (c PackageChange) String() string {
+	var s string
+	switch c.Kind {
+	case ChangeAdded:
+		s = fmt.Sprintf("Installing %s (%s)", c.Name, c.NewVersion)
+	case ChangeRemoved:
+		s = fmt.Sprintf("Removing %s (%s)", c.Name, c.OldVersion)
+	case ChangeUpgraded:
+		s = fmt.Sprintf("Upgrading %s (%s => %s)", c.Name, c.from(), c.to())
+	case ChangeDowngraded:
+		s = fmt.Sprintf("Downgrading %s (%s => %s)", c.Name, c.from(), c.to())
+	case ChangeReference:
+		s = fmt.Sprintf("Updating %s (%s => %s)", c.Name, c.from(), c.to())
+	default:
+		s = fmt.Sprintf("Moving %s (%s)", c.Name, c.NewVersion)
+	}
+	if c.Moved() {
+		s += " to " + section(c.NewDev)
+	}
+	return s
+}
+
+// from 返回旧版本的描述，dev分支或只有引用变化时附带引用的前7位
+func (c PackageChange) from() string {
+	return describeVersion(c.OldVersion, c.OldReference, c.Kind == ChangeReference)
+}
+
+// to 返回新版本的描述，dev分支或只有引用变化时附带引用的前7位
+func (c PackageChange) to() string {
+	return describeVersion(c.NewVersion, c.NewReference, c.Kind == ChangeReference)
+}
+
+// Changeset 是两个锁文件之间所有包的变化
+type Changeset struct {
+	// Changes 发生变化的包，按小写包名排序；未变化的包不包含在内
+	Changes []PackageChange `json:"changes"`
+}
+
+// IsEmpty 判断是否没有任何变化
+func (cs *Changeset) IsEmpty() bool {
+	return len(cs.Changes) == 0
+}
+
+// Count 返回指定类型的变化数量
+func (cs *Changeset) Count(kind ChangeKind) int {
+	n := 0
+	for _, c := range cs.Changes {
+		if c.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// Diff 比较两个锁文件中的包
+//
+// 参数:
+//   - old: 旧的锁文件，为nil时视为空锁文件
+//   - new: 新的锁文件，为nil时视为空锁文件
+//
+// 返回:
+//   - *Changeset: 所有发生变化的包
+//
+// 分类规则:
+//   - 包名比较不区分大小写
+//   - 版本按Composer的规则比较（见version.Order），无法解析的版本按字符串比较
+//   - 版本相同时比较source的引用（没有source时使用dist的引用）
+//   - 只在packages和packages-dev之间移动的包归类为ChangeMoved
+//
+// 示例:
+//
+//	oldLock, _ := lock.ParseFile("base/composer.lock")
+//	newLock, _ := lock.ParseFile("composer.lock")
+//
+//	changes := lock.Diff(oldLock, newLock)
+//	fmt.Print(changes.Markdown())
+func Diff(old, new *Lock) *Changeset {
+	type entry struct {
+		pkg *LockedPackage
+		dev bool
+	}
+	collect := func(l *Lock) map[string]entry {
+		entries := make(map[string]entry)
+		if l == nil {
+			return entries
+		}
+		for i := range l.Packages {
+			entries[strings.ToLower(l.Packages[i].Name)] = entry{&l.Packages[i], false}
+		}
+		for i := range l.PackagesDev {
+			entries[strings.ToLower(l.PackagesDev[i].Name)] = entry{&l.PackagesDev[i], true}
+		}
+		return entries
+	}
+	before, after := collect(old), collect(new)
+
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	cs := &Changeset{Changes: []PackageChange{}}
+	for _, name := range names {
+		o, inOld := before[name]
+		n, inNew := after[name]
+
+		var c PackageChange
+		switch {
+		case !inOld:
+			c = PackageChange{Name: n.pkg.Name, Kind: ChangeAdded, NewVersion: n.pkg.Version, NewReference: n.pkg.reference(), NewDev: n.dev}
+		case !inNew:
+			c = PackageChange{Name: o.pkg.Name, Kind: ChangeRemoved, OldVersion: o.pkg.Version, OldReference: o.pkg.reference(), OldDev: o.dev}
+		default:
+			c = PackageChange{
+				Name:         n.pkg.Name,
+				OldVersion:   o.pkg.Version,
+				NewVersion:   n.pkg.Version,
+				OldReference: o.pkg.reference(),
+				NewReference: n.pkg.reference(),
+				OldDev:       o.dev,
+				NewDev:       n.dev,
+			}
+			switch cmp := compareVersions(o.pkg.Version, n.pkg.Version); {
+			case cmp < 0:
+				c.Kind = ChangeUpgraded
+			case cmp > 0:
+				c.Kind = ChangeDowngraded
+			case c.OldReference != c.NewReference:
+				c.Kind = ChangeReference
+			case o.dev != n.dev:
+				c.Kind = ChangeMoved
+			default:
+				continue
+			}
+		}
+		cs.Changes = append(cs.Changes, c)
+	}
+	return cs
+}
+
+// Text 以纯文本输出变化，每行一个包；没有变化时输出"No package changes."
+//
+// 示例:
+//
+//	fmt.Print(changes.Text())
+//	// 输出:
+//	//   - Upgrading monolog/monolog (3.4.0 => 3.5.0)
+//	//   - Installing psr/log (3.0.0)
+func (cs *Changeset) Text() string {
+	if cs.IsEmpty() {
+		return "No package changes.\n"
+	}
+
+	var b strings.Builder
+	for _, c := range cs.Changes {
+		b.WriteString("  - ")
+		b.WriteString(c.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Markdown 以Markdown表格输出变化，适合发布到代码评审的评论中
+//
+// 示例:
+//
+//	| Package | Change | From | To |
+//	| --- | --- | --- | --- |
+//	| monolog/monolog | upgraded | 3.4.0 | 3.5.0 |
+func (cs *Changeset) Markdown() string {
+	if cs.IsEmpty() {
+		return "No package changes.\n"
+	}
+
+	var b strings.Builder
+	b.WriteString("| Package | Change | From | To |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for _, c := range cs.Changes {
+		kind := string(c.Kind)
+		if c.Moved() && c.Kind != ChangeMoved {
+			kind += ", moved"
+		}
+		if c.Moved() {
+			kind += " to " + section(c.NewDev)
+		}
+
+		from, to := "", ""
+		if c.Kind != ChangeAdded {
+			from = c.from()
+			if c.OldDev {
+				from += " (dev)"
+			}
+		}
+		if c.Kind != ChangeRemoved {
+			to = c.to()
+			if c.NewDev {
+				to += " (dev)"
+			}
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", markdownCell(c.Name), kind, markdownCell(from), markdownCell(to))
+	}
+	return b.String()
+}
+
+// JSON 以JSON输出变化
+//
+// 示例:
+//
+//	{"changes":[{"name":"monolog/monolog","kind":"upgraded","old-version":"3.4.0","new-version":"3.5.0","old-dev":false,"new-dev":false}]}
+func (cs *Changeset) JSON() ([]byte, error) {
+	data, err := json.Marshal(cs)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling to JSON: %v", err)
+	}
+	return data, nil
+}
+
+// reference 返回包的源码引用，没有source时使用dist的引用
+func (p *LockedPackage) reference() string {
+	if p.Source != nil && p.Source.Reference != "" {
+		return p.Source.Reference
+	}
+	if p.Dist != nil {
+		return p.Dist.Reference
+	}
+	return ""
+}
+
+// compareVersions 按Composer的规则比较版本，无法解析时按字符串比较
+func compareVersions(a, b string) int {
+	if cmp, err := version.Order(a, b); err == nil {
+		return cmp
+	}
+	return strings.Compare(a, b)
+}
+
+// describeVersion 返回版本的描述，dev分支或withReference为true时附带引用的前7位，如"dev-main 1a2b3c4"
+func describeVersion(v, reference string, withReference bool) string {
+	isDev := strings.HasPrefix(v, "dev-") || strings.HasSuffix(v, "-dev")
+	if reference == "" || !isDev && !withReference {
+		return v
+	}
+	if len(reference) > 7 {
+		reference = reference[:7]
+	}
+	return v + " " + reference
+}
+
+// section 返回包所在的锁文件字段名
+func section(dev bool) string {
+	if dev {
+		return "packages-dev"
+	}
+	return "packages"
+}
+
+// markdownCell 转义Markdown表格单元格中的竖线
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
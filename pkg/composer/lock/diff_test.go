@@ -0,0 +1,112 @@
+package lock
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func lockedPackage(name, version, reference string) LockedPackage {
+	return LockedPackage{Name: name, Version: version, Source: &Source{Type: "git", Reference: reference}}
+}
+
+func TestDiff(t *testing.T) {
+	old := &Lock{
+		Packages: []LockedPackage{
+			lockedPackage("monolog/monolog", "3.4.0", "aaaaaaaaaaaa"),
+			lockedPackage("psr/log", "3.0.0", "bbbbbbbbbbbb"),
+			lockedPackage("acme/tool", "dev-main", "1111111111111"),
+			lockedPackage("symfony/console", "v6.4.0", "cccccccccccc"),
+			lockedPackage("vendor/removed", "1.0.0", "dddddddddddd"),
+			lockedPackage("vendor/moved", "2.0.0", "eeeeeeeeeeee"),
+			lockedPackage("vendor/same", "1.0.0", "ffffffffffff"),
+		},
+	}
+	new := &Lock{
+		Packages: []LockedPackage{
+			lockedPackage("Monolog/Monolog", "3.5.0", "abababababab"),
+			lockedPackage("psr/log", "3.0.0", "bbbbbbbbbbbb"),
+			lockedPackage("acme/tool", "dev-main", "2222222222222"),
+			lockedPackage("symfony/console", "v6.3.0", "cdcdcdcdcdcd"),
+			lockedPackage("vendor/added", "0.1.0", "121212121212"),
+			lockedPackage("vendor/same", "1.0.0", "ffffffffffff"),
+		},
+		PackagesDev: []LockedPackage{
+			lockedPackage("vendor/moved", "2.0.0", "eeeeeeeeeeee"),
+		},
+	}
+
+	changes := Diff(old, new)
+	want := []PackageChange{
+		{Name: "acme/tool", Kind: ChangeReference, OldVersion: "dev-main", NewVersion: "dev-main", OldReference: "1111111111111", NewReference: "2222222222222"},
+		{Name: "Monolog/Monolog", Kind: ChangeUpgraded, OldVersion: "3.4.0", NewVersion: "3.5.0", OldReference: "aaaaaaaaaaaa", NewReference: "abababababab"},
+		{Name: "symfony/console", Kind: ChangeDowngraded, OldVersion: "v6.4.0", NewVersion: "v6.3.0", OldReference: "cccccccccccc", NewReference: "cdcdcdcdcdcd"},
+		{Name: "vendor/added", Kind: ChangeAdded, NewVersion: "0.1.0", NewReference: "121212121212"},
+		{Name: "vendor/moved", Kind: ChangeMoved, OldVersion: "2.0.0", NewVersion: "2.0.0", OldReference: "eeeeeeeeeeee", NewReference: "eeeeeeeeeeee", NewDev: true},
+		{Name: "vendor/removed", Kind: ChangeRemoved, OldVersion: "1.0.0", OldReference: "dddddddddddd"},
+	}
+	if !reflect.DeepEqual(changes.Changes, want) {
+		t.Errorf("Diff() =\n%+v\nwant\n%+v", changes.Changes, want)
+	}
+	if changes.Count(ChangeUpgraded) != 1 || changes.IsEmpty() {
+		t.Errorf("Count() or IsEmpty() returned unexpected results")
+	}
+
+	if !Diff(old, old).IsEmpty() {
+		t.Errorf("Diff() of identical locks should be empty")
+	}
+	if got := Diff(nil, new); got.Count(ChangeAdded) != 7 {
+		t.Errorf("Diff(nil, new) should report every package as added, got %+v", got.Changes)
+	}
+}
+
+func TestChangesetRenderers(t *testing.T) {
+	old := &Lock{Packages: []LockedPackage{
+		lockedPackage("acme/tool", "dev-main", "1111111111111"),
+		lockedPackage("monolog/monolog", "3.4.0", "aaaa"),
+	}}
+	new := &Lock{
+		Packages: []LockedPackage{lockedPackage("acme/tool", "dev-main", "2222222222222")},
+		PackagesDev: []LockedPackage{
+			lockedPackage("monolog/monolog", "3.5.0", "abab"),
+			lockedPackage("phpunit/phpunit", "10.5.0", "cccc"),
+		},
+	}
+	changes := Diff(old, new)
+
+	wantText := "  - Updating acme/tool (dev-main 1111111 => dev-main 2222222)\n" +
+		"  - Upgrading monolog/monolog (3.4.0 => 3.5.0) to packages-dev\n" +
+		"  - Installing phpunit/phpunit (10.5.0)\n"
+	if got := changes.Text(); got != wantText {
+		t.Errorf("Text() =\n%s\nwant\n%s", got, wantText)
+	}
+
+	wantMarkdown := "| Package | Change | From | To |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| acme/tool | reference-changed | dev-main 1111111 | dev-main 2222222 |\n" +
+		"| monolog/monolog | upgraded, moved to packages-dev | 3.4.0 | 3.5.0 (dev) |\n" +
+		"| phpunit/phpunit | added |  | 10.5.0 (dev) |\n"
+	if got := changes.Markdown(); got != wantMarkdown {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, wantMarkdown)
+	}
+
+	data, err := changes.JSON()
+	if err != nil {
+		t.Fatalf("JSON() returned unexpected error: %v", err)
+	}
+	var decoded Changeset
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("JSON() returned invalid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, changes) {
+		t.Errorf("JSON() round trip = %+v, want %+v", decoded, changes)
+	}
+
+	empty := Diff(old, old)
+	if empty.Text() != "No package changes.\n" || empty.Markdown() != "No package changes.\n" {
+		t.Errorf("empty renderers returned unexpected output")
+	}
+	if data, _ := empty.JSON(); string(data) != `{"changes":[]}` {
+		t.Errorf("empty JSON() = %s", data)
+	}
+}
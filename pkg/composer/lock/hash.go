@@ -0,0 +1,545 @@
+package lock
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/dependency"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/document"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/stability"
+)
+
+// RelevantKeys 是参与content-hash计算的composer.json顶层键，与Composer的Locker::getContentHash一致
+//
+// 此外config.platform也参与计算。
+var RelevantKeys = []string{
+	"name",
+	"version",
+	"require",
+	"require-dev",
+	"conflict",
+	"replace",
+	"provide",
+	"minimum-stability",
+	"prefer-stable",
+	"repositories",
+	"extra",
+}
+
+// ContentHash 计算composer.json内容的content-hash
+//
+// 算法与Composer的Locker::getContentHash一致：取出相关的顶层键和config.platform，
+// 按键排序后以PHP json_encode的方式编码（转义"/"和非ASCII字符，空对象编码为"[]"），再计算MD5。
+// 嵌套对象中键的顺序保持源文本中的顺序，因此必须传入composer.json的原始内容。
+//
+// 参数:
+//   - composerJSON: composer.json的原始内容
+//
+// 返回:
+//   - string: 32位十六进制的content-hash
+//   - error: 如果内容不是JSON对象，返回错误
+//
+// 示例:
+//
+//	data, _ := os.ReadFile("composer.json")
+//	hash, err := lock.ContentHash(data)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(hash == l.ContentHash) // 锁文件是否是最新的
+func ContentHash(composerJSON []byte) (string, error) {
+	root, err := parseRoot(composerJSON)
+	if err != nil {
+		return "", err
+	}
+
+	type entry struct {
+		key   string
+		value []byte
+	}
+	var entries []entry
+	for _, key := range RelevantKeys {
+		if n := root.Get(key); n != nil {
+			entries = append(entries, entry{key, encodePHP(n)})
+		}
+	}
+	if platform := root.Get("config").Get("platform"); platform != nil && platform.Kind != document.Null {
+		entries = append(entries, entry{"config", []byte(`{"platform":` + string(encodePHP(platform)) + `}`)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	var buf bytes.Buffer
+	if len(entries) == 0 {
+		buf.WriteString("[]")
+	} else {
+		buf.WriteByte('{')
+		for i, e := range entries {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writePHPString(&buf, e.key)
+			buf.WriteByte(':')
+			buf.Write(e.value)
+		}
+		buf.WriteByte('}')
+	}
+
+	sum := md5.Sum(buf.Bytes())
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Freshness 描述锁文件与composer.json是否一致
+type Freshness struct {
+	// Fresh 锁文件的content-hash与composer.json一致时为true
+	Fresh bool
+
+	// ContentHash 根据composer.json计算出的content-hash
+	ContentHash string
+
+	// LockedHash 锁文件中记录的content-hash
+	LockedHash string
+
+	// ChangedKeys 可以从锁文件内容推断出发生变化的键，按字母顺序排列，如"config.platform"、"require"
+	//
+	// 锁文件只记录了部分相关内容，name、version、conflict、replace、provide、repositories和extra
+	// 的变化只能通过哈希发现，因此Fresh为false时ChangedKeys可能为空。
+	ChangedKeys []string
+}
+
+// CheckFreshness 检查锁文件是否与composer.json一致，并推断发生变化的键
+//
+// 参数:
+//   - composerJSON: composer.json的原始内容
+//   - l: 解析后的锁文件
+//
+// 返回:
+//   - *Freshness: 检查结果
+//   - error: 如果composer.json不是JSON对象或相关字段的类型无效，返回错误
+//
+// 推断规则:
+//   - minimum-stability、prefer-stable与锁文件中的同名字段比较
+//   - require和require-dev中的平台包与platform和platform-dev比较，其他包必须出现在锁文件中
+//     （或被锁定的包replace、provide），require中的包不能只出现在packages-dev中
+//   - 从约束中提取的稳定性标志与stability-flags比较
+//   - config.platform与platform-overrides比较
+//
+// 示例:
+//
+//	freshness, err := lock.CheckFreshness(data, l)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if !freshness.Fresh {
+//		fmt.Println("composer.lock已过期，变化的键:", freshness.ChangedKeys)
+//	}
+func CheckFreshness(composerJSON []byte, l *Lock) (*Freshness, error) {
+	hash, err := ContentHash(composerJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Freshness{
+		Fresh:       hash == l.ContentHash,
+		ContentHash: hash,
+		LockedHash:  l.ContentHash,
+	}
+	if result.Fresh {
+		return result, nil
+	}
+
+	root, err := decodeRoot(composerJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string]bool)
+
+	minimum := normalizeStability(root.MinimumStability)
+	if minimum != normalizeStability(l.MinimumStability) {
+		changed["minimum-stability"] = true
+	}
+	if root.PreferStable != l.PreferStable {
+		changed["prefer-stable"] = true
+	}
+
+	provided := l.providedNames()
+	for _, section := range []struct {
+		key      string
+		require  map[string]string
+		platform Platform
+		dev      bool
+	}{
+		{"require", root.Require, l.Platform, false},
+		{"require-dev", root.RequireDev, l.PlatformDev, true},
+	} {
+		if !requirementsMatch(section.require, section.platform, section.dev, l, provided) {
+			changed[section.key] = true
+		}
+	}
+
+	if _, err := stability.Normalize(minimum); err == nil {
+		flags := stability.ExtractFlags(root.mergedRequire(), minimum)
+		for _, name := range differingFlags(flags, l.StabilityFlags) {
+			if hasPackage(root.Require, name) || !hasPackage(root.RequireDev, name) {
+				changed["require"] = true
+			} else {
+				changed["require-dev"] = true
+			}
+		}
+	}
+
+	if !platformOverridesEqual(root.Config.Platform, l.PlatformOverrides) {
+		changed["config.platform"] = true
+	}
+
+	for key := range changed {
+		result.ChangedKeys = append(result.ChangedKeys, key)
+	}
+	sort.Strings(result.ChangedKeys)
+	return result, nil
+}
+
+// rootFields 是composer.json中锁文件记录的字段
+type rootFields struct {
+	Require          map[string]string `json:"require"`
+	RequireDev       map[string]string `json:"require-dev"`
+	MinimumStability string            `json:"minimum-stability"`
+	PreferStable     bool              `json:"prefer-stable"`
+	Config           struct {
+		Platform map[string]interface{} `json:"platform"`
+	} `json:"config"`
+}
+
+// decodeRoot 解析composer.json中锁文件记录的字段
+func decodeRoot(composerJSON []byte) (*rootFields, error) {
+	var root rootFields
+	if err := json.Unmarshal(composerJSON, &root); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnmarshallingJSON, err)
+	}
+	return &root, nil
+}
+
+// mergedRequire 合并require和require-dev，同一个包在两处都有时使用require中的约束
+func (r *rootFields) mergedRequire() map[string]string {
+	merged := make(map[string]string, len(r.Require)+len(r.RequireDev))
+	for name, c := range r.RequireDev {
+		merged[name] = c
+	}
+	for name, c := range r.Require {
+		merged[name] = c
+	}
+	return merged
+}
+
+// parseRoot 解析composer.json并确认根值是对象
+func parseRoot(data []byte) (*document.Node, error) {
+	doc, err := document.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	if doc.Root.Kind != document.Object {
+		return nil, fmt.Errorf("%w: composer.json must be an object, got %s", ErrUnmarshallingJSON, doc.Root.Kind)
+	}
+	return doc.Root, nil
+}
+
+// normalizeStability 规范化稳定性名称，空值视为"stable"，无效值原样返回
+func normalizeStability(s string) string {
+	if s == "" {
+		return "stable"
+	}
+	if normalized, err := stability.Normalize(s); err == nil {
+		return normalized
+	}
+	return s
+}
+
+// providedNames 返回锁定的包及其replace、provide的包名集合，key为小写包名，value表示是否只来自packages-dev
+func (l *Lock) providedNames() map[string]bool {
+	names := make(map[string]bool)
+	add := func(pkgs []LockedPackage, dev bool) {
+		for _, pkg := range pkgs {
+			keys := []string{pkg.Name}
+			for name := range pkg.Replace {
+				keys = append(keys, name)
+			}
+			for name := range pkg.Provide {
+				keys = append(keys, name)
+			}
+			for _, key := range keys {
+				key = strings.ToLower(key)
+				if onlyDev, ok := names[key]; !ok || (onlyDev && !dev) {
+					names[key] = dev
+				}
+			}
+		}
+	}
+	add(l.Packages, false)
+	add(l.PackagesDev, true)
+	return names
+}
+
+// requirementsMatch 判断一组依赖是否与锁文件中记录的平台依赖和锁定的包一致
+func requirementsMatch(require map[string]string, platform Platform, dev bool, l *Lock, provided map[string]bool) bool {
+	platformReqs := make(map[string]string)
+	for name, c := range require {
+		name = strings.ToLower(name)
+		if dependency.IsPlatformPackage(name) {
+			platformReqs[name] = c
+			continue
+		}
+		onlyDev, ok := provided[name]
+		if !ok || (onlyDev && !dev) {
+			return false
+		}
+	}
+
+	if len(platformReqs) != len(platform) {
+		return false
+	}
+	for name, c := range platform {
+		if platformReqs[strings.ToLower(name)] != c {
+			return false
+		}
+	}
+	return true
+}
+
+// differingFlags 返回两组稳定性标志中不一致的包名
+func differingFlags(flags map[string]string, locked StabilityFlags) []string {
+	var names []string
+	for name, s := range flags {
+		if locked.Stability(name) != s {
+			names = append(names, name)
+		}
+	}
+	for name := range locked {
+		if _, ok := flags[strings.ToLower(name)]; !ok {
+			names = append(names, strings.ToLower(name))
+		}
+	}
+	return names
+}
+
+// hasPackage 不区分大小写地判断依赖映射中是否包含指定的包
+func hasPackage(require map[string]string, name string) bool {
+	for key := range require {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// platformOverridesEqual 判断config.platform与锁文件中的platform-overrides是否一致
+func platformOverridesEqual(a, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, v := range a {
+		if fmt.Sprint(v) != fmt.Sprint(b[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// encodePHP 按PHP json_encode的默认选项编码节点，节点的值等同于json_decode($json, true)的结果
+func encodePHP(n *document.Node) []byte {
+	var buf bytes.Buffer
+	writePHP(&buf, n)
+	return buf.Bytes()
+}
+
+// writePHP 按PHP json_encode的默认选项输出节点
+func writePHP(buf *bytes.Buffer, n *document.Node) {
+	switch n.Kind {
+	case document.Object:
+		writePHPObject(buf, n)
+	case document.Array:
+		buf.WriteByte('[')
+		for i, e := range n.Elements {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writePHP(buf, e)
+		}
+		buf.WriteByte(']')
+	case document.String:
+		s, _ := n.StringValue()
+		writePHPString(buf, s)
+	case document.Number:
+		buf.WriteString(phpNumber(string(n.Raw)))
+	default:
+		buf.Write(n.Raw)
+	}
+}
+
+// writePHPObject 输出解码为PHP关联数组的对象
+//
+// PHP数组中整数形式的字符串键会变成整数键，重复的键保留第一次出现的位置和最后一次的值；
+// 键恰好为0到n-1时数组被编码为JSON数组，空对象因此编码为"[]"。
+func writePHPObject(buf *bytes.Buffer, n *document.Node) {
+	var keys []string
+	values := make(map[string]*document.Node)
+	for _, m := range n.Members {
+		key := m.Key
+		if i, ok := phpIntegerKey(key); ok {
+			key = strconv.FormatInt(i, 10)
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = m.Value
+	}
+
+	isList := true
+	for i, key := range keys {
+		if key != strconv.Itoa(i) {
+			isList = false
+			break
+		}
+	}
+
+	if isList {
+		buf.WriteByte('[')
+	} else {
+		buf.WriteByte('{')
+	}
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if !isList {
+			writePHPString(buf, key)
+			buf.WriteByte(':')
+		}
+		writePHP(buf, values[key])
+	}
+	if isList {
+		buf.WriteByte(']')
+	} else {
+		buf.WriteByte('}')
+	}
+}
+
+// phpIntegerKey 判断字符串键是否会被PHP转换为整数键，如"0"、"-12"，但不包括"01"、"+1"
+func phpIntegerKey(key string) (int64, bool) {
+	if key == "" || key == "-0" || (key[0] == '0' && len(key) > 1) || strings.HasPrefix(key, "-0") {
+		return 0, false
+	}
+	for i, r := range key {
+		if !(r >= '0' && r <= '9') && !(i == 0 && r == '-' && len(key) > 1) {
+			return 0, false
+		}
+	}
+	i, err := strconv.ParseInt(key, 10, 64)
+	return i, err == nil
+}
+
+// writePHPString 按PHP json_encode的默认选项输出字符串：转义"/"，非ASCII字符输出为\uXXXX
+func writePHPString(buf *bytes.Buffer, s string) {
+	const hexDigits = "0123456789abcdef"
+	writeUnit := func(u rune) {
+		buf.WriteString(`\u`)
+		buf.WriteByte(hexDigits[u>>12&0xf])
+		buf.WriteByte(hexDigits[u>>8&0xf])
+		buf.WriteByte(hexDigits[u>>4&0xf])
+		buf.WriteByte(hexDigits[u&0xf])
+	}
+
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"':
+			buf.WriteString(`\"`)
+		case r == '\\':
+			buf.WriteString(`\\`)
+		case r == '/':
+			buf.WriteString(`\/`)
+		case r == '\b':
+			buf.WriteString(`\b`)
+		case r == '\f':
+			buf.WriteString(`\f`)
+		case r == '\n':
+			buf.WriteString(`\n`)
+		case r == '\r':
+			buf.WriteString(`\r`)
+		case r == '\t':
+			buf.WriteString(`\t`)
+		case r < 0x20:
+			writeUnit(r)
+		case r < utf8.RuneSelf:
+			buf.WriteRune(r)
+		case r > 0xffff:
+			r -= 0x10000
+			writeUnit(0xd800 + r>>10)
+			writeUnit(0xdc00 + r&0x3ff)
+		default:
+			writeUnit(r)
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// phpNumber 按PHP json_decode后再json_encode的结果输出数字
+//
+// 在int64范围内的整数保持整数，其他数字按浮点数以serialize_precision=-1的规则输出，
+// 如"1.50"输出为"1.5"，"1e20"输出为"1.0e+20"，"2.0"输出为"2"。
+func phpNumber(raw string) string {
+	if !strings.ContainsAny(raw, ".eE") {
+		if i, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return strconv.FormatInt(i, 10)
+		}
+	}
+
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return raw
+	}
+	if f == 0 {
+		if strings.HasPrefix(raw, "-") {
+			return "-0"
+		}
+		return "0"
+	}
+
+	sign := ""
+	if f < 0 {
+		sign, f = "-", -f
+	}
+
+	// 最短的有效数字和小数点位置，与zend_dtoa的mode 0一致
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, _ := strconv.Atoi(exp)
+	decpt := e + 1
+
+	switch {
+	case decpt < -3 || decpt > 17:
+		rest := digits[1:]
+		if rest == "" {
+			rest = "0"
+		}
+		expSign := "+"
+		if e < 0 {
+			expSign, e = "-", -e
+		}
+		return sign + digits[:1] + "." + rest + "e" + expSign + strconv.Itoa(e)
+	case decpt <= 0:
+		return sign + "0." + strings.Repeat("0", -decpt) + digits
+	case decpt >= len(digits):
+		return sign + digits + strings.Repeat("0", decpt-len(digits))
+	default:
+		return sign + digits[:decpt] + "." + digits[decpt:]
+	}
+}
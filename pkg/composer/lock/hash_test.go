@@ -0,0 +1,147 @@
+package lock
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/document"
+)
+
+func TestContentHash(t *testing.T) {
+	hash := func(s string) string {
+		t.Helper()
+		h, err := ContentHash([]byte(s))
+		if err != nil {
+			t.Fatalf("ContentHash(%s) returned unexpected error: %v", s, err)
+		}
+		return h
+	}
+
+	// Composer为没有相关键的composer.json生成的content-hash，即md5("[]")
+	if got := hash(`{"description": "nothing relevant"}`); got != "d751713988987e9331980363e24189ce" {
+		t.Errorf("ContentHash() = %s, want d751713988987e9331980363e24189ce", got)
+	}
+
+	base := hash(`{"name": "vendor/project", "require": {"php": ">=8.1", "psr/log": "^3.0"}}`)
+	if got := hash(`{"require": {"php": ">=8.1", "psr/log": "^3.0"}, "name": "vendor/project", "license": "MIT"}`); got != base {
+		t.Errorf("top-level key order and irrelevant keys should not change the hash")
+	}
+	if got := hash(`{"name": "vendor/project", "require": {"psr/log": "^3.0", "php": ">=8.1"}}`); got == base {
+		t.Errorf("nested key order should change the hash")
+	}
+	if got := hash(`{"name": "vendor/project", "require": {"php": ">=8.1", "psr/log": "^3.0"}, "config": {"sort-packages": true}}`); got != base {
+		t.Errorf("config keys other than platform should not change the hash")
+	}
+	if got := hash(`{"name": "vendor/project", "require": {"php": ">=8.1", "psr/log": "^3.0"}, "config": {"platform": {"php": "8.1.0"}}}`); got == base {
+		t.Errorf("config.platform should change the hash")
+	}
+
+	if _, err := ContentHash([]byte(`[]`)); err == nil {
+		t.Errorf("ContentHash() should fail for a non-object document")
+	}
+}
+
+func TestEncodePHP(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{}`, `[]`},
+		{`{"a": {}, "b": []}`, `{"a":[],"b":[]}`},
+		{`{"0": "x", "1": "y"}`, `["x","y"]`},
+		{`{"1": "x", "0": "y"}`, `{"1":"x","0":"y"}`},
+		{`{"01": "x"}`, `{"01":"x"}`},
+		{`{"a": 1, "a": 2}`, `{"a":2}`},
+		{`"https://example.com/a"`, `"https:\/\/example.com\/a"`},
+		{`"café 😀"`, `"caf\u00e9 \ud83d\ude00"`},
+		{`"tab\tquote\"\u0001"`, `"tab\tquote\"\u0001"`},
+		{`"<b>&'"`, `"<b>&'"`},
+		{`[1, 1.50, 2.0, 1e20, 0.00001, 0.0001, -3, 12345678901234567890]`, `[1,1.5,2,1.0e+20,1.0e-5,0.0001,-3,1.2345678901234567e+19]`},
+		{`[true, false, null]`, `[true,false,null]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			doc, err := document.Parse([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("document.Parse() returned unexpected error: %v", err)
+			}
+			if got := string(encodePHP(doc.Root)); got != tt.want {
+				t.Errorf("encodePHP() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckFreshness(t *testing.T) {
+	composerJSON := `{
+		"name": "vendor/project",
+		"require": {"php": ">=8.1", "monolog/monolog": "^3.0", "acme/tool": "dev-main"},
+		"require-dev": {"phpunit/phpunit": "^10.0"},
+		"config": {"platform": {"php": "8.1.0"}}
+	}`
+	hash, err := ContentHash([]byte(composerJSON))
+	if err != nil {
+		t.Fatalf("ContentHash() returned unexpected error: %v", err)
+	}
+
+	newLock := func() *Lock {
+		return &Lock{
+			ContentHash: hash,
+			Packages: []LockedPackage{
+				{Name: "monolog/monolog", Version: "3.5.0", Provide: map[string]string{"psr/log-implementation": "3.0.0"}},
+				{Name: "acme/tool", Version: "dev-main"},
+			},
+			PackagesDev:       []LockedPackage{{Name: "phpunit/phpunit", Version: "10.5.0"}},
+			MinimumStability:  "stable",
+			StabilityFlags:    StabilityFlags{"acme/tool": 20},
+			Platform:          Platform{"php": ">=8.1"},
+			PlatformDev:       Platform{},
+			PlatformOverrides: map[string]interface{}{"php": "8.1.0"},
+		}
+	}
+
+	freshness, err := CheckFreshness([]byte(composerJSON), newLock())
+	if err != nil {
+		t.Fatalf("CheckFreshness() returned unexpected error: %v", err)
+	}
+	if !freshness.Fresh || freshness.ContentHash != hash || len(freshness.ChangedKeys) != 0 {
+		t.Errorf("CheckFreshness() = %+v, want fresh", freshness)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(l *Lock)
+		want   []string
+	}{
+		{"only hash differs", func(l *Lock) {}, nil},
+		{"minimum-stability", func(l *Lock) { l.MinimumStability = "dev" }, []string{"minimum-stability"}},
+		{"prefer-stable", func(l *Lock) { l.PreferStable = true }, []string{"prefer-stable"}},
+		{"platform constraint", func(l *Lock) { l.Platform["php"] = ">=7.4" }, []string{"require"}},
+		{"missing package", func(l *Lock) { l.Packages = l.Packages[:1] }, []string{"require"}},
+		{"package only in dev", func(l *Lock) {
+			l.PackagesDev = append(l.PackagesDev, l.Packages[0])
+			l.Packages = l.Packages[1:]
+		}, []string{"require"}},
+		{"missing dev package", func(l *Lock) { l.PackagesDev = nil }, []string{"require-dev"}},
+		{"stability flags", func(l *Lock) { l.StabilityFlags = StabilityFlags{} }, []string{"require"}},
+		{"platform overrides", func(l *Lock) { l.PlatformOverrides = nil }, []string{"config.platform"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLock()
+			l.ContentHash = "0123456789abcdef0123456789abcdef"
+			tt.modify(l)
+
+			freshness, err := CheckFreshness([]byte(composerJSON), l)
+			if err != nil {
+				t.Fatalf("CheckFreshness() returned unexpected error: %v", err)
+			}
+			if freshness.Fresh {
+				t.Errorf("CheckFreshness() should report a stale lock")
+			}
+			if !reflect.DeepEqual(freshness.ChangedKeys, tt.want) {
+				t.Errorf("ChangedKeys = %v, want %v", freshness.ChangedKeys, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,422 @@
+// Package lock 提供解析PHP Composer的composer.lock文件的功能
+//
+// 本包处理composer.lock的各个部分，包括：
+// - packages和packages-dev中锁定的包
+// - aliases、stability-flags、platform和platform-dev等根包信息
+// - content-hash和plugin-api-version
+// - 根据composer.json创建锁文件，并以Composer写入的格式输出
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/autoload"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/parser"
+)
+
+// 错误定义
+var (
+	// ErrFileNotFound 表示composer.lock文件未找到
+	ErrFileNotFound = fmt.Errorf("composer.lock file not found")
+
+	// ErrReadingFile 表示读取文件时出错
+	ErrReadingFile = parser.ErrReadingFile
+
+	// ErrInvalidJSON 表示JSON格式无效
+	ErrInvalidJSON = parser.ErrInvalidJSON
+
+	// ErrUnmarshallingJSON 表示JSON反序列化时出错
+	ErrUnmarshallingJSON = parser.ErrUnmarshallingJSON
+)
+
+// Lock 表示composer.lock文件的根结构
+//
+// 示例:
+//
+//	{
+//	  "content-hash": "3f1c5b4c2a7e...",
+//	  "packages": [
+//	    {"name": "psr/log", "version": "3.0.0", "type": "library"}
+//	  ],
+//	  "packages-dev": [],
+//	  "aliases": [],
+//	  "minimum-stability": "stable",
+//	  "stability-flags": [],
+//	  "prefer-stable": false,
+//	  "prefer-lowest": false,
+//	  "platform": {"php": ">=8.1"},
+//	  "platform-dev": [],
+//	  "plugin-api-version": "2.6.0"
+//	}
+type Lock struct {
+	// Readme 文件开头的说明文字
+	Readme []string `json:"_readme,omitempty"`
+
+	// ContentHash composer.json相关内容的哈希值，用于判断锁文件是否过期
+	ContentHash string `json:"content-hash"`
+
+	// Packages 锁定的运行时依赖
+	Packages []LockedPackage `json:"packages"`
+
+	// PackagesDev 锁定的开发时依赖
+	PackagesDev []LockedPackage `json:"packages-dev"`
+
+	// Aliases 根包中定义的内联别名，如"dev-main as 1.0.x-dev"
+	Aliases []Alias `json:"aliases"`
+
+	// MinimumStability 根包的minimum-stability
+	MinimumStability string `json:"minimum-stability"`
+
+	// StabilityFlags 根包依赖的稳定性标志，key为小写包名
+	StabilityFlags StabilityFlags `json:"stability-flags"`
+
+	// PreferStable 根包的prefer-stable
+	PreferStable bool `json:"prefer-stable"`
+
+	// PreferLowest 生成锁文件时是否使用了--prefer-lowest
+	PreferLowest bool `json:"prefer-lowest"`
+
+	// Platform 根包require中的平台依赖，如"php"、"ext-json"
+	Platform Platform `json:"platform"`
+
+	// PlatformDev 根包require-dev中的平台依赖
+	PlatformDev Platform `json:"platform-dev"`
+
+	// PlatformOverrides config.platform中模拟的平台包版本，值为版本字符串或表示禁用的false
+	PlatformOverrides map[string]interface{} `json:"platform-overrides,omitempty"`
+
+	// PluginAPIVersion 生成锁文件的Composer插件API版本
+	PluginAPIVersion string `json:"plugin-api-version,omitempty"`
+}
+
+// LockedPackage 表示composer.lock中锁定的一个包
+type LockedPackage struct {
+	// Name 包名，如"monolog/monolog"
+	Name string `json:"name"`
+
+	// Version 锁定的版本，如"3.5.0"、"dev-main"
+	Version string `json:"version"`
+
+	// Source 源码仓库信息
+	Source *Source `json:"source,omitempty"`
+
+	// Dist 发行包信息
+	Dist *Dist `json:"dist,omitempty"`
+
+	// Require 包的运行时依赖
+	Require map[string]string `json:"require,omitempty"`
+
+	// Conflict 包声明的冲突
+	Conflict map[string]string `json:"conflict,omitempty"`
+
+	// Provide 包提供的虚拟包
+	Provide map[string]string `json:"provide,omitempty"`
+
+	// Replace 包替换的其他包
+	Replace map[string]string `json:"replace,omitempty"`
+
+	// RequireDev 包的开发时依赖
+	RequireDev map[string]string `json:"require-dev,omitempty"`
+
+	// Suggest 包建议安装的其他包
+	Suggest map[string]string `json:"suggest,omitempty"`
+
+	// Bin 可执行文件列表
+	Bin []string `json:"bin,omitempty"`
+
+	// Type 包类型，如"library"、"composer-plugin"
+	Type string `json:"type,omitempty"`
+
+	// Extra 附加元数据
+	Extra map[string]interface{} `json:"extra,omitempty"`
+
+	// Autoload 自动加载配置
+	Autoload *autoload.Autoload `json:"autoload,omitempty"`
+
+	// AutoloadDev 开发时自动加载配置
+	AutoloadDev *autoload.Autoload `json:"autoload-dev,omitempty"`
+
+	// NotificationURL 安装通知的URL
+	NotificationURL string `json:"notification-url,omitempty"`
+
+	// IncludePath 旧式的include-path配置
+	IncludePath []string `json:"include-path,omitempty"`
+
+	// License 许可证列表
+	License []string `json:"license,omitempty"`
+
+	// Authors 作者信息
+	Authors []Author `json:"authors,omitempty"`
+
+	// Description 包描述
+	Description string `json:"description,omitempty"`
+
+	// Homepage 项目主页
+	Homepage string `json:"homepage,omitempty"`
+
+	// Keywords 关键词
+	Keywords []string `json:"keywords,omitempty"`
+
+	// Support 支持信息，如"issues"、"source"
+	Support map[string]string `json:"support,omitempty"`
+
+	// Funding 资助信息
+	Funding []Funding `json:"funding,omitempty"`
+
+	// Abandoned 包是否已被废弃，可以是布尔值或推荐替代包的字符串
+	Abandoned interface{} `json:"abandoned,omitempty"`
+
+	// DefaultBranch 是否为仓库的默认分支
+	DefaultBranch bool `json:"default-branch,omitempty"`
+
+	// Time 发布时间，格式为RFC 3339，如"2023-10-27T15:32:31+00:00"；与Composer一致总是输出在最后
+	Time string `json:"time,omitempty"`
+}
+
+// ReleaseTime 解析发布时间
+//
+// 返回:
+//   - time.Time: 发布时间
+//   - bool: 没有发布时间或格式无效时返回false
+func (p *LockedPackage) ReleaseTime() (time.Time, bool) {
+	if p.Time == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, p.Time); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Source 表示包的源码仓库
+type Source struct {
+	// Type 仓库类型，如"git"、"hg"、"svn"
+	Type string `json:"type"`
+
+	// URL 仓库地址
+	URL string `json:"url"`
+
+	// Reference 锁定的提交或标签
+	Reference string `json:"reference"`
+}
+
+// Dist 表示包的发行包
+type Dist struct {
+	// Type 发行包类型，如"zip"、"tar"、"path"
+	Type string `json:"type"`
+
+	// URL 下载地址
+	URL string `json:"url"`
+
+	// Reference 对应的提交或标签
+	Reference string `json:"reference,omitempty"`
+
+	// Shasum 发行包的SHA1校验和，通常为空
+	Shasum string `json:"shasum"`
+}
+
+// Author 表示锁定包的作者
+type Author struct {
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Homepage string `json:"homepage,omitempty"`
+	Role     string `json:"role,omitempty"`
+}
+
+// Funding 表示锁定包的资助渠道
+type Funding struct {
+	// Type 资助类型，如"github"、"tidelift"
+	Type string `json:"type"`
+
+	// URL 资助页面地址
+	URL string `json:"url"`
+}
+
+// Alias 表示根包中的内联别名
+type Alias struct {
+	// Package 包名
+	Package string `json:"package"`
+
+	// Version 被别名的版本，Composer写入规范化后的版本，如"dev-main"、"1.0.9999999.9999999-dev"
+	Version string `json:"version"`
+
+	// Alias 别名，如"1.0.x-dev"
+	Alias string `json:"alias"`
+
+	// AliasNormalized 规范化后的别名，如"1.0.9999999.9999999-dev"
+	AliasNormalized string `json:"alias_normalized"`
+}
+
+// Platform 是平台包到版本约束的映射
+//
+// Composer把空的映射写成PHP空数组"[]"，Platform在解析和输出时都兼容这种形式。
+type Platform map[string]string
+
+// UnmarshalJSON 实现json.Unmarshaler接口，接受对象或空数组
+func (p *Platform) UnmarshalJSON(data []byte) error {
+	m := make(map[string]string)
+	if err := unmarshalPHPMap(data, &m); err != nil {
+		return err
+	}
+	*p = m
+	return nil
+}
+
+// MarshalJSON 实现json.Marshaler接口，空映射输出为"[]"
+func (p Platform) MarshalJSON() ([]byte, error) {
+	if len(p) == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(map[string]string(p))
+}
+
+// StabilityFlags 是包名到稳定性级别的映射，级别为0（stable）、5（RC）、10（beta）、15（alpha）或20（dev）
+//
+// 与Platform一样，空的映射写成"[]"。
+type StabilityFlags map[string]int
+
+// 稳定性级别，与Composer的BasePackage::STABILITIES一致
+var stabilityLevels = map[int]string{0: "stable", 5: "RC", 10: "beta", 15: "alpha", 20: "dev"}
+
+// Stability 返回包的稳定性标志名称，如"beta"；没有标志时返回空字符串
+func (f StabilityFlags) Stability(packageName string) string {
+	level, ok := f[strings.ToLower(packageName)]
+	if !ok {
+		return ""
+	}
+	return stabilityLevels[level]
+}
+
+// UnmarshalJSON 实现json.Unmarshaler接口，接受对象或空数组
+func (f *StabilityFlags) UnmarshalJSON(data []byte) error {
+	m := make(map[string]int)
+	if err := unmarshalPHPMap(data, &m); err != nil {
+		return err
+	}
+	*f = m
+	return nil
+}
+
+// MarshalJSON 实现json.Marshaler接口，空映射输出为"[]"
+func (f StabilityFlags) MarshalJSON() ([]byte, error) {
+	if len(f) == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(map[string]int(f))
+}
+
+// unmarshalPHPMap 解析PHP关联数组：对象正常解析，空数组和null视为空映射
+func unmarshalPHPMap(data []byte, v interface{}) error {
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err == nil {
+		if len(list) > 0 {
+			return fmt.Errorf("expected an object or an empty array, got a non-empty array")
+		}
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// AllPackages 返回packages和packages-dev中的所有包
+func (l *Lock) AllPackages() []LockedPackage {
+	all := make([]LockedPackage, 0, len(l.Packages)+len(l.PackagesDev))
+	all = append(all, l.Packages...)
+	return append(all, l.PackagesDev...)
+}
+
+// FindPackage 按包名（不区分大小写）查找锁定的包
+//
+// 返回:
+//   - *LockedPackage: 找到的包，不存在时为nil
+//   - bool: 包位于packages-dev中时为true
+func (l *Lock) FindPackage(name string) (*LockedPackage, bool) {
+	for i := range l.Packages {
+		if strings.EqualFold(l.Packages[i].Name, name) {
+			return &l.Packages[i], false
+		}
+	}
+	for i := range l.PackagesDev {
+		if strings.EqualFold(l.PackagesDev[i].Name, name) {
+			return &l.PackagesDev[i], true
+		}
+	}
+	return nil, false
+}
+
+// ParseFile 从文件路径解析composer.lock文件
+//
+// 参数:
+//   - filePath: composer.lock文件路径
+//
+// 返回:
+//   - *Lock: 解析后的结构体
+//   - error: 文件不存在时返回ErrFileNotFound，JSON无效时返回*parser.ParseError
+//
+// 示例:
+//
+//	l, err := lock.ParseFile("./composer.lock")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, pkg := range l.Packages {
+//		fmt.Println(pkg.Name, pkg.Version)
+//	}
+func ParseFile(filePath string) (*Lock, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrFileNotFound, filePath)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrReadingFile, err)
+	}
+
+	return ParseBytes(data)
+}
+
+// ParseDir 在指定目录中查找并解析composer.lock文件
+//
+// 示例:
+//
+//	l, err := lock.ParseDir("/path/to/php/project")
+func ParseDir(dir string) (*Lock, error) {
+	return ParseFile(filepath.Join(dir, "composer.lock"))
+}
+
+// Parse 从io.Reader读取并解析composer.lock
+func Parse(r io.Reader) (*Lock, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReadingFile, err)
+	}
+
+	return ParseBytes(data)
+}
+
+// ParseString 解析composer.lock字符串
+func ParseString(s string) (*Lock, error) {
+	return ParseBytes([]byte(s))
+}
+
+// ParseBytes 解析composer.lock的原始字节
+func ParseBytes(data []byte) (*Lock, error) {
+	// 验证JSON，失败时由parser生成带有位置信息的ParseError
+	if !json.Valid(data) {
+		_, err := parser.ParseBytes(data)
+		return nil, err
+	}
+
+	var l Lock
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnmarshallingJSON, err)
+	}
+	return &l, nil
+}
@@ -0,0 +1,280 @@
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/parser"
+)
+
+const sampleLock = `{
+    "_readme": [
+        "This file locks the dependencies of your project to a known state",
+        "This file is @generated automatically"
+    ],
+    "content-hash": "8f4d2b1e6c9a0d3f5e7b2a1c4d6e8f0a",
+    "packages": [
+        {
+            "name": "monolog/monolog",
+            "version": "3.5.0",
+            "source": {
+                "type": "git",
+                "url": "https://github.com/Seldaek/monolog.git",
+                "reference": "c915e2634718dbc8a4a15c61b0e62e7a44e14448"
+            },
+            "dist": {
+                "type": "zip",
+                "url": "https://api.github.com/repos/Seldaek/monolog/zipball/c915e2634718dbc8a4a15c61b0e62e7a44e14448",
+                "reference": "c915e2634718dbc8a4a15c61b0e62e7a44e14448",
+                "shasum": ""
+            },
+            "require": {
+                "php": ">=8.1",
+                "psr/log": "^2.0 || ^3.0"
+            },
+            "provide": {
+                "psr/log-implementation": "3.0.0"
+            },
+            "type": "library",
+            "extra": {
+                "branch-alias": {
+                    "dev-main": "3.x-dev"
+                }
+            },
+            "autoload": {
+                "psr-4": {
+                    "Monolog\\": "src/Monolog"
+                }
+            },
+            "notification-url": "https://packagist.org/downloads/",
+            "license": [
+                "MIT"
+            ],
+            "authors": [
+                {
+                    "name": "Jordi Boggiano",
+                    "email": "j.boggiano@seld.be",
+                    "homepage": "https://seld.be"
+                }
+            ],
+            "description": "Sends your logs to files, sockets, inboxes, databases and various web services",
+            "keywords": [
+                "log",
+                "logging"
+            ],
+            "support": {
+                "issues": "https://github.com/Seldaek/monolog/issues",
+                "source": "https://github.com/Seldaek/monolog/tree/3.5.0"
+            },
+            "funding": [
+                {
+                    "url": "https://github.com/Seldaek",
+                    "type": "github"
+                }
+            ],
+            "time": "2023-10-27T15:32:31+00:00"
+        },
+        {
+            "name": "psr/log",
+            "version": "3.0.0",
+            "source": {
+                "type": "git",
+                "url": "https://github.com/php-fig/log.git",
+                "reference": "fe5ea303b0887d5caefd3d431c3e61ad47037001"
+            },
+            "type": "library",
+            "time": "2021-07-14T16:46:02+00:00"
+        }
+    ],
+    "packages-dev": [
+        {
+            "name": "acme/tool",
+            "version": "dev-main",
+            "source": {
+                "type": "git",
+                "url": "https://github.com/acme/tool.git",
+                "reference": "0123456789abcdef0123456789abcdef01234567"
+            },
+            "type": "library",
+            "default-branch": true
+        }
+    ],
+    "aliases": [
+        {
+            "package": "acme/tool",
+            "version": "dev-main",
+            "alias": "1.0.x-dev",
+            "alias_normalized": "1.0.9999999.9999999-dev"
+        }
+    ],
+    "minimum-stability": "stable",
+    "stability-flags": {
+        "acme/tool": 20
+    },
+    "prefer-stable": true,
+    "prefer-lowest": false,
+    "platform": {
+        "php": ">=8.1",
+        "ext-json": "*"
+    },
+    "platform-dev": [],
+    "plugin-api-version": "2.6.0"
+}`
+
+func TestParseString(t *testing.T) {
+	l, err := ParseString(sampleLock)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	if l.ContentHash != "8f4d2b1e6c9a0d3f5e7b2a1c4d6e8f0a" {
+		t.Errorf("ContentHash = %q", l.ContentHash)
+	}
+	if len(l.Readme) != 2 {
+		t.Errorf("Readme = %v", l.Readme)
+	}
+	if len(l.Packages) != 2 || len(l.PackagesDev) != 1 {
+		t.Fatalf("got %d packages and %d dev packages", len(l.Packages), len(l.PackagesDev))
+	}
+
+	monolog := l.Packages[0]
+	if monolog.Name != "monolog/monolog" || monolog.Version != "3.5.0" || monolog.Type != "library" {
+		t.Errorf("Packages[0] = %s %s %s", monolog.Name, monolog.Version, monolog.Type)
+	}
+	if monolog.Source == nil || monolog.Source.Reference != "c915e2634718dbc8a4a15c61b0e62e7a44e14448" {
+		t.Errorf("Source = %+v", monolog.Source)
+	}
+	if monolog.Dist == nil || monolog.Dist.Type != "zip" {
+		t.Errorf("Dist = %+v", monolog.Dist)
+	}
+	if monolog.Require["psr/log"] != "^2.0 || ^3.0" {
+		t.Errorf("Require = %v", monolog.Require)
+	}
+	if monolog.Autoload == nil || monolog.Autoload.PSR4 == nil {
+		t.Errorf("Autoload = %+v", monolog.Autoload)
+	}
+	if monolog.NotificationURL != "https://packagist.org/downloads/" {
+		t.Errorf("NotificationURL = %q", monolog.NotificationURL)
+	}
+	if len(monolog.License) != 1 || monolog.License[0] != "MIT" {
+		t.Errorf("License = %v", monolog.License)
+	}
+	if _, ok := monolog.Extra["branch-alias"]; !ok {
+		t.Errorf("Extra = %v", monolog.Extra)
+	}
+	if len(monolog.Funding) != 1 || monolog.Funding[0].Type != "github" {
+		t.Errorf("Funding = %v", monolog.Funding)
+	}
+	released, ok := monolog.ReleaseTime()
+	if !ok || !released.Equal(time.Date(2023, 10, 27, 15, 32, 31, 0, time.UTC)) {
+		t.Errorf("ReleaseTime() = %v, %v", released, ok)
+	}
+
+	if !l.PackagesDev[0].DefaultBranch {
+		t.Errorf("PackagesDev[0].DefaultBranch should be true")
+	}
+	if len(l.Aliases) != 1 || l.Aliases[0].AliasNormalized != "1.0.9999999.9999999-dev" {
+		t.Errorf("Aliases = %+v", l.Aliases)
+	}
+	if got := l.StabilityFlags.Stability("ACME/tool"); got != "dev" {
+		t.Errorf("StabilityFlags.Stability() = %q, want dev", got)
+	}
+	if !l.PreferStable || l.PreferLowest || l.MinimumStability != "stable" {
+		t.Errorf("stability settings = %v %v %q", l.PreferStable, l.PreferLowest, l.MinimumStability)
+	}
+	if l.Platform["ext-json"] != "*" || len(l.PlatformDev) != 0 {
+		t.Errorf("Platform = %v, PlatformDev = %v", l.Platform, l.PlatformDev)
+	}
+	if l.PluginAPIVersion != "2.6.0" {
+		t.Errorf("PluginAPIVersion = %q", l.PluginAPIVersion)
+	}
+}
+
+func TestFindPackage(t *testing.T) {
+	l, err := ParseString(sampleLock)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		found   bool
+		wantDev bool
+	}{
+		{"psr/log", true, false},
+		{"Monolog/Monolog", true, false},
+		{"acme/tool", true, true},
+		{"vendor/missing", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, dev := l.FindPackage(tt.name)
+			if (pkg != nil) != tt.found || dev != tt.wantDev {
+				t.Errorf("FindPackage(%q) = %v, %v", tt.name, pkg, dev)
+			}
+		})
+	}
+
+	if got := len(l.AllPackages()); got != 3 {
+		t.Errorf("AllPackages() returned %d packages, want 3", got)
+	}
+}
+
+func TestEmptyMapsRoundTrip(t *testing.T) {
+	l, err := ParseString(`{"content-hash": "x", "packages": [], "packages-dev": [], "aliases": [],
+		"minimum-stability": "stable", "stability-flags": [], "prefer-stable": false,
+		"prefer-lowest": false, "platform": [], "platform-dev": []}`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(l)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned unexpected error: %v", err)
+	}
+	for _, want := range []string{`"stability-flags":[]`, `"platform":[]`, `"platform-dev":[]`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("marshalled lock %s does not contain %s", data, want)
+		}
+	}
+	if strings.Contains(string(data), "platform-overrides") {
+		t.Errorf("marshalled lock %s should omit empty platform-overrides", data)
+	}
+
+	if _, err := ParseString(`{"platform": ["php"]}`); !errors.Is(err, ErrUnmarshallingJSON) {
+		t.Errorf("ParseString() error = %v, want ErrUnmarshallingJSON", err)
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "composer.lock"), []byte(sampleLock), 0644); err != nil {
+		t.Fatalf("Failed to write composer.lock: %v", err)
+	}
+
+	l, err := ParseDir(dir)
+	if err != nil {
+		t.Fatalf("ParseDir() returned unexpected error: %v", err)
+	}
+	if len(l.Packages) != 2 {
+		t.Errorf("ParseDir() returned %d packages, want 2", len(l.Packages))
+	}
+
+	if _, err := ParseFile(filepath.Join(dir, "missing.lock")); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("ParseFile() error = %v, want ErrFileNotFound", err)
+	}
+
+	if _, err := Parse(strings.NewReader(`{"packages": [`)); err == nil {
+		t.Errorf("Parse() should fail for invalid JSON")
+	} else {
+		var parseErr *parser.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("Parse() error = %T, want *parser.ParseError", err)
+		}
+	}
+}
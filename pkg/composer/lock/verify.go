@@ -0,0 +1,297 @@
+package lock
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/constraint"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/dependency"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/version"
+)
+
+// FindingKind 表示锁文件校验发现的问题类型
+type FindingKind string
+
+// 问题类型定义
+const (
+	// FindingMissing 锁文件中没有该包，也没有锁定的包replace或provide它
+	FindingMissing FindingKind = "missing"
+
+	// FindingUnsatisfied 锁定的版本不满足依赖的版本约束
+	FindingUnsatisfied FindingKind = "unsatisfied"
+
+	// FindingDevOnly require中的包只出现在packages-dev中，使用--no-dev安装时会缺失
+	FindingDevOnly FindingKind = "dev-only"
+
+	// FindingConflict 锁定的版本命中了conflict中的约束
+	FindingConflict FindingKind = "conflict"
+
+	// FindingInvalidConstraint composer.json中的版本约束无效，无法校验
+	FindingInvalidConstraint FindingKind = "invalid-constraint"
+)
+
+// Finding 描述锁文件不满足composer.json的一处问题
+type Finding struct {
+	// Kind 问题类型
+	Kind FindingKind
+
+	// Section 问题所在的composer.json字段："require"、"require-dev"或"conflict"
+	Section string
+
+	// Package composer.json中的包名
+	Package string
+
+	// Constraint composer.json中的版本约束
+	Constraint string
+
+	// LockedPackage 相关的锁定包名，通过replace或provide满足时与Package不同；FindingMissing时为空
+	LockedPackage string
+
+	// LockedVersion 相关的锁定版本；通过replace或provide满足时为对应链接的约束
+	LockedVersion string
+}
+
+// String 返回问题的描述
+func (f Finding) String() string {
+	switch f.Kind {
+	case FindingMissing:
+		return fmt.Sprintf("%s: %s %q is not locked", f.Section, f.Package, f.Constraint)
+	case FindingUnsatisfied:
+		return fmt.Sprintf("%s: %s %q is not satisfied by locked %s", f.Section, f.Package, f.Constraint, f.locked())
+	case FindingDevOnly:
+		return fmt.Sprintf("%s: %s %q is only locked in packages-dev (%s)", f.Section, f.Package, f.Constraint, f.locked())
+	case FindingConflict:
+		return fmt.Sprintf("%s: %s %q is violated by locked %s", f.Section, f.Package, f.Constraint, f.locked())
+	case FindingInvalidConstraint:
+		return fmt.Sprintf("%s: %s %q is not a valid constraint", f.Section, f.Package, f.Constraint)
+	default:
+		return fmt.Sprintf("%s: %s %q: %s", f.Section, f.Package, f.Constraint, f.Kind)
+	}
+}
+
+// locked 返回锁定包的描述，如"monolog/monolog 3.5.0"
+func (f Finding) locked() string {
+	if f.LockedPackage != f.Package {
+		return fmt.Sprintf("%s %s (via %s)", f.Package, f.LockedVersion, f.LockedPackage)
+	}
+	return fmt.Sprintf("%s %s", f.LockedPackage, f.LockedVersion)
+}
+
+// candidate 表示能满足某个包名的一个锁定来源
+type candidate struct {
+	// pkg 锁定的包
+	pkg *LockedPackage
+
+	// dev 锁定的包是否位于packages-dev中
+	dev bool
+
+	// versions 包自身可匹配的版本，包括别名
+	versions []string
+
+	// link 来自replace或provide时为链接的约束，否则为空
+	link string
+}
+
+// matches 判断来源是否满足约束：自身的版本需要匹配约束，replace和provide的约束需要与之相交
+func (c candidate) matches(con constraint.Constraint) bool {
+	if c.link != "" {
+		linked, err := constraint.Parse(c.link)
+		return err == nil && constraint.Intersects(linked, con)
+	}
+	for _, v := range c.versions {
+		if con.Matches(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// version 返回来源在问题描述中使用的版本
+func (c candidate) version() string {
+	if c.link != "" {
+		return c.link
+	}
+	return c.pkg.Version
+}
+
+// Verify 校验锁文件是否满足composer.json中的依赖和冲突
+//
+// 参数:
+//   - l: 解析后的锁文件
+//   - require: composer.json的require
+//   - requireDev: composer.json的require-dev
+//   - conflict: composer.json的conflict
+//
+// 返回:
+//   - []Finding: 按require、require-dev、conflict的顺序排列，同一字段中按包名排序；锁文件满足要求时为空
+//
+// 校验规则:
+//   - 平台包（如"php"、"ext-json"）不在锁文件中，不做校验
+//   - 锁定包的版本包括根包的内联别名、extra.branch-alias中的分支别名，默认分支还包括"9999999-dev"
+//   - 锁定包replace或provide的链接与依赖约束相交时视为满足，"self.version"取锁定包的版本
+//   - require中的包只能由packages中的包满足，否则报告FindingDevOnly
+//   - conflict中的约束匹配任一锁定包的版本，或与replace、provide的链接相交时报告FindingConflict
+//
+// 示例:
+//
+//	findings := lock.Verify(l, project.Require, project.RequireDev, project.Conflict)
+//	for _, f := range findings {
+//		fmt.Println(f) // require: monolog/monolog "^3.0" is not satisfied by locked monolog/monolog 2.9.1
+//	}
+func Verify(l *Lock, require, requireDev, conflict map[string]string) []Finding {
+	index := l.candidates()
+
+	var findings []Finding
+	for _, section := range []struct {
+		name    string
+		require map[string]string
+		dev     bool
+	}{
+		{"require", require, false},
+		{"require-dev", requireDev, true},
+	} {
+		for _, name := range sortedNames(section.require) {
+			if dependency.IsPlatformPackage(name) {
+				continue
+			}
+			if f, ok := verifyRequirement(section.name, name, section.require[name], section.dev, index); !ok {
+				findings = append(findings, f)
+			}
+		}
+	}
+
+	for _, name := range sortedNames(conflict) {
+		if dependency.IsPlatformPackage(name) {
+			continue
+		}
+		findings = append(findings, verifyConflict(name, conflict[name], index)...)
+	}
+	return findings
+}
+
+// verifyRequirement 校验一个依赖，满足时第二个返回值为true
+func verifyRequirement(section, name, c string, dev bool, index map[string][]candidate) (Finding, bool) {
+	f := Finding{Section: section, Package: name, Constraint: c}
+
+	con, err := constraint.Parse(c)
+	if err != nil {
+		f.Kind = FindingInvalidConstraint
+		return f, false
+	}
+
+	candidates := index[strings.ToLower(name)]
+	if len(candidates) == 0 {
+		f.Kind = FindingMissing
+		return f, false
+	}
+
+	var devMatch *candidate
+	for i := range candidates {
+		cand := candidates[i]
+		if !cand.matches(con) {
+			continue
+		}
+		if !cand.dev || dev {
+			return f, true
+		}
+		if devMatch == nil {
+			devMatch = &candidates[i]
+		}
+	}
+
+	f.Kind, f.LockedPackage, f.LockedVersion = FindingUnsatisfied, candidates[0].pkg.Name, candidates[0].version()
+	if devMatch != nil {
+		f.Kind, f.LockedPackage, f.LockedVersion = FindingDevOnly, devMatch.pkg.Name, devMatch.version()
+	}
+	return f, false
+}
+
+// verifyConflict 校验一个冲突，返回每个命中冲突的锁定来源
+func verifyConflict(name, c string, index map[string][]candidate) []Finding {
+	con, err := constraint.Parse(c)
+	if err != nil {
+		return []Finding{{Kind: FindingInvalidConstraint, Section: "conflict", Package: name, Constraint: c}}
+	}
+
+	var findings []Finding
+	for _, cand := range index[strings.ToLower(name)] {
+		if cand.matches(con) {
+			findings = append(findings, Finding{
+				Kind:          FindingConflict,
+				Section:       "conflict",
+				Package:       name,
+				Constraint:    c,
+				LockedPackage: cand.pkg.Name,
+				LockedVersion: cand.version(),
+			})
+		}
+	}
+	return findings
+}
+
+// candidates 按小写包名索引所有锁定来源，packages中的来源排在packages-dev之前
+func (l *Lock) candidates() map[string][]candidate {
+	index := make(map[string][]candidate)
+	add := func(pkgs []LockedPackage, dev bool) {
+		for i := range pkgs {
+			pkg := &pkgs[i]
+			key := strings.ToLower(pkg.Name)
+			index[key] = append(index[key], candidate{pkg: pkg, dev: dev, versions: l.versionsOf(pkg)})
+
+			for _, links := range []map[string]string{pkg.Replace, pkg.Provide} {
+				for target, link := range links {
+					if link == "self.version" {
+						link = pkg.Version
+					}
+					key := strings.ToLower(target)
+					index[key] = append(index[key], candidate{pkg: pkg, dev: dev, link: link})
+				}
+			}
+		}
+	}
+	add(l.Packages, false)
+	add(l.PackagesDev, true)
+	return index
+}
+
+// versionsOf 返回锁定包可以匹配的所有版本：锁定的版本、根包内联别名、分支别名和默认分支别名
+func (l *Lock) versionsOf(pkg *LockedPackage) []string {
+	versions := []string{pkg.Version}
+	// 锁文件中别名的version是规范化后的版本，如"1.0.9999999.9999999-dev"，锁定包的version是"1.0.x-dev"
+	normalized := normalizeVersion(pkg.Version)
+	for _, alias := range l.Aliases {
+		if strings.EqualFold(alias.Package, pkg.Name) && normalizeVersion(alias.Version) == normalized {
+			versions = append(versions, alias.Alias)
+		}
+	}
+
+	if branchAliases, ok := pkg.Extra["branch-alias"].(map[string]interface{}); ok {
+		if alias, ok := branchAliases[pkg.Version].(string); ok {
+			versions = append(versions, alias)
+			return versions
+		}
+	}
+	if pkg.DefaultBranch {
+		versions = append(versions, version.DefaultBranchAlias)
+	}
+	return versions
+}
+
+// normalizeVersion 返回规范化后的版本，无法规范化时返回原值
+func normalizeVersion(v string) string {
+	if normalized, err := version.Normalize(v); err == nil {
+		return normalized
+	}
+	return v
+}
+
+// sortedNames 返回按字母顺序排列的包名
+func sortedNames(m map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,122 @@
+package lock
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	l := &Lock{
+		Packages: []LockedPackage{
+			{Name: "monolog/monolog", Version: "3.5.0", Provide: map[string]string{"psr/log-implementation": "3.0.0"}},
+			{Name: "psr/log", Version: "3.0.0"},
+			{Name: "symfony/polyfill", Version: "v1.28.0", Replace: map[string]string{"symfony/polyfill-ctype": "self.version"}},
+			{Name: "acme/framework", Version: "dev-main", Extra: map[string]interface{}{
+				"branch-alias": map[string]interface{}{"dev-main": "2.x-dev"},
+			}},
+			{Name: "acme/tool", Version: "dev-feature"},
+			{Name: "acme/default", Version: "dev-trunk", DefaultBranch: true},
+		},
+		PackagesDev: []LockedPackage{
+			{Name: "phpunit/phpunit", Version: "10.5.0"},
+			{Name: "acme/debug", Version: "1.0.0"},
+		},
+		Aliases: []Alias{{Package: "acme/tool", Version: "dev-feature", Alias: "1.2.x-dev", AliasNormalized: "1.2.9999999.9999999-dev"}},
+	}
+
+	tests := []struct {
+		name       string
+		require    map[string]string
+		requireDev map[string]string
+		conflict   map[string]string
+		want       []Finding
+	}{
+		{
+			name: "satisfied",
+			require: map[string]string{
+				"php":                    ">=8.1",
+				"ext-json":               "*",
+				"monolog/monolog":        "^3.0",
+				"Psr/Log":                "^2.0 || ^3.0",
+				"psr/log-implementation": "^3.0",
+				"symfony/polyfill-ctype": "^1.20",
+				"acme/framework":         "^2.0@dev",
+				"acme/tool":              "~1.2@dev",
+				"acme/default":           "dev-trunk",
+			},
+			requireDev: map[string]string{"phpunit/phpunit": "^10.0", "psr/log": "^3.0"},
+			conflict:   map[string]string{"monolog/monolog": "<3.0", "symfony/polyfill-ctype": ">=2.0"},
+		},
+		{
+			name:    "default branch alias",
+			require: map[string]string{"acme/default": "^1.0"},
+			want: []Finding{
+				{Kind: FindingUnsatisfied, Section: "require", Package: "acme/default", Constraint: "^1.0", LockedPackage: "acme/default", LockedVersion: "dev-trunk"},
+			},
+		},
+		{
+			name:       "findings",
+			require:    map[string]string{"monolog/monolog": "^2.0", "vendor/missing": "^1.0", "acme/debug": "^1.0", "psr/log": "~>1"},
+			requireDev: map[string]string{"vendor/missing-dev": "*"},
+			conflict:   map[string]string{"psr/log": "3.0.0", "symfony/polyfill-ctype": "<1.30"},
+			want: []Finding{
+				{Kind: FindingDevOnly, Section: "require", Package: "acme/debug", Constraint: "^1.0", LockedPackage: "acme/debug", LockedVersion: "1.0.0"},
+				{Kind: FindingUnsatisfied, Section: "require", Package: "monolog/monolog", Constraint: "^2.0", LockedPackage: "monolog/monolog", LockedVersion: "3.5.0"},
+				{Kind: FindingInvalidConstraint, Section: "require", Package: "psr/log", Constraint: "~>1"},
+				{Kind: FindingMissing, Section: "require", Package: "vendor/missing", Constraint: "^1.0"},
+				{Kind: FindingMissing, Section: "require-dev", Package: "vendor/missing-dev", Constraint: "*"},
+				{Kind: FindingConflict, Section: "conflict", Package: "psr/log", Constraint: "3.0.0", LockedPackage: "psr/log", LockedVersion: "3.0.0"},
+				{Kind: FindingConflict, Section: "conflict", Package: "symfony/polyfill-ctype", Constraint: "<1.30", LockedPackage: "symfony/polyfill", LockedVersion: "v1.28.0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Verify(l, tt.require, tt.requireDev, tt.conflict)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Verify() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerify_NormalizedAlias(t *testing.T) {
+	l := &Lock{
+		Packages: []LockedPackage{{Name: "a/b", Version: "1.0.x-dev"}},
+		Aliases:  []Alias{{Package: "a/b", Version: "1.0.9999999.9999999-dev", Alias: "1.0.0", AliasNormalized: "1.0.0.0"}},
+	}
+
+	got := Verify(l, nil, nil, map[string]string{"a/b": "1.0.0"})
+	want := []Finding{
+		{Kind: FindingConflict, Section: "conflict", Package: "a/b", Constraint: "1.0.0", LockedPackage: "a/b", LockedVersion: "1.0.x-dev"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Verify() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestFindingString(t *testing.T) {
+	tests := []struct {
+		finding Finding
+		want    string
+	}{
+		{
+			Finding{Kind: FindingUnsatisfied, Section: "require", Package: "monolog/monolog", Constraint: "^2.0", LockedPackage: "monolog/monolog", LockedVersion: "3.5.0"},
+			`require: monolog/monolog "^2.0" is not satisfied by locked monolog/monolog 3.5.0`,
+		},
+		{
+			Finding{Kind: FindingConflict, Section: "conflict", Package: "symfony/polyfill-ctype", Constraint: "<1.30", LockedPackage: "symfony/polyfill", LockedVersion: "v1.28.0"},
+			`conflict: symfony/polyfill-ctype "<1.30" is violated by locked symfony/polyfill-ctype v1.28.0 (via symfony/polyfill)`,
+		},
+		{
+			Finding{Kind: FindingMissing, Section: "require-dev", Package: "vendor/missing", Constraint: "*"},
+			`require-dev: vendor/missing "*" is not locked`,
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.finding.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
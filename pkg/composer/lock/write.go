@@ -0,0 +1,147 @@
+package lock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/dependency"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/stability"
+)
+
+// DefaultReadme 是Composer写在锁文件开头的说明文字
+var DefaultReadme = []string{
+	"This file locks the dependencies of your project to a known state",
+	"Read more about it at https://getcomposer.org/doc/01-basic-usage.md#installing-dependencies",
+	"This file is @generated automatically",
+}
+
+// DefaultPluginAPIVersion 是New写入plugin-api-version的版本，与Composer 2.6及以后的版本一致
+const DefaultPluginAPIVersion = "2.6.0"
+
+// New 根据composer.json创建不包含任何锁定包的锁文件
+//
+// 根包相关的字段与Composer的Locker::setLockData一致：
+//   - content-hash由ContentHash计算
+//   - minimum-stability规范化后写入，为空时为"stable"
+//   - stability-flags由require和require-dev中的约束提取，规则见stability.ExtractFlags
+//   - platform和platform-dev为require和require-dev中的平台包，包名转为小写
+//   - platform-overrides为config.platform，为空时不写入
+//
+// 参数:
+//   - composerJSON: composer.json的原始内容
+//
+// 返回:
+//   - *Lock: 锁文件，packages、packages-dev和aliases为空列表，prefer-lowest为false
+//   - error: 如果composer.json不是JSON对象、相关字段的类型无效或minimum-stability无效，返回错误
+//
+// 示例:
+//
+//	data, _ := os.ReadFile("composer.json")
+//	l, err := lock.New(data)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	l.Packages = packages
+//	err = l.Save("composer.lock")
+func New(composerJSON []byte) (*Lock, error) {
+	hash, err := ContentHash(composerJSON)
+	if err != nil {
+		return nil, err
+	}
+	root, err := decodeRoot(composerJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	minimum := normalizeStability(root.MinimumStability)
+	if _, err := stability.Normalize(minimum); err != nil {
+		return nil, fmt.Errorf("%w: minimum-stability: %v", ErrUnmarshallingJSON, err)
+	}
+
+	flags := make(StabilityFlags)
+	for name, s := range stability.ExtractFlags(root.mergedRequire(), minimum) {
+		for level, levelName := range stabilityLevels {
+			if levelName == s {
+				flags[name] = level
+			}
+		}
+	}
+
+	l := &Lock{
+		Readme:           append([]string(nil), DefaultReadme...),
+		ContentHash:      hash,
+		Packages:         []LockedPackage{},
+		PackagesDev:      []LockedPackage{},
+		Aliases:          []Alias{},
+		MinimumStability: minimum,
+		StabilityFlags:   flags,
+		PreferStable:     root.PreferStable,
+		Platform:         platformRequirements(root.Require),
+		PlatformDev:      platformRequirements(root.RequireDev),
+		PluginAPIVersion: DefaultPluginAPIVersion,
+	}
+	if len(root.Config.Platform) > 0 {
+		l.PlatformOverrides = root.Config.Platform
+	}
+	return l, nil
+}
+
+// platformRequirements 返回require中的平台包，包名转为小写
+func platformRequirements(require map[string]string) Platform {
+	platform := make(Platform)
+	for name, c := range require {
+		if dependency.IsPlatformPackage(name) {
+			platform[strings.ToLower(name)] = c
+		}
+	}
+	return platform
+}
+
+// ToJSON 以Composer写入composer.lock的格式输出锁文件
+//
+// 格式与Composer的JsonFile::encode一致：4个空格缩进，不转义"/"和非ASCII字符，以换行结尾。
+// 为nil的packages、packages-dev和aliases输出为空列表，空的platform和stability-flags输出为"[]"。
+// 相同的锁文件总是得到相同的输出。
+//
+// 返回:
+//   - []byte: 锁文件内容
+//   - error: 如果序列化失败，返回错误
+func (l *Lock) ToJSON() ([]byte, error) {
+	out := *l
+	if out.Packages == nil {
+		out.Packages = []LockedPackage{}
+	}
+	if out.PackagesDev == nil {
+		out.PackagesDev = []LockedPackage{}
+	}
+	if out.Aliases == nil {
+		out.Aliases = []Alias{}
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(&out); err != nil {
+		return nil, fmt.Errorf("error marshalling to JSON: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Save 把锁文件写入指定路径，格式见ToJSON
+//
+// 示例:
+//
+//	if err := l.Save("./composer.lock"); err != nil {
+//		log.Fatal(err)
+//	}
+func (l *Lock) Save(filePath string) error {
+	data, err := l.ToJSON()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
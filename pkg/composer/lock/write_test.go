@@ -0,0 +1,117 @@
+package lock
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	data := []byte(`{
+		"name": "acme/app",
+		"require": {"PHP": ">=8.1", "ext-json": "*", "acme/lib": "^1.0@beta", "acme/tool": "dev-main as 1.0.x-dev"},
+		"require-dev": {"ext-xdebug": "^3.0", "acme/test": "^2.0@RC"},
+		"minimum-stability": "Beta",
+		"prefer-stable": true,
+		"config": {"platform": {"php": "8.1.0", "ext-redis": false}}
+	}`)
+
+	l, err := New(data)
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+
+	hash, _ := ContentHash(data)
+	if l.ContentHash != hash {
+		t.Errorf("ContentHash = %s, want %s", l.ContentHash, hash)
+	}
+	if l.MinimumStability != "beta" || !l.PreferStable || l.PreferLowest {
+		t.Errorf("stability fields = %q %v %v", l.MinimumStability, l.PreferStable, l.PreferLowest)
+	}
+	if want := (StabilityFlags{"acme/lib": 10, "acme/tool": 20, "acme/test": 5}); !reflect.DeepEqual(l.StabilityFlags, want) {
+		t.Errorf("StabilityFlags = %v, want %v", l.StabilityFlags, want)
+	}
+	if want := (Platform{"php": ">=8.1", "ext-json": "*"}); !reflect.DeepEqual(l.Platform, want) {
+		t.Errorf("Platform = %v, want %v", l.Platform, want)
+	}
+	if want := (Platform{"ext-xdebug": "^3.0"}); !reflect.DeepEqual(l.PlatformDev, want) {
+		t.Errorf("PlatformDev = %v, want %v", l.PlatformDev, want)
+	}
+	if want := map[string]interface{}{"php": "8.1.0", "ext-redis": false}; !reflect.DeepEqual(l.PlatformOverrides, want) {
+		t.Errorf("PlatformOverrides = %v, want %v", l.PlatformOverrides, want)
+	}
+
+	// 新建的锁文件对composer.json是最新的
+	freshness, err := CheckFreshness(data, l)
+	if err != nil || !freshness.Fresh {
+		t.Errorf("CheckFreshness() = %+v, %v, want fresh", freshness, err)
+	}
+
+	if _, err := New([]byte(`{"minimum-stability": "unstable"}`)); err == nil {
+		t.Error("New() with invalid minimum-stability expected error, got nil")
+	}
+	if _, err := New([]byte(`{"require": "x"}`)); err == nil {
+		t.Error("New() with invalid require expected error, got nil")
+	}
+}
+
+func TestLock_ToJSON(t *testing.T) {
+	l, err := New([]byte(`{"require": {"acme/lib": "^1.0"}}`))
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+	l.Packages = []LockedPackage{{
+		Name:          "acme/lib",
+		Version:       "1.0.0",
+		Dist:          &Dist{Type: "zip", URL: "https://example.com/acme/lib.zip?a=1&b=2"},
+		Description:   "Ünïcode <lib>",
+		Time:          "2024-01-01T00:00:00+00:00",
+		DefaultBranch: true,
+	}}
+	l.PackagesDev = nil
+
+	data, err := l.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() returned unexpected error: %v", err)
+	}
+	out := string(data)
+
+	for _, want := range []string{
+		"{\n    \"_readme\": [\n        \"This file locks",
+		`"url": "https://example.com/acme/lib.zip?a=1&b=2"`,
+		`"description": "Ünïcode <lib>"`,
+		"\"default-branch\": true,\n            \"time\": \"2024-01-01T00:00:00+00:00\"\n",
+		`"packages-dev": [],`,
+		`"aliases": [],`,
+		`"stability-flags": [],`,
+		`"platform": [],`,
+		`"plugin-api-version": "2.6.0"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("ToJSON() does not contain %q:\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "}\n") {
+		t.Errorf("ToJSON() should end with a newline")
+	}
+
+	// 输出可以重新解析，且再次输出的内容相同
+	parsed, err := ParseBytes(data)
+	if err != nil {
+		t.Fatalf("ParseBytes() returned unexpected error: %v", err)
+	}
+	again, _ := parsed.ToJSON()
+	if string(again) != out {
+		t.Errorf("ToJSON() is not stable across a round trip:\n%s\n%s", out, again)
+	}
+
+	path := filepath.Join(t.TempDir(), "composer.lock")
+	if err := l.Save(path); err != nil {
+		t.Fatalf("Save() returned unexpected error: %v", err)
+	}
+	if saved, _ := os.ReadFile(path); string(saved) != out {
+		t.Errorf("Save() wrote %q, want %q", saved, out)
+	}
+}
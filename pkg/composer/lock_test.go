@@ -0,0 +1,117 @@
+package composer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+)
+
+func TestParseLockDir(t *testing.T) {
+	dir := t.TempDir()
+	content := `{
+		"content-hash": "abc123",
+		"packages": [{"name": "psr/log", "version": "3.0.0"}],
+		"packages-dev": [],
+		"aliases": [],
+		"minimum-stability": "stable",
+		"stability-flags": [],
+		"prefer-stable": false,
+		"prefer-lowest": false,
+		"platform": [],
+		"platform-dev": [],
+		"plugin-api-version": "2.6.0"
+	}`
+	if err := os.WriteFile(filepath.Join(dir, "composer.lock"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write composer.lock: %v", err)
+	}
+
+	l, err := ParseLockDir(dir)
+	if err != nil {
+		t.Fatalf("ParseLockDir() returned unexpected error: %v", err)
+	}
+	if l.ContentHash != "abc123" || len(l.Packages) != 1 || l.Packages[0].Name != "psr/log" {
+		t.Errorf("ParseLockDir() = %+v", l)
+	}
+
+	if _, err := ParseLockFile(filepath.Join(dir, "missing.lock")); !errors.Is(err, lock.ErrFileNotFound) {
+		t.Errorf("ParseLockFile() error = %v, want ErrFileNotFound", err)
+	}
+}
+
+func TestIsLockFresh(t *testing.T) {
+	source := `{
+    "name": "vendor/project",
+    "require": {
+        "php": ">=8.1",
+        "psr/log": "^3.0"
+    }
+}
+`
+	project, err := ParseString(source)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	hash, err := project.ContentHash()
+	if err != nil {
+		t.Fatalf("ContentHash() returned unexpected error: %v", err)
+	}
+	want, _ := lock.ContentHash([]byte(source))
+	if hash != want {
+		t.Errorf("ContentHash() = %s, want %s", hash, want)
+	}
+
+	l := &lock.Lock{
+		ContentHash:      hash,
+		Packages:         []lock.LockedPackage{{Name: "psr/log", Version: "3.0.0"}},
+		MinimumStability: "stable",
+		Platform:         lock.Platform{"php": ">=8.1"},
+	}
+	freshness, err := IsLockFresh(project, l)
+	if err != nil {
+		t.Fatalf("IsLockFresh() returned unexpected error: %v", err)
+	}
+	if !freshness.Fresh {
+		t.Errorf("IsLockFresh() = %+v, want fresh", freshness)
+	}
+
+	if err := project.AddDependency("monolog/monolog", "^3.0"); err != nil {
+		t.Fatalf("AddDependency() returned unexpected error: %v", err)
+	}
+	freshness, err = IsLockFresh(project, l)
+	if err != nil {
+		t.Fatalf("IsLockFresh() returned unexpected error: %v", err)
+	}
+	if freshness.Fresh || len(freshness.ChangedKeys) != 1 || freshness.ChangedKeys[0] != "require" {
+		t.Errorf("IsLockFresh() = %+v, want stale require", freshness)
+	}
+}
+
+func TestComposerJSON_VerifyLock(t *testing.T) {
+	project, err := ParseString(`{
+		"require": {"php": ">=8.1", "monolog/monolog": "^3.0"},
+		"require-dev": {"phpunit/phpunit": "^10.0"},
+		"conflict": {"psr/log": "<2.0"}
+	}`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	l := &lock.Lock{
+		Packages:    []lock.LockedPackage{{Name: "monolog/monolog", Version: "3.5.0"}, {Name: "psr/log", Version: "1.1.4"}},
+		PackagesDev: []lock.LockedPackage{{Name: "phpunit/phpunit", Version: "9.6.0"}},
+	}
+	findings := project.VerifyLock(l)
+	if len(findings) != 2 {
+		t.Fatalf("VerifyLock() = %v, want 2 findings", findings)
+	}
+	if findings[0].Kind != lock.FindingUnsatisfied || findings[0].Package != "phpunit/phpunit" {
+		t.Errorf("findings[0] = %v, want unsatisfied phpunit/phpunit", findings[0])
+	}
+	if findings[1].Kind != lock.FindingConflict || findings[1].Package != "psr/log" {
+		t.Errorf("findings[1] = %v, want conflict psr/log", findings[1])
+	}
+}
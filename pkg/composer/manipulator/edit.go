@@ -0,0 +1,170 @@
+package manipulator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/document"
+)
+
+// span 表示对象成员或数组元素在源文本中的范围
+type span struct {
+	start, end int
+}
+
+func memberSpans(obj *document.Node) []span {
+	spans := make([]span, 0, len(obj.Members))
+	for _, member := range obj.Members {
+		spans = append(spans, span{member.KeyStart, member.Value.End})
+	}
+	return spans
+}
+
+func elementSpans(arr *document.Node) []span {
+	spans := make([]span, 0, len(arr.Elements))
+	for _, el := range arr.Elements {
+		spans = append(spans, span{el.Start, el.End})
+	}
+	return spans
+}
+
+// splice 用replacement替换源文本中[start, end)的部分并重新解析文档
+func (m *Manipulator) splice(start, end int, replacement []byte) error {
+	src := m.doc.Source
+	out := make([]byte, 0, len(src)-(end-start)+len(replacement))
+	out = append(out, src[:start]...)
+	out = append(out, replacement...)
+	out = append(out, src[end:]...)
+
+	doc, err := document.Parse(out)
+	if err != nil {
+		return fmt.Errorf("edit produced invalid JSON: %v", err)
+	}
+	// 排版风格以编辑前的文档为准
+	doc.Format = m.doc.Format
+	m.doc = doc
+	return nil
+}
+
+// render 将值转换为位于第depth层的源文本
+func (m *Manipulator) render(value interface{}, depth int) ([]byte, error) {
+	n, ok := value.(*document.Node)
+	if !ok {
+		var err error
+		if n, err = document.FromValue(value); err != nil {
+			return nil, err
+		}
+	}
+	return document.RenderAt(n, m.doc.Format, depth), nil
+}
+
+// replaceValue 替换位于第depth层的节点
+func (m *Manipulator) replaceValue(node *document.Node, value interface{}, depth int) error {
+	text, err := m.render(value, depth)
+	if err != nil {
+		return err
+	}
+	return m.splice(node.Start, node.End, text)
+}
+
+// insertMember 在第depth层的对象中插入键值对，before为-1时追加到末尾
+func (m *Manipulator) insertMember(obj *document.Node, before int, key string, value interface{}, depth int) error {
+	text, err := m.render(value, depth+1)
+	if err != nil {
+		return err
+	}
+
+	separator := []byte(": ")
+	if len(obj.Members) > 0 {
+		// 沿用已有成员的键值分隔符，如": "或":"
+		last := obj.Members[len(obj.Members)-1]
+		separator = m.doc.Source[last.KeyStart+len(last.RawKey) : last.Value.Start]
+	}
+
+	item := append(append(quote(key), separator...), text...)
+	return m.insertItem(obj, memberSpans(obj), before, item, '{', '}', depth)
+}
+
+// insertElement 在第depth层的数组中插入元素，at为-1时追加到末尾
+func (m *Manipulator) insertElement(arr *document.Node, at int, value interface{}, depth int) error {
+	text, err := m.render(value, depth+1)
+	if err != nil {
+		return err
+	}
+	return m.insertItem(arr, elementSpans(arr), at, text, '[', ']', depth)
+}
+
+// insertItem 在容器中插入一项，使用相邻项前面的空白作为缩进
+func (m *Manipulator) insertItem(container *document.Node, spans []span, before int, item []byte, open, close byte, depth int) error {
+	f := m.doc.Format
+
+	if len(spans) == 0 {
+		var buf bytes.Buffer
+		buf.WriteByte(open)
+		buf.WriteString(f.Newline + strings.Repeat(f.Indent, depth+1))
+		buf.Write(item)
+		buf.WriteString(f.Newline + strings.Repeat(f.Indent, depth))
+		buf.WriteByte(close)
+		return m.splice(container.Start, container.End, buf.Bytes())
+	}
+
+	// 项与项之间的空白取自最后一项之前（第一项之前可能紧跟着括号而没有空白）
+	last := spans[len(spans)-1]
+	space := m.leadingSpace(last.start)
+
+	if before < 0 || before >= len(spans) {
+		text := append([]byte{','}, space...)
+		return m.splice(last.end, last.end, append(text, item...))
+	}
+
+	next := spans[before]
+	text := append(append(item, ','), space...)
+	return m.splice(next.start, next.start, text)
+}
+
+// removeMember 删除对象中的第i个成员
+func (m *Manipulator) removeMember(obj *document.Node, i int) {
+	m.removeItem(obj, memberSpans(obj), i, "{}")
+}
+
+// removeElement 删除数组中的第i个元素
+func (m *Manipulator) removeElement(arr *document.Node, i int) {
+	m.removeItem(arr, elementSpans(arr), i, "[]")
+}
+
+// removeItem 删除容器中的一项以及与之相邻的分隔符
+func (m *Manipulator) removeItem(container *document.Node, spans []span, i int, empty string) {
+	switch {
+	case len(spans) == 1:
+		m.splice(container.Start, container.End, []byte(empty))
+	case i < len(spans)-1:
+		m.splice(spans[i].start, spans[i+1].start, nil)
+	default:
+		m.splice(spans[i-1].end, spans[i].end, nil)
+	}
+}
+
+// leadingSpace 返回位置pos之前、上一个分隔符之后的空白
+func (m *Manipulator) leadingSpace(pos int) []byte {
+	start := pos
+	for start > 0 {
+		switch m.doc.Source[start-1] {
+		case ' ', '\t', '\r', '\n':
+			start--
+			continue
+		}
+		break
+	}
+	return append([]byte(nil), m.doc.Source[start:pos]...)
+}
+
+// quote 返回键名的JSON字符串字面量，不转义HTML字符
+func quote(s string) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return bytes.TrimRight(buf.Bytes(), "\n")
+}
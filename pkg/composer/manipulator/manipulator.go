@@ -0,0 +1,412 @@
+// Package manipulator 提供直接编辑composer.json源文本的功能
+//
+// 与Composer的JsonManipulator相同，本包的所有操作都作用于原始文本：
+// 只改写被修改的键值对，文件其余部分（键顺序、缩进、换行符、行内数组等）保持不变。
+//
+// 支持的操作包括：
+// - 在require/require-dev等部分添加、更新、删除依赖链接以及按sort-packages规则排序
+// - 添加和删除仓库
+// - 设置和删除config选项
+// - 添加脚本
+// - 添加和删除任意顶层键及其子节点
+package manipulator
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/document"
+)
+
+// Manipulator 在composer.json源文本上执行就地编辑
+type Manipulator struct {
+	doc *document.Document
+}
+
+// New 从composer.json源文本创建Manipulator
+//
+// 参数:
+//   - contents: composer.json的源文本
+//
+// 返回:
+//   - *Manipulator: 编辑器
+//   - error: 如果源文本不是JSON对象，返回错误
+//
+// 示例:
+//
+//	data, _ := os.ReadFile("composer.json")
+//	m, err := manipulator.New(data)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	m.AddLink("require", "monolog/monolog", "^3.0", true)
+//	os.WriteFile("composer.json", m.Contents(), 0644)
+func New(contents []byte) (*Manipulator, error) {
+	if len(bytes.TrimSpace(contents)) == 0 {
+		contents = []byte("{}\n")
+	}
+
+	doc, err := document.Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Root.Kind != document.Object {
+		return nil, fmt.Errorf("composer.json must contain a JSON object, got %s", doc.Root.Kind)
+	}
+
+	return &Manipulator{doc: doc}, nil
+}
+
+// Contents 返回编辑后的源文本
+func (m *Manipulator) Contents() []byte {
+	return m.doc.Source
+}
+
+// AddLink 在require、require-dev、conflict、provide、replace或suggest部分添加或更新一个依赖链接
+//
+// 参数:
+//   - linkType: 链接类型，如"require"、"require-dev"
+//   - packageName: 包名
+//   - constraint: 版本约束
+//   - sortPackages: 是否按Composer的sort-packages规则把新链接插入到有序位置
+//
+// 返回:
+//   - error: 如果编辑失败，返回错误
+//
+// 注意:
+//   - 已存在的链接（包名不区分大小写）只替换版本约束，位置不变
+//   - 链接部分不存在时会在文档末尾创建
+func (m *Manipulator) AddLink(linkType, packageName, constraint string, sortPackages bool) error {
+	links := m.doc.Root.Get(linkType)
+	if links == nil {
+		return m.AddMainKey(linkType, map[string]string{packageName: constraint})
+	}
+	if links.Kind != document.Object {
+		return fmt.Errorf("'%s' must be an object, got %s", linkType, links.Kind)
+	}
+
+	for _, member := range links.Members {
+		if strings.EqualFold(member.Key, packageName) {
+			return m.replaceValue(member.Value, constraint, 2)
+		}
+	}
+
+	before := -1
+	if sortPackages {
+		key := sortKey(packageName)
+		for i, member := range links.Members {
+			if sortKey(member.Key) > key {
+				before = i
+				break
+			}
+		}
+	}
+	return m.insertMember(links, before, packageName, constraint, 1)
+}
+
+// RemoveLink 从链接部分删除一个依赖（包名不区分大小写）
+//
+// 返回:
+//   - bool: 如果依赖存在并被删除返回true，否则返回false
+func (m *Manipulator) RemoveLink(linkType, packageName string) bool {
+	links := m.doc.Root.Get(linkType)
+	if links == nil || links.Kind != document.Object {
+		return false
+	}
+
+	for i, member := range links.Members {
+		if strings.EqualFold(member.Key, packageName) {
+			m.removeMember(links, i)
+			return true
+		}
+	}
+	return false
+}
+
+// SortLinks 按Composer的sort-packages规则对链接部分排序：平台包在前，其余按包名排序（不区分大小写）
+//
+// 只移动键值对本身，键值对之间的空白和分隔符保持原位。
+//
+// 返回:
+//   - bool: 如果链接部分存在并且顺序发生了变化返回true
+func (m *Manipulator) SortLinks(linkType string) bool {
+	links := m.doc.Root.Get(linkType)
+	if links == nil || links.Kind != document.Object || len(links.Members) < 2 {
+		return false
+	}
+
+	order := make([]int, len(links.Members))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return sortKey(links.Members[order[a]].Key) < sortKey(links.Members[order[b]].Key)
+	})
+	if sort.IntsAreSorted(order) {
+		return false
+	}
+
+	src := m.doc.Source
+	spans := memberSpans(links)
+	var buf bytes.Buffer
+	for i, j := range order {
+		if i > 0 {
+			buf.Write(src[spans[i-1].end:spans[i].start])
+		}
+		buf.Write(src[spans[j].start:spans[j].end])
+	}
+	return m.splice(spans[0].start, spans[len(spans)-1].end, buf.Bytes()) == nil
+}
+
+// AddRepository 添加或替换一个仓库
+//
+// 参数:
+//   - name: 仓库名称；repositories为对象时作为键使用，为数组时写入仓库的"name"字段
+//   - config: 仓库配置，如map[string]interface{}{"type": "vcs", "url": "..."}
+//   - appendRepo: repositories为数组时，true表示追加到末尾，false表示插入到开头
+//
+// 返回:
+//   - error: 如果编辑失败，返回错误
+//
+// 注意:
+//   - repositories不存在时会以数组形式创建
+//   - 数组中已存在同名仓库时替换该仓库
+func (m *Manipulator) AddRepository(name string, config interface{}, appendRepo bool) error {
+	repos := m.doc.Root.Get("repositories")
+	if repos != nil && repos.Kind == document.Object {
+		return m.AddSubNode("repositories", name, config)
+	}
+
+	value, err := document.FromValue(config)
+	if err != nil {
+		return err
+	}
+	if name != "" && value.Kind == document.Object && value.Get("name") == nil {
+		value, err = withName(value, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if repos == nil {
+		return m.AddMainKey("repositories", []interface{}{value})
+	}
+	if repos.Kind != document.Array {
+		return fmt.Errorf("'repositories' must be an array or object, got %s", repos.Kind)
+	}
+
+	if i := findRepository(repos, name); i >= 0 {
+		return m.replaceValue(repos.Elements[i], value, 2)
+	}
+
+	at := -1
+	if !appendRepo {
+		at = 0
+	}
+	return m.insertElement(repos, at, value, 1)
+}
+
+// RemoveRepository 删除一个仓库
+//
+// 参数:
+//   - name: repositories为对象时是仓库的键；为数组时匹配仓库的"name"或"url"字段
+//
+// 返回:
+//   - bool: 如果仓库存在并被删除返回true，否则返回false
+func (m *Manipulator) RemoveRepository(name string) bool {
+	repos := m.doc.Root.Get("repositories")
+	if repos == nil {
+		return false
+	}
+	if repos.Kind == document.Object {
+		return m.RemoveSubNode("repositories", name)
+	}
+
+	i := findRepository(repos, name)
+	if i < 0 {
+		return false
+	}
+	m.removeElement(repos, i)
+	return true
+}
+
+// AddConfigSetting 设置一个config选项
+//
+// 名称中的第一个"."用于访问嵌套的键，如"platform.php"、"allow-plugins.acme/plugin"。
+//
+// 示例:
+//
+//	m.AddConfigSetting("sort-packages", true)
+//	m.AddConfigSetting("platform.php", "8.2.0")
+func (m *Manipulator) AddConfigSetting(name string, value interface{}) error {
+	return m.AddSubNode("config", name, value)
+}
+
+// RemoveConfigSetting 删除一个config选项，名称规则与AddConfigSetting相同
+//
+// 返回:
+//   - bool: 如果选项存在并被删除返回true，否则返回false
+func (m *Manipulator) RemoveConfigSetting(name string) bool {
+	return m.RemoveSubNode("config", name)
+}
+
+// AddScript 添加或替换一个脚本
+//
+// 参数:
+//   - name: 脚本名称，如"test"、"post-install-cmd"
+//   - command: 脚本命令，可以是字符串或字符串数组
+func (m *Manipulator) AddScript(name string, command interface{}) error {
+	return m.AddSubNode("scripts", name, command)
+}
+
+// AddMainKey 添加或替换一个顶层键
+//
+// 新键追加在文档末尾，已存在的键只替换值。
+func (m *Manipulator) AddMainKey(key string, value interface{}) error {
+	if member := m.doc.Root.Member(key); member != nil {
+		return m.replaceValue(member.Value, value, 1)
+	}
+	return m.insertMember(m.doc.Root, -1, key, value, 0)
+}
+
+// RemoveMainKey 删除一个顶层键
+//
+// 返回:
+//   - bool: 如果键存在并被删除返回true，否则返回false
+func (m *Manipulator) RemoveMainKey(key string) bool {
+	for i, member := range m.doc.Root.Members {
+		if member.Key == key {
+			m.removeMember(m.doc.Root, i)
+			return true
+		}
+	}
+	return false
+}
+
+// AddSubNode 在顶层键mainNode下添加或替换子节点name
+//
+// 对于config、extra和scripts，名称中的第一个"."用于访问下一层的键。
+// mainNode或中间节点不存在时会自动创建。
+func (m *Manipulator) AddSubNode(mainNode, name string, value interface{}) error {
+	path := splitName(mainNode, name)
+
+	// 找到已存在的最深一层对象
+	parent := m.doc.Root
+	depth := 0
+	for i, key := range path {
+		child := parent.Get(key)
+		if child == nil {
+			// 从缺失的那一层开始构造嵌套值
+			var nested interface{} = value
+			for j := len(path) - 1; j > i; j-- {
+				nested = map[string]interface{}{path[j]: nested}
+			}
+			return m.insertMember(parent, -1, key, nested, depth)
+		}
+		if i == len(path)-1 {
+			return m.replaceValue(child, value, depth+1)
+		}
+		if child.Kind != document.Object {
+			return fmt.Errorf("'%s' must be an object, got %s", strings.Join(path[:i+1], "."), child.Kind)
+		}
+		parent = child
+		depth++
+	}
+	return nil
+}
+
+// RemoveSubNode 删除顶层键mainNode下的子节点name，名称规则与AddSubNode相同
+//
+// 返回:
+//   - bool: 如果子节点存在并被删除返回true，否则返回false
+func (m *Manipulator) RemoveSubNode(mainNode, name string) bool {
+	path := splitName(mainNode, name)
+
+	parent := m.doc.Root
+	for i, key := range path {
+		if parent.Kind != document.Object {
+			return false
+		}
+		if i == len(path)-1 {
+			for j, member := range parent.Members {
+				if member.Key == key {
+					m.removeMember(parent, j)
+					return true
+				}
+			}
+			return false
+		}
+		parent = parent.Get(key)
+		if parent == nil {
+			return false
+		}
+	}
+	return false
+}
+
+// splitName 将mainNode和name拆分为键路径
+func splitName(mainNode, name string) []string {
+	switch mainNode {
+	case "config", "extra", "scripts":
+		if first, rest, ok := strings.Cut(name, "."); ok {
+			return []string{mainNode, first, rest}
+		}
+	}
+	return []string{mainNode, name}
+}
+
+// sortKey 返回Composer的sort-packages排序键：平台包排在普通包之前
+func sortKey(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case name == "php" || strings.HasPrefix(name, "php-"):
+		return "0-" + name
+	case name == "hhvm":
+		return "1-" + name
+	case strings.HasPrefix(name, "ext-"):
+		return "2-" + name
+	case strings.HasPrefix(name, "lib-"):
+		return "3-" + name
+	case !strings.Contains(name, "/"):
+		return "4-" + name
+	default:
+		return "5-" + name
+	}
+}
+
+// findRepository 在数组形式的repositories中按name或url查找仓库
+func findRepository(repos *document.Node, name string) int {
+	if name == "" {
+		return -1
+	}
+	for i, repo := range repos.Elements {
+		for _, key := range []string{"name", "url"} {
+			if v, ok := repo.Get(key).StringValue(); ok && v == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// withName 返回在开头添加了"name"字段的仓库配置
+func withName(value *document.Node, name string) (*document.Node, error) {
+	var buf bytes.Buffer
+	buf.WriteString(`{"name":`)
+	buf.Write(quote(name))
+	for _, member := range value.Members {
+		buf.WriteByte(',')
+		buf.Write(member.RawKey)
+		buf.WriteByte(':')
+		buf.Write(member.Value.Raw)
+	}
+	buf.WriteByte('}')
+
+	doc, err := document.Parse(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return doc.Root, nil
+}
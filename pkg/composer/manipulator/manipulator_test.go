@@ -0,0 +1,306 @@
+package manipulator
+
+import (
+	"strings"
+	"testing"
+)
+
+const sample = `{
+    "name": "vendor/project",
+    "require": {
+        "php": "^8.1",
+        "monolog/monolog": "^3.0",
+        "symfony/console": "^6.0"
+    },
+    "config": {
+        "sort-packages": true
+    }
+}
+`
+
+func mustNew(t *testing.T, src string) *Manipulator {
+	t.Helper()
+	m, err := New([]byte(src))
+	if err != nil {
+		t.Fatalf("New() returned unexpected error: %v", err)
+	}
+	return m
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New([]byte(`[1, 2]`)); err == nil {
+		t.Errorf("New() should reject non-object documents")
+	}
+	if _, err := New([]byte(`{"name": }`)); err == nil {
+		t.Errorf("New() should reject invalid JSON")
+	}
+
+	m := mustNew(t, "")
+	if err := m.AddLink("require", "php", "^8.2", false); err != nil {
+		t.Fatalf("AddLink() returned unexpected error: %v", err)
+	}
+	want := "{\n    \"require\": {\n        \"php\": \"^8.2\"\n    }\n}\n"
+	if got := string(m.Contents()); got != want {
+		t.Errorf("Contents() = %q, want %q", got, want)
+	}
+}
+
+func TestAddLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		linkType string
+		pkg      string
+		version  string
+		sort     bool
+		want     string
+	}{
+		{
+			name:     "Update existing link in place",
+			linkType: "require",
+			pkg:      "Monolog/Monolog",
+			version:  "^3.5",
+			want:     strings.Replace(sample, `"monolog/monolog": "^3.0"`, `"monolog/monolog": "^3.5"`, 1),
+		},
+		{
+			name:     "Append new link",
+			linkType: "require",
+			pkg:      "guzzlehttp/guzzle",
+			version:  "^7.0",
+			want: strings.Replace(sample, `"symfony/console": "^6.0"`,
+				`"symfony/console": "^6.0",
+        "guzzlehttp/guzzle": "^7.0"`, 1),
+		},
+		{
+			name:     "Insert sorted link",
+			linkType: "require",
+			pkg:      "guzzlehttp/guzzle",
+			version:  "^7.0",
+			sort:     true,
+			want: strings.Replace(sample, `"monolog/monolog": "^3.0"`,
+				`"guzzlehttp/guzzle": "^7.0",
+        "monolog/monolog": "^3.0"`, 1),
+		},
+		{
+			name:     "Sorted platform packages go first",
+			linkType: "require",
+			pkg:      "ext-intl",
+			version:  "*",
+			sort:     true,
+			want: strings.Replace(sample, `"php": "^8.1",`,
+				`"php": "^8.1",
+        "ext-intl": "*",`, 1),
+		},
+		{
+			name:     "Create missing section",
+			linkType: "require-dev",
+			pkg:      "phpunit/phpunit",
+			version:  "^10.0",
+			want: strings.Replace(sample, `"sort-packages": true
+    }`, `"sort-packages": true
+    },
+    "require-dev": {
+        "phpunit/phpunit": "^10.0"
+    }`, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mustNew(t, sample)
+			if err := m.AddLink(tt.linkType, tt.pkg, tt.version, tt.sort); err != nil {
+				t.Fatalf("AddLink() returned unexpected error: %v", err)
+			}
+			if got := string(m.Contents()); got != tt.want {
+				t.Errorf("AddLink() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	m := mustNew(t, `{"require": "php"}`)
+	if err := m.AddLink("require", "php", "^8.1", false); err == nil {
+		t.Errorf("AddLink() should fail when the section is not an object")
+	}
+}
+
+func TestRemoveLink(t *testing.T) {
+	tests := []struct {
+		name string
+		pkg  string
+		want string
+	}{
+		{
+			name: "Remove middle link",
+			pkg:  "monolog/monolog",
+			want: strings.Replace(sample, "        \"monolog/monolog\": \"^3.0\",\n", "", 1),
+		},
+		{
+			name: "Remove last link",
+			pkg:  "symfony/console",
+			want: strings.Replace(sample, "\"^3.0\",\n        \"symfony/console\": \"^6.0\"", "\"^3.0\"", 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mustNew(t, sample)
+			if !m.RemoveLink("require", tt.pkg) {
+				t.Fatalf("RemoveLink() returned false for an existing link")
+			}
+			if got := string(m.Contents()); got != tt.want {
+				t.Errorf("RemoveLink() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	m := mustNew(t, `{"require": {"php": "^8.1"}}`)
+	if m.RemoveLink("require", "missing/package") || m.RemoveLink("require-dev", "php") {
+		t.Errorf("RemoveLink() should return false for missing links")
+	}
+	if !m.RemoveLink("require", "php") {
+		t.Fatalf("RemoveLink() returned false for an existing link")
+	}
+	if got := string(m.Contents()); got != `{"require": {}}` {
+		t.Errorf("RemoveLink() = %s, want {\"require\": {}}", got)
+	}
+}
+
+func TestSortLinks(t *testing.T) {
+	m := mustNew(t, "{\n    \"require\": {\n        \"symfony/console\": \"^6.0\",\n        \"ext-json\": \"*\",\n        \"Acme/Lib\": \"^1.0\",  \"php\": \"^8.1\"\n    }\n}\n")
+	if !m.SortLinks("require") {
+		t.Fatal("SortLinks() returned false for unsorted links")
+	}
+	want := "{\n    \"require\": {\n        \"php\": \"^8.1\",\n        \"ext-json\": \"*\",\n        \"Acme/Lib\": \"^1.0\",  \"symfony/console\": \"^6.0\"\n    }\n}\n"
+	if got := string(m.Contents()); got != want {
+		t.Errorf("SortLinks() =\n%s\nwant\n%s", got, want)
+	}
+
+	if m.SortLinks("require") {
+		t.Error("SortLinks() returned true for sorted links")
+	}
+	if m.SortLinks("require-dev") {
+		t.Error("SortLinks() returned true for a missing section")
+	}
+}
+
+func TestRepositories(t *testing.T) {
+	m := mustNew(t, sample)
+
+	if err := m.AddRepository("internal", map[string]string{"type": "composer", "url": "https://repo.example.com"}, true); err != nil {
+		t.Fatalf("AddRepository() returned unexpected error: %v", err)
+	}
+	if err := m.AddRepository("", map[string]string{"type": "path", "url": "../lib"}, false); err != nil {
+		t.Fatalf("AddRepository() returned unexpected error: %v", err)
+	}
+
+	want := strings.Replace(sample, `"sort-packages": true
+    }`, `"sort-packages": true
+    },
+    "repositories": [
+        {
+            "type": "path",
+            "url": "../lib"
+        },
+        {
+            "name": "internal",
+            "type": "composer",
+            "url": "https://repo.example.com"
+        }
+    ]`, 1)
+	if got := string(m.Contents()); got != want {
+		t.Errorf("AddRepository() =\n%s\nwant\n%s", got, want)
+	}
+
+	// 同名仓库被替换
+	if err := m.AddRepository("internal", map[string]string{"type": "composer", "url": "https://mirror.example.com"}, true); err != nil {
+		t.Fatalf("AddRepository() returned unexpected error: %v", err)
+	}
+	if got := string(m.Contents()); strings.Count(got, `"name": "internal"`) != 1 || !strings.Contains(got, "mirror.example.com") {
+		t.Errorf("AddRepository() did not replace the repository:\n%s", got)
+	}
+
+	if !m.RemoveRepository("../lib") || !m.RemoveRepository("internal") {
+		t.Fatalf("RemoveRepository() returned false for existing repositories")
+	}
+	if m.RemoveRepository("missing") {
+		t.Errorf("RemoveRepository() should return false for missing repositories")
+	}
+	if got := string(m.Contents()); !strings.Contains(got, `"repositories": []`) {
+		t.Errorf("RemoveRepository() =\n%s", got)
+	}
+
+	// 对象形式的repositories
+	m = mustNew(t, `{"repositories": {"packagist.org": false}}`)
+	if err := m.AddRepository("local", map[string]string{"type": "path", "url": "../pkg"}, true); err != nil {
+		t.Fatalf("AddRepository() returned unexpected error: %v", err)
+	}
+	if !m.RemoveRepository("packagist.org") {
+		t.Errorf("RemoveRepository() returned false for an existing repository")
+	}
+	if got := string(m.Contents()); !strings.HasPrefix(got, `{"repositories": {"local": {`) {
+		t.Errorf("object repositories = %s", got)
+	}
+}
+
+func TestConfigAndScripts(t *testing.T) {
+	m := mustNew(t, sample)
+
+	if err := m.AddConfigSetting("platform.php", "8.2.0"); err != nil {
+		t.Fatalf("AddConfigSetting() returned unexpected error: %v", err)
+	}
+	if err := m.AddConfigSetting("sort-packages", false); err != nil {
+		t.Fatalf("AddConfigSetting() returned unexpected error: %v", err)
+	}
+	if err := m.AddConfigSetting("platform.ext-intl", "1.0"); err != nil {
+		t.Fatalf("AddConfigSetting() returned unexpected error: %v", err)
+	}
+	if err := m.AddScript("test", []string{"phpunit", "phpstan"}); err != nil {
+		t.Fatalf("AddScript() returned unexpected error: %v", err)
+	}
+
+	want := strings.Replace(sample, `"sort-packages": true
+    }`, `"sort-packages": false,
+        "platform": {
+            "php": "8.2.0",
+            "ext-intl": "1.0"
+        }
+    },
+    "scripts": {
+        "test": [
+            "phpunit",
+            "phpstan"
+        ]
+    }`, 1)
+	if got := string(m.Contents()); got != want {
+		t.Errorf("config edits =\n%s\nwant\n%s", got, want)
+	}
+
+	if !m.RemoveConfigSetting("platform.php") || !m.RemoveSubNode("scripts", "test") {
+		t.Fatalf("removing existing settings returned false")
+	}
+	if m.RemoveConfigSetting("platform.missing") || m.RemoveConfigSetting("missing.key") {
+		t.Errorf("removing missing settings should return false")
+	}
+	if !m.RemoveMainKey("scripts") || m.RemoveMainKey("scripts") {
+		t.Errorf("RemoveMainKey() returned an unexpected result")
+	}
+
+	if err := m.AddConfigSetting("sort-packages.nested", true); err == nil {
+		t.Errorf("AddConfigSetting() should fail when a parent is not an object")
+	}
+}
+
+func TestInlineAndCompactStyles(t *testing.T) {
+	m := mustNew(t, `{"require":{"php":"^8.1"},"keywords":["a", "b"]}`)
+
+	if err := m.AddLink("require", "ext-json", "*", false); err != nil {
+		t.Fatalf("AddLink() returned unexpected error: %v", err)
+	}
+	if err := m.AddMainKey("type", "library"); err != nil {
+		t.Fatalf("AddMainKey() returned unexpected error: %v", err)
+	}
+
+	want := `{"require":{"php":"^8.1","ext-json":"*"},"keywords":["a", "b"],"type":"library"}`
+	if got := string(m.Contents()); got != want {
+		t.Errorf("Contents() = %s, want %s", got, want)
+	}
+}
@@ -1,9 +1,12 @@
 package composer
 
 import (
+	"encoding/json"
+
 	"github.com/scagogogo/php-composer-json-parser/pkg/composer/archive"
 	"github.com/scagogogo/php-composer-json-parser/pkg/composer/autoload"
 	"github.com/scagogogo/php-composer-json-parser/pkg/composer/config"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/document"
 	"github.com/scagogogo/php-composer-json-parser/pkg/composer/repository"
 )
 
@@ -112,6 +115,17 @@ type ComposerJSON struct {
 
 	// PreferStable 优先使用稳定版本
 	PreferStable bool `json:"prefer-stable,omitempty"`
+
+	// Unknown 结构体字段之外的顶层键，值为原始JSON，如"funding"、"readme"、"_comment"
+	//
+	// 解析时自动收集，保存时按原样写回。
+	Unknown map[string]json.RawMessage `json:"-"`
+
+	// source 解析时的原始文档，保存时用于保留键顺序和格式
+	source *document.Document
+
+	// sourceModel 原始文档经过结构体转换后的值，用于区分被删除的键和结构体无法表示的键
+	sourceModel *document.Node
 }
 
 // Author 表示Composer包的作者信息
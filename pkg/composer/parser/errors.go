@@ -0,0 +1,264 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseError 描述JSON语法错误的位置和可能的原因
+//
+// ParseError包装了ErrInvalidJSON，因此仍然可以使用errors.Is(err, parser.ErrInvalidJSON)判断。
+//
+// 示例:
+//
+//	_, err := parser.ParseString(`{"name": "vendor/project",}`)
+//	var parseErr *parser.ParseError
+//	if errors.As(err, &parseErr) {
+//		fmt.Printf("第%d行第%d列: %s\n", parseErr.Line, parseErr.Column, parseErr.Hint)
+//		fmt.Println(parseErr.Snippet)
+//	}
+type ParseError struct {
+	// Line 出错位置的行号，从1开始
+	Line int
+
+	// Column 出错位置的列号（按字符计算），从1开始
+	Column int
+
+	// Offset 出错位置的字节偏移，从0开始
+	Offset int
+
+	// Pointer 出错位置所在值的JSON指针，如"/require"；位于根对象时为空字符串
+	Pointer string
+
+	// Snippet 出错位置所在的整行文本
+	Snippet string
+
+	// Message encoding/json给出的原始错误信息
+	Message string
+
+	// Hint 对常见错误的说明，如尾随逗号、未加引号的键、单引号字符串等；无法判断时为空
+	Hint string
+}
+
+// Error 实现error接口
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v at line %d, column %d", ErrInvalidJSON, e.Line, e.Column)
+	if e.Pointer != "" {
+		fmt.Fprintf(&b, " (%s)", e.Pointer)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.Hint != "" {
+		fmt.Fprintf(&b, "; hint: %s", e.Hint)
+	}
+	return b.String()
+}
+
+// Unwrap 返回ErrInvalidJSON，使errors.Is可以匹配
+func (e *ParseError) Unwrap() error {
+	return ErrInvalidJSON
+}
+
+// newParseError 为无效的JSON数据构造ParseError
+func newParseError(data []byte) *ParseError {
+	offset := len(data)
+	message := "unexpected end of JSON input"
+
+	var v interface{}
+	var syntaxErr *json.SyntaxError
+	if err := json.Unmarshal(data, &v); errors.As(err, &syntaxErr) {
+		message = syntaxErr.Error()
+		// SyntaxError.Offset是已读取的字节数，出错的字符是最后读取的那一个
+		if !strings.HasPrefix(message, "unexpected end") && syntaxErr.Offset > 0 {
+			offset = int(syntaxErr.Offset) - 1
+		}
+	}
+	if offset > len(data) {
+		offset = len(data)
+	}
+
+	line, column, snippet := locate(data, offset)
+	return &ParseError{
+		Line:    line,
+		Column:  column,
+		Offset:  offset,
+		Pointer: pointerAt(data, offset),
+		Snippet: snippet,
+		Message: message,
+		Hint:    hint(data, offset, message),
+	}
+}
+
+// locate 计算字节偏移对应的行号、列号和所在行的文本
+func locate(data []byte, offset int) (int, int, string) {
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	column := utf8.RuneCount(data[lineStart:offset]) + 1
+
+	lineEnd := bytes.IndexByte(data[lineStart:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(data)
+	} else {
+		lineEnd += lineStart
+	}
+	snippet := strings.TrimRight(string(data[lineStart:lineEnd]), "\r")
+
+	return line, column, snippet
+}
+
+// hint 根据出错位置附近的字符推断常见错误
+func hint(data []byte, offset int, message string) string {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return "input is empty"
+	}
+	if strings.Contains(message, "escape") {
+		return "invalid escape sequence in string"
+	}
+	if strings.Contains(message, "in string literal") {
+		return "strings cannot contain raw line breaks or control characters"
+	}
+	if offset >= len(data) {
+		return "unexpected end of input, check for a missing closing brace or bracket"
+	}
+
+	c := data[offset]
+	prev := previousToken(data, offset)
+
+	switch {
+	case c == '\'':
+		return "strings and keys must use double quotes, not single quotes"
+	case c == '/' || c == '#':
+		return "comments are not allowed in JSON"
+	case (c == '}' || c == ']') && prev == ',':
+		return "trailing comma before closing brace or bracket is not allowed"
+	case (prev == '{' || prev == ',') && isIdentStart(c) && insideObject(data, offset):
+		return "object keys must be double-quoted strings"
+	case c == '"' && (prev == '"' || prev == '}' || prev == ']' || isValueEnd(prev)):
+		return "missing comma between elements"
+	case (c == '{' || c == '[') && (prev == '"' || prev == '}' || prev == ']'):
+		return "missing comma between elements"
+	case c == ',' && (prev == ',' || prev == '[' || prev == '{'):
+		return "missing value between commas"
+	case c == '=':
+		return "use ':' to separate keys and values"
+	}
+	return ""
+}
+
+// previousToken 返回offset之前最近的非空白字符，没有时返回0
+func previousToken(data []byte, offset int) byte {
+	for i := offset - 1; i >= 0; i-- {
+		switch data[i] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return data[i]
+	}
+	return 0
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isValueEnd(c byte) bool {
+	return (c >= '0' && c <= '9') || c == 'e' || c == 'l'
+}
+
+// frame 是pointerAt扫描时的容器状态
+type frame struct {
+	object   bool
+	key      string
+	hasValue bool // 对象已读到':'，即当前位于值的位置
+	index    int
+}
+
+// scanFrames 扫描data[:offset]，返回出错位置所在的容器栈
+func scanFrames(data []byte, offset int) []frame {
+	var stack []frame
+	for i := 0; i < offset && i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			start := i
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			if i >= offset || len(stack) == 0 {
+				continue
+			}
+			top := &stack[len(stack)-1]
+			if top.object && !top.hasValue {
+				var key string
+				if err := json.Unmarshal(data[start:i+1], &key); err == nil {
+					top.key = key
+				} else {
+					top.key = string(data[start+1 : i])
+				}
+			}
+		case '{':
+			stack = append(stack, frame{object: true})
+		case '[':
+			stack = append(stack, frame{})
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case ':':
+			if len(stack) > 0 {
+				stack[len(stack)-1].hasValue = true
+			}
+		case ',':
+			if len(stack) > 0 {
+				top := &stack[len(stack)-1]
+				if top.object {
+					top.hasValue = false
+				} else {
+					top.index++
+				}
+			}
+		}
+	}
+	return stack
+}
+
+// insideObject 判断offset是否直接位于一个对象中
+func insideObject(data []byte, offset int) bool {
+	stack := scanFrames(data, offset)
+	return len(stack) > 0 && stack[len(stack)-1].object
+}
+
+// pointerAt 返回offset所在值的JSON指针（RFC 6901）
+func pointerAt(data []byte, offset int) string {
+	var b strings.Builder
+	for _, f := range scanFrames(data, offset) {
+		switch {
+		case f.object && f.hasValue:
+			b.WriteByte('/')
+			b.WriteString(EscapePointer(f.key))
+		case !f.object:
+			b.WriteByte('/')
+			b.WriteString(strconv.Itoa(f.index))
+		}
+	}
+	return b.String()
+}
+
+// EscapePointer 按RFC 6901转义JSON指针中的一段：把"~"替换为"~0"，把"/"替换为"~1"
+//
+// 示例:
+//
+//	"/require/" + parser.EscapePointer("psr/log") // "/require/psr~1log"
+func EscapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantLine    int
+		wantColumn  int
+		wantPointer string
+		wantSnippet string
+		wantHint    string
+	}{
+		{
+			name: "Trailing comma",
+			input: `{
+    "name": "vendor/project",
+    "require": {
+        "php": "^8.1",
+    }
+}`,
+			wantLine:    5,
+			wantColumn:  5,
+			wantPointer: "/require",
+			wantSnippet: "    }",
+			wantHint:    "trailing comma",
+		},
+		{
+			name:        "Unquoted key",
+			input:       "{\n  name: \"vendor/project\"\n}",
+			wantLine:    2,
+			wantColumn:  3,
+			wantPointer: "",
+			wantSnippet: "  name: \"vendor/project\"",
+			wantHint:    "double-quoted",
+		},
+		{
+			name:        "Single quotes",
+			input:       `{"require": {'php': '^8.1'}}`,
+			wantLine:    1,
+			wantColumn:  14,
+			wantPointer: "/require",
+			wantHint:    "single quotes",
+		},
+		{
+			name:        "Missing comma in array",
+			input:       "{\n  \"keywords\": [\"a\" \"b\"]\n}",
+			wantLine:    2,
+			wantColumn:  20,
+			wantPointer: "/keywords/0",
+			wantHint:    "missing comma",
+		},
+		{
+			name:        "Comment",
+			input:       "{\r\n  // comment\r\n  \"name\": \"a/b\"\r\n}",
+			wantLine:    2,
+			wantColumn:  3,
+			wantSnippet: "  // comment",
+			wantHint:    "comments",
+		},
+		{
+			name:        "Unexpected end",
+			input:       `{"autoload": {"psr-4": {"App\\": "src/"`,
+			wantLine:    1,
+			wantColumn:  40,
+			wantPointer: "/autoload/psr-4/App\\",
+			wantHint:    "missing closing",
+		},
+		{
+			name:        "Escaped pointer tokens",
+			input:       `{"extra": {"a/b~c": [1, 2,]}}`,
+			wantLine:    1,
+			wantColumn:  27,
+			wantPointer: "/extra/a~1b~0c/2",
+			wantHint:    "trailing comma",
+		},
+		{
+			name:       "Empty input",
+			input:      "",
+			wantLine:   1,
+			wantColumn: 1,
+			wantHint:   "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseString(tt.input)
+			if !errors.Is(err, ErrInvalidJSON) {
+				t.Fatalf("ParseString() error = %v, want ErrInvalidJSON", err)
+			}
+
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("ParseString() error = %T, want *ParseError", err)
+			}
+
+			if parseErr.Line != tt.wantLine || parseErr.Column != tt.wantColumn {
+				t.Errorf("position = %d:%d, want %d:%d", parseErr.Line, parseErr.Column, tt.wantLine, tt.wantColumn)
+			}
+			if parseErr.Pointer != tt.wantPointer {
+				t.Errorf("Pointer = %q, want %q", parseErr.Pointer, tt.wantPointer)
+			}
+			if tt.wantSnippet != "" && parseErr.Snippet != tt.wantSnippet {
+				t.Errorf("Snippet = %q, want %q", parseErr.Snippet, tt.wantSnippet)
+			}
+			if !strings.Contains(parseErr.Hint, tt.wantHint) {
+				t.Errorf("Hint = %q, want it to contain %q", parseErr.Hint, tt.wantHint)
+			}
+			if !strings.Contains(err.Error(), "invalid JSON format at line") {
+				t.Errorf("Error() = %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestParseErrorOffset(t *testing.T) {
+	input := "{\n  \"név\": \"ü\",\n}"
+	_, err := ParseString(input)
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("ParseString() error = %v, want *ParseError", err)
+	}
+	if input[parseErr.Offset] != '}' {
+		t.Errorf("Offset %d points at %q, want '}'", parseErr.Offset, input[parseErr.Offset])
+	}
+	if parseErr.Line != 3 || parseErr.Column != 1 {
+		t.Errorf("position = %d:%d, want 3:1", parseErr.Line, parseErr.Column)
+	}
+}
+
+func TestEscapePointer(t *testing.T) {
+	tests := map[string]string{
+		"name":        "name",
+		"psr/log":     "psr~1log",
+		"a~b":         "a~0b",
+		"~/":          "~0~1",
+		"Acme\\Lib\\": "Acme\\Lib\\",
+	}
+	for token, want := range tests {
+		if got := EscapePointer(token); got != want {
+			t.Errorf("EscapePointer(%q) = %q, want %q", token, got, want)
+		}
+	}
+}
@@ -43,6 +43,23 @@ var (
 //		log.Fatal(err)
 //	}
 func ParseFile(filePath string) (map[string]interface{}, error) {
+	data, err := ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseBytes(data)
+}
+
+// ReadFile 读取composer.json文件的原始字节
+//
+// 参数:
+//   - filePath: composer.json文件路径
+//
+// 返回:
+//   - []byte: 文件的原始内容
+//   - error: 文件不存在时返回ErrFileNotFound，读取失败时返回ErrReadingFile
+func ReadFile(filePath string) ([]byte, error) {
 	// 检查文件是否存在
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, filePath)
@@ -55,7 +72,12 @@ func ParseFile(filePath string) (map[string]interface{}, error) {
 	}
 	defer file.Close()
 
-	return Parse(file)
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReadingFile, err)
+	}
+
+	return data, nil
 }
 
 // ParseDir 在指定目录中查找并解析composer.json文件
@@ -86,9 +108,21 @@ func Parse(r io.Reader) (map[string]interface{}, error) {
 		return nil, fmt.Errorf("%w: %v", ErrReadingFile, err)
 	}
 
-	// 验证JSON
+	return ParseBytes(data)
+}
+
+// ParseBytes 解析composer.json的原始字节
+//
+// 参数:
+//   - data: 要解析的JSON数据
+//
+// 返回:
+//   - map[string]interface{}: 解析后的原始JSON数据
+//   - error: 如果解析失败，返回错误；JSON语法错误时返回*ParseError
+func ParseBytes(data []byte) (map[string]interface{}, error) {
+	// 验证JSON，失败时返回带有位置信息的ParseError
 	if !json.Valid(data) {
-		return nil, ErrInvalidJSON
+		return nil, newParseError(data)
 	}
 
 	var result map[string]interface{}
@@ -108,15 +142,5 @@ func Parse(r io.Reader) (map[string]interface{}, error) {
 //   - map[string]interface{}: 解析后的原始JSON数据
 //   - error: 如果解析失败，返回错误
 func ParseString(jsonStr string) (map[string]interface{}, error) {
-	// 验证JSON
-	if !json.Valid([]byte(jsonStr)) {
-		return nil, ErrInvalidJSON
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrUnmarshallingJSON, err)
-	}
-
-	return result, nil
+	return ParseBytes([]byte(jsonStr))
 }
@@ -0,0 +1,140 @@
+package composer
+
+import (
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/dependency"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/manipulator"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/platform"
+)
+
+// PlatformRequirements 返回require中的平台包依赖，如"php"、"ext-intl"、"lib-icu"
+//
+// 参数:
+//   - includeDev: 是否包括require-dev中的平台包依赖，同一个包在两处都有时使用require-dev中的约束
+//
+// 返回:
+//   - map[string]string: 新的依赖映射，key为包名，value为版本约束；没有平台包依赖时为空映射
+//
+// 示例:
+//
+//	composer, _ := composer.ParseFile("./composer.json")
+//
+//	for name, c := range composer.PlatformRequirements(false) {
+//		fmt.Printf("%s: %s\n", name, c) // php: >=8.1
+//	}
+func (c *ComposerJSON) PlatformRequirements(includeDev bool) map[string]string {
+	platform, _ := dependency.SplitRequirements(c.requirements(includeDev))
+	return platform
+}
+
+// PackageRequirements 返回require中需要安装的包依赖，即去掉平台包之后的依赖
+//
+// 参数:
+//   - includeDev: 是否包括require-dev中的依赖，同一个包在两处都有时使用require-dev中的约束
+//
+// 返回:
+//   - map[string]string: 新的依赖映射，key为包名，value为版本约束；没有包依赖时为空映射
+//
+// 示例:
+//
+//	composer, _ := composer.ParseFile("./composer.json")
+//
+//	packages := composer.PackageRequirements(true)
+//	fmt.Println("需要安装", len(packages), "个包")
+func (c *ComposerJSON) PackageRequirements(includeDev bool) map[string]string {
+	_, packages := dependency.SplitRequirements(c.requirements(includeDev))
+	return packages
+}
+
+// requirements 返回require，includeDev为true时合并require-dev
+func (c *ComposerJSON) requirements(includeDev bool) map[string]string {
+	if includeDev {
+		return dependency.MergeDependencies(c.Require, c.RequireDev)
+	}
+	return c.Require
+}
+
+// PlatformOverrides 返回config.platform中模拟的平台包版本
+//
+// 返回:
+//   - map[string]string: 新的映射，key为平台包名，value为版本；没有设置时为空映射
+func (c *ComposerJSON) PlatformOverrides() map[string]string {
+	overrides := make(map[string]string, len(c.Config.Platform))
+	for name, v := range c.Config.Platform {
+		overrides[name] = v
+	}
+	return overrides
+}
+
+// SetPlatformOverride 在config.platform中设置平台包的模拟版本
+//
+// 参数:
+//   - name: 平台包名，如"php"、"ext-intl"
+//   - version: 模拟的版本，如"8.1.2"
+//
+// 返回:
+//   - error: 如果name不是平台包或version不是有效版本，返回错误
+//
+// 示例:
+//
+//	composer, _ := composer.ParseFile("./composer.json")
+//
+//	// 无论本机安装的PHP版本如何，都按PHP 8.1.2解析依赖
+//	if err := composer.SetPlatformOverride("php", "8.1.2"); err != nil {
+//		log.Fatal(err)
+//	}
+//	composer.Save("./composer.json", true)
+func (c *ComposerJSON) SetPlatformOverride(name, version string) error {
+	if err := c.Config.SetPlatform(name, version); err != nil {
+		return err
+	}
+
+	c.editSource(func(m *manipulator.Manipulator) error {
+		return m.AddConfigSetting("platform."+name, version)
+	})
+	return nil
+}
+
+// RemovePlatformOverride 从config.platform中移除平台包的模拟版本
+//
+// 返回:
+//   - bool: 如果成功移除返回true，如果config.platform中没有该包返回false
+func (c *ComposerJSON) RemovePlatformOverride(name string) bool {
+	if !c.Config.RemovePlatform(name) {
+		return false
+	}
+
+	c.editSource(func(m *manipulator.Manipulator) error {
+		m.RemoveConfigSetting("platform." + name)
+		return nil
+	})
+	return true
+}
+
+// CheckPlatform 检查运行环境是否满足composer.json和composer.lock中的平台包依赖，与`composer check-platform-reqs`对应
+//
+// 参数:
+//   - rt: 运行环境，可以由platform.LoadRuntime从`php -v`和`php -m`的输出读取
+//   - l: 解析后的composer.lock，为nil时只检查composer.json中的依赖
+//   - includeDev: 是否包括require-dev和packages-dev中的依赖
+//   - opts: 检查选项；Overrides为nil时使用config.platform
+//
+// 返回:
+//   - *platform.Report: 每个被依赖的平台包的结果
+//
+// 示例:
+//
+//	project, _ := composer.ParseDir(".")
+//	l, _ := composer.ParseLockDir(".")
+//	rt, _ := platform.LoadRuntime("php-v.txt", "php-m.txt")
+//
+//	report := project.CheckPlatform(rt, l, false, platform.Options{Ignore: []string{"ext-xdebug"}})
+//	if !report.OK() {
+//		fmt.Print(report)
+//	}
+func (c *ComposerJSON) CheckPlatform(rt *platform.Runtime, l *lock.Lock, includeDev bool, opts platform.Options) *platform.Report {
+	if opts.Overrides == nil {
+		opts.Overrides = c.Config.Platform
+	}
+	return platform.Check(rt, platform.Requirements(c.rootPackage(), l, includeDev), opts)
+}
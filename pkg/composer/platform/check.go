@@ -0,0 +1,358 @@
+package platform
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/constraint"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/dependency"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/version"
+)
+
+// rootName 是根包没有名称时使用的来源名，与Composer一致
+const rootName = "__root__"
+
+// Requirement 是一个包对平台包的依赖
+type Requirement struct {
+	// Package 被依赖的平台包名，如"php"、"ext-intl"
+	Package string
+
+	// Constraint 版本约束
+	Constraint string
+
+	// Source 声明依赖的包：根包名或锁定包名
+	Source string
+
+	// Dev 是否来自根包的require-dev或锁文件的packages-dev
+	Dev bool
+}
+
+// String 返回依赖的描述，如"acme/lib requires php (^8.1)"
+func (r Requirement) String() string {
+	return fmt.Sprintf("%s requires %s (%s)", r.Source, r.Package, r.Constraint)
+}
+
+// Requirements 收集根包和锁定包中的平台包依赖
+//
+// 参数:
+//   - root: 根包，使用其Name、Require和RequireDev
+//   - l: 解析后的锁文件，为nil时只收集根包的依赖；锁定包只使用require
+//   - includeDev: 是否包括根包的require-dev和锁文件的packages-dev
+//
+// 返回:
+//   - []Requirement: 先根包后锁定包，同一个包的依赖按平台包名排序
+func Requirements(root lock.LockedPackage, l *lock.Lock, includeDev bool) []Requirement {
+	name := root.Name
+	if name == "" {
+		name = rootName
+	}
+
+	var reqs []Requirement
+	reqs = appendRequirements(reqs, name, root.Require, false)
+	if includeDev {
+		reqs = appendRequirements(reqs, name, root.RequireDev, true)
+	}
+	if l != nil {
+		for _, pkg := range l.Packages {
+			reqs = appendRequirements(reqs, pkg.Name, pkg.Require, false)
+		}
+		if includeDev {
+			for _, pkg := range l.PackagesDev {
+				reqs = appendRequirements(reqs, pkg.Name, pkg.Require, true)
+			}
+		}
+	}
+	return reqs
+}
+
+// appendRequirements 把一组require中的平台包依赖追加到reqs
+func appendRequirements(reqs []Requirement, source string, require map[string]string, dev bool) []Requirement {
+	for _, name := range sortedKeys(require) {
+		if dependency.IsPlatformPackage(name) {
+			reqs = append(reqs, Requirement{Package: name, Constraint: require[name], Source: source, Dev: dev})
+		}
+	}
+	return reqs
+}
+
+// Options 控制平台包检查
+type Options struct {
+	// Overrides config.platform中模拟的平台包版本，覆盖运行环境提供的版本，也可以提供运行环境中没有的包
+	Overrides map[string]string
+
+	// Ignore 忽略的平台包，与--ignore-platform-req的规则一致
+	//
+	// 可以是包名（如"ext-intl"）、带"*"的通配符（如"ext-*"），以"+"结尾时只忽略版本上限，如"php+"允许更高的PHP版本。
+	Ignore []string
+
+	// IgnoreAll 忽略所有平台包依赖，对应--ignore-platform-reqs
+	IgnoreAll bool
+}
+
+// Status 表示一个平台包的检查结果
+type Status string
+
+// 检查结果定义，与`composer check-platform-reqs`的输出一致
+const (
+	// StatusSuccess 平台包存在且满足所有依赖
+	StatusSuccess Status = "success"
+
+	// StatusFailed 平台包存在，但版本不满足至少一个依赖
+	StatusFailed Status = "failed"
+
+	// StatusMissing 运行环境没有提供该平台包
+	StatusMissing Status = "missing"
+
+	// StatusIgnored 平台包被Options.Ignore或Options.IgnoreAll忽略
+	StatusIgnored Status = "ignored"
+
+	// StatusSkipped 运行环境没有描述Composer的版本，不检查composer、composer-plugin-api和composer-runtime-api
+	StatusSkipped Status = "skipped"
+)
+
+// Result 是一个平台包的检查结果
+type Result struct {
+	// Package 小写的平台包名
+	Package string
+
+	// Version 运行环境或config.platform提供的版本，缺失时为空
+	Version string
+
+	// Overridden 版本是否来自config.platform
+	Overridden bool
+
+	// Status 检查结果
+	Status Status
+
+	// Requirements 对该平台包的所有依赖
+	Requirements []Requirement
+
+	// Failed 不满足的依赖；StatusMissing时为所有依赖
+	Failed []Requirement
+}
+
+// Report 是平台包检查的结果
+type Report struct {
+	// Results 每个被依赖的平台包的结果，按包名排序
+	Results []Result
+}
+
+// OK 判断是否所有平台包依赖都满足
+func (r *Report) OK() bool {
+	return len(r.Problems()) == 0
+}
+
+// Problems 返回缺失或版本不匹配的平台包
+func (r *Report) Problems() []Result {
+	var problems []Result
+	for _, res := range r.Results {
+		if res.Status == StatusFailed || res.Status == StatusMissing {
+			problems = append(problems, res)
+		}
+	}
+	return problems
+}
+
+// String 以类似`composer check-platform-reqs`的格式输出结果，每行一个平台包
+//
+// 示例:
+//
+//	ext-intl   8.1.2                                                   success
+//	ext-redis  5.3.0 (config.platform)                                 success
+//	ext-xdebug n/a                     acme/app requires ext-xdebug (^3.0) missing
+//	php        8.1.2                   acme/lib requires php (>=8.2)       failed
+func (r *Report) String() string {
+	rows := make([][3]string, 0, len(r.Results))
+	for _, res := range r.Results {
+		v := res.Version
+		if v == "" {
+			v = "n/a"
+		} else if res.Overridden {
+			v += " (config.platform)"
+		}
+		failed := make([]string, 0, len(res.Failed))
+		for _, req := range res.Failed {
+			failed = append(failed, req.String())
+		}
+		rows = append(rows, [3]string{res.Package, v, strings.Join(failed, ", ")})
+	}
+
+	var widths [3]int
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		fmt.Fprintf(&b, "%-*s %-*s %-*s %s\n", widths[0], row[0], widths[1], row[1], widths[2], row[2], r.Results[i].Status)
+	}
+	return b.String()
+}
+
+// Check 检查运行环境是否满足平台包依赖
+//
+// 参数:
+//   - rt: 运行环境
+//   - reqs: 平台包依赖，通常由Requirements收集
+//   - opts: config.platform和忽略规则
+//
+// 返回:
+//   - *Report: 每个被依赖的平台包的结果
+//
+// 检查规则:
+//   - 包名比较不区分大小写
+//   - 无效的版本约束视为不满足
+//   - 运行环境提供的版本无法解析时，与Composer一致去掉"-"、"+"或"~"之后的后缀，仍无法解析时视为"0"
+//
+// 示例:
+//
+//	rt, _ := platform.LoadRuntime("php-v.txt", "php-m.txt")
+//	l, _ := lock.ParseFile("composer.lock")
+//
+//	reqs := platform.Requirements(root, l, true)
+//	report := platform.Check(rt, reqs, platform.Options{Ignore: []string{"ext-xdebug", "php+"}})
+//	if !report.OK() {
+//		fmt.Print(report)
+//		os.Exit(2)
+//	}
+func Check(rt *Runtime, reqs []Requirement, opts Options) *Report {
+	provided := rt.Packages()
+	overridden := make(map[string]bool)
+	for name, v := range opts.Overrides {
+		key := strings.ToLower(name)
+		provided[key] = v
+		overridden[key] = true
+	}
+
+	byPackage := make(map[string][]Requirement)
+	for _, req := range reqs {
+		key := strings.ToLower(req.Package)
+		byPackage[key] = append(byPackage[key], req)
+	}
+	names := make([]string, 0, len(byPackage))
+	for name := range byPackage {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	filter := newIgnoreFilter(opts)
+	report := &Report{Results: []Result{}}
+	for _, name := range names {
+		res := Result{Package: name, Requirements: byPackage[name], Overridden: overridden[name]}
+		v, ok := provided[name]
+		if ok {
+			res.Version = v
+		}
+
+		switch {
+		case filter.ignoresAll(name):
+			res.Status = StatusIgnored
+		case !ok && strings.HasPrefix(name, "composer"):
+			res.Status = StatusSkipped
+		case !ok:
+			res.Status, res.Failed = StatusMissing, res.Requirements
+		default:
+			for _, req := range res.Requirements {
+				if !satisfies(req.Constraint, v, filter.ignoresUpperBound(name)) {
+					res.Failed = append(res.Failed, req)
+				}
+			}
+			res.Status = StatusSuccess
+			if len(res.Failed) > 0 {
+				res.Status = StatusFailed
+			}
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
+
+// satisfies 判断版本是否满足约束，ignoreUpperBound为true时只检查约束的下限
+func satisfies(c, v string, ignoreUpperBound bool) bool {
+	con, err := constraint.Parse(c)
+	if err != nil {
+		return false
+	}
+	if ignoreUpperBound {
+		con = lowerBound(con)
+	}
+	return con.Matches(providedVersion(v))
+}
+
+// lowerBound 返回只保留约束下限的约束，与Composer的IgnoreListPlatformRequirementFilter一致
+func lowerBound(c constraint.Constraint) constraint.Constraint {
+	set := constraint.SetOf(c)
+	if len(set.Intervals) == 0 {
+		return c
+	}
+	start := set.Intervals[0].Start
+	op := constraint.OpGreater
+	if start.Inclusive {
+		op = constraint.OpGreaterOrEqual
+	}
+	return &constraint.Single{Operator: op, Version: start.Version}
+}
+
+// providedVersion 返回可以规范化的版本，规则见Check
+func providedVersion(v string) string {
+	if _, err := version.Normalize(v); err == nil {
+		return v
+	}
+	if i := strings.IndexAny(v, "-+~"); i > 0 {
+		if _, err := version.Normalize(v[:i]); err == nil {
+			return v[:i]
+		}
+	}
+	return "0"
+}
+
+// ignoreFilter 实现--ignore-platform-req的匹配规则
+type ignoreFilter struct {
+	all        bool
+	full       []*regexp.Regexp
+	upperBound []*regexp.Regexp
+}
+
+// newIgnoreFilter 根据选项创建忽略规则
+func newIgnoreFilter(opts Options) ignoreFilter {
+	f := ignoreFilter{all: opts.IgnoreAll}
+	for _, pattern := range opts.Ignore {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		upper := strings.HasSuffix(pattern, "+")
+		pattern = strings.TrimSuffix(pattern, "+")
+		re := regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$")
+		if upper {
+			f.upperBound = append(f.upperBound, re)
+		} else {
+			f.full = append(f.full, re)
+		}
+	}
+	return f
+}
+
+// ignoresAll 判断是否完全忽略平台包
+func (f ignoreFilter) ignoresAll(name string) bool {
+	return f.all || matchAny(f.full, name)
+}
+
+// ignoresUpperBound 判断是否忽略平台包依赖的版本上限
+func (f ignoreFilter) ignoresUpperBound(name string) bool {
+	return matchAny(f.upperBound, name)
+}
+
+// matchAny 判断名称是否匹配任一正则表达式
+func matchAny(patterns []*regexp.Regexp, name string) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
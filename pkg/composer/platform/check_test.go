@@ -0,0 +1,144 @@
+package platform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+)
+
+func testRequirements() []Requirement {
+	root := lock.LockedPackage{
+		Name:       "acme/app",
+		Require:    map[string]string{"php": "^8.1", "ext-intl": "*", "monolog/monolog": "^3.0"},
+		RequireDev: map[string]string{"ext-xdebug": "^3.0"},
+	}
+	l := &lock.Lock{
+		Packages: []lock.LockedPackage{
+			{Name: "acme/lib", Version: "1.0.0", Require: map[string]string{"php": ">=8.2", "ext-redis": "^5.0"}},
+		},
+		PackagesDev: []lock.LockedPackage{
+			{Name: "phpunit/phpunit", Version: "10.5.0", Require: map[string]string{"ext-dom": "*"}},
+		},
+	}
+	return Requirements(root, l, true)
+}
+
+func TestRequirements(t *testing.T) {
+	reqs := testRequirements()
+	var got []string
+	for _, r := range reqs {
+		got = append(got, r.String())
+	}
+	want := []string{
+		"acme/app requires ext-intl (*)",
+		"acme/app requires php (^8.1)",
+		"acme/app requires ext-xdebug (^3.0)",
+		"acme/lib requires ext-redis (^5.0)",
+		"acme/lib requires php (>=8.2)",
+		"phpunit/phpunit requires ext-dom (*)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Requirements() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !reqs[2].Dev || reqs[0].Dev {
+		t.Errorf("Requirements() Dev flags = %+v", reqs)
+	}
+
+	if got := Requirements(lock.LockedPackage{Require: map[string]string{"php": "*"}}, nil, false); len(got) != 1 || got[0].Source != "__root__" {
+		t.Errorf("Requirements() without name = %+v", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rt := &Runtime{
+		PHP:        "8.1.2",
+		Extensions: map[string]string{"intl": "", "dom": "", "redis": "6.0.2"},
+	}
+
+	report := Check(rt, testRequirements(), Options{})
+	statuses := make(map[string]Status)
+	for _, res := range report.Results {
+		statuses[res.Package] = res.Status
+	}
+	want := map[string]Status{
+		"ext-dom":    StatusSuccess,
+		"ext-intl":   StatusSuccess,
+		"ext-redis":  StatusFailed,
+		"ext-xdebug": StatusMissing,
+		"php":        StatusFailed,
+	}
+	for name, status := range want {
+		if statuses[name] != status {
+			t.Errorf("Check() %s = %q, want %q", name, statuses[name], status)
+		}
+	}
+	if report.OK() || len(report.Problems()) != 3 {
+		t.Errorf("Problems() = %+v", report.Problems())
+	}
+
+	php := report.Results[len(report.Results)-1]
+	if php.Package != "php" || len(php.Failed) != 1 || php.Failed[0].Source != "acme/lib" {
+		t.Errorf("php result = %+v", php)
+	}
+	if out := report.String(); !strings.Contains(out, "acme/lib requires php (>=8.2)") || !strings.Contains(out, "missing") {
+		t.Errorf("String() =\n%s", out)
+	}
+}
+
+func TestCheck_OverridesAndIgnore(t *testing.T) {
+	rt := &Runtime{PHP: "8.1.2", Extensions: map[string]string{"intl": "", "dom": "", "redis": "6.0.2"}}
+
+	report := Check(rt, testRequirements(), Options{
+		Overrides: map[string]string{"php": "8.2.0", "ext-xdebug": "3.3.0"},
+		Ignore:    []string{"ext-red*"},
+	})
+	if !report.OK() {
+		t.Errorf("Check() problems = %+v", report.Problems())
+	}
+	for _, res := range report.Results {
+		if res.Package == "php" && (!res.Overridden || res.Version != "8.2.0") {
+			t.Errorf("php result = %+v, want overridden 8.2.0", res)
+		}
+		if res.Package == "ext-redis" && res.Status != StatusIgnored {
+			t.Errorf("ext-redis status = %q, want ignored", res.Status)
+		}
+	}
+
+	report = Check(&Runtime{PHP: "9.0.0"}, []Requirement{{Package: "php", Constraint: "^8.1", Source: "acme/app"}}, Options{})
+	if report.OK() {
+		t.Error("Check() with php 9.0.0 and ^8.1 should fail")
+	}
+	report = Check(&Runtime{PHP: "9.0.0"}, []Requirement{{Package: "php", Constraint: "^8.1", Source: "acme/app"}}, Options{Ignore: []string{"php+"}})
+	if !report.OK() {
+		t.Errorf("Check() with php+ ignored = %+v", report.Problems())
+	}
+	report = Check(&Runtime{PHP: "8.0.0"}, []Requirement{{Package: "php", Constraint: "^8.1", Source: "acme/app"}}, Options{Ignore: []string{"php+"}})
+	if report.OK() {
+		t.Error("Check() with php+ ignored should still enforce the lower bound")
+	}
+
+	report = Check(&Runtime{}, testRequirements(), Options{IgnoreAll: true})
+	if !report.OK() {
+		t.Errorf("Check() with IgnoreAll = %+v", report.Problems())
+	}
+}
+
+func TestCheck_ComposerPackages(t *testing.T) {
+	reqs := []Requirement{{Package: "composer-plugin-api", Constraint: "^2.0", Source: "acme/plugin"}}
+
+	if res := Check(&Runtime{PHP: "8.2.0"}, reqs, Options{}).Results[0]; res.Status != StatusSkipped {
+		t.Errorf("composer-plugin-api status = %q, want skipped", res.Status)
+	}
+	if res := Check(&Runtime{PHP: "8.2.0", PluginAPI: "1.1.0"}, reqs, Options{}).Results[0]; res.Status != StatusFailed {
+		t.Errorf("composer-plugin-api status = %q, want failed", res.Status)
+	}
+}
+
+func TestCheck_UnparsableProvidedVersion(t *testing.T) {
+	reqs := []Requirement{{Package: "ext-foo", Constraint: ">=1.2", Source: "acme/app"}}
+	rt := &Runtime{PHP: "8.2.0", Extensions: map[string]string{"foo": "1.2.3-beta_custom build"}}
+	if res := Check(rt, reqs, Options{}).Results[0]; res.Status != StatusSuccess {
+		t.Errorf("ext-foo status = %q, want success", res.Status)
+	}
+}
@@ -0,0 +1,249 @@
+// Package platform 提供平台包依赖（如"php"、"ext-intl"、"lib-icu"）的检查
+//
+// 本包与`composer check-platform-reqs`对应，包括：
+// - 描述PHP运行环境的Runtime，可以直接构造，也可以从`php -v`、`php -m`或get_loaded_extensions()的JSON输出解析
+// - 从composer.json和composer.lock收集平台包依赖
+// - 按config.platform覆盖运行环境，按--ignore-platform-req的规则忽略依赖
+// - 报告缺失或版本不匹配的平台包
+package platform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/version"
+)
+
+// 错误定义
+var (
+	// ErrInvalidPHPVersion 表示无法从`php -v`的输出中识别PHP版本
+	ErrInvalidPHPVersion = errors.New("invalid php -v output")
+
+	// ErrInvalidExtensions 表示无法解析扩展列表
+	ErrInvalidExtensions = errors.New("invalid extension list")
+)
+
+// Runtime 描述一个PHP运行环境提供的平台包
+//
+// 示例:
+//
+//	rt := &platform.Runtime{
+//		PHP:        "8.2.12",
+//		Is64Bit:    true,
+//		Extensions: map[string]string{"json": "", "intl": "", "redis": "6.0.2"},
+//		Libraries:  map[string]string{"icu": "72.1"},
+//	}
+type Runtime struct {
+	// PHP PHP版本，如"8.2.12"
+	PHP string
+
+	// Is64Bit 是否为64位PHP，为true时提供php-64bit
+	Is64Bit bool
+
+	// IPv6 是否支持IPv6，为true时提供php-ipv6
+	IPv6 bool
+
+	// ZTS 是否为线程安全版本，为true时提供php-zts
+	ZTS bool
+
+	// Debug 是否为调试版本，为true时提供php-debug
+	Debug bool
+
+	// Extensions 已加载的扩展，key为不带"ext-"前缀的扩展名，value为扩展版本
+	//
+	// 版本为空时使用PHP版本，与Composer对PHP自带扩展的处理一致。
+	Extensions map[string]string
+
+	// Libraries 系统库，key为不带"lib-"前缀的库名，如"icu"、"openssl"，value为版本
+	Libraries map[string]string
+
+	// Composer Composer版本，为空时不检查对composer的依赖
+	Composer string
+
+	// PluginAPI composer-plugin-api的版本，为空时不检查对它的依赖
+	PluginAPI string
+
+	// RuntimeAPI composer-runtime-api的版本，为空时不检查对它的依赖
+	RuntimeAPI string
+}
+
+// Packages 返回运行环境提供的所有平台包及其版本
+//
+// 包名都是小写，扩展名中的空格替换为"-"，如"Zend OPcache"对应"ext-zend-opcache"。
+func (rt *Runtime) Packages() map[string]string {
+	packages := make(map[string]string)
+	if rt.PHP != "" {
+		packages["php"] = rt.PHP
+		for name, ok := range map[string]bool{"php-64bit": rt.Is64Bit, "php-ipv6": rt.IPv6, "php-zts": rt.ZTS, "php-debug": rt.Debug} {
+			if ok {
+				packages[name] = rt.PHP
+			}
+		}
+	}
+	for name, v := range rt.Extensions {
+		if v == "" {
+			v = rt.PHP
+		}
+		packages[ExtensionPackage(name)] = v
+	}
+	for name, v := range rt.Libraries {
+		packages["lib-"+strings.ToLower(name)] = v
+	}
+	for name, v := range map[string]string{"composer": rt.Composer, "composer-plugin-api": rt.PluginAPI, "composer-runtime-api": rt.RuntimeAPI} {
+		if v != "" {
+			packages[name] = v
+		}
+	}
+	return packages
+}
+
+// ExtensionPackage 返回扩展对应的平台包名，与Composer的命名规则一致
+//
+// 示例:
+//
+//	platform.ExtensionPackage("Zend OPcache") // "ext-zend-opcache"
+//	platform.ExtensionPackage("PDO")          // "ext-pdo"
+func ExtensionPackage(extension string) string {
+	return "ext-" + strings.ReplaceAll(strings.ToLower(strings.TrimSpace(extension)), " ", "-")
+}
+
+// phpVersionRegex 匹配`php -v`输出的第一行，如"PHP 8.2.12 (cli) (built: Oct 24 2023 21:15:15) (NTS)"
+var phpVersionRegex = regexp.MustCompile(`^PHP (\S+) `)
+
+// ParsePHPVersion 从`php -v`的输出中解析PHP版本
+//
+// 与Composer一致，版本中第一个"-"、"+"或"~"之后的发行版后缀会被去掉，如"8.1.2-1ubuntu2.14"解析为"8.1.2"。
+// 第一行包含"ZTS"或"DEBUG"时，返回的Runtime中ZTS或Debug为true。
+//
+// 返回:
+//   - *Runtime: 只包含PHP版本信息的运行环境
+//   - error: 如果输出中没有可识别的PHP版本，返回ErrInvalidPHPVersion
+func ParsePHPVersion(output string) (*Runtime, error) {
+	line, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	m := phpVersionRegex.FindStringSubmatch(line + " ")
+	if m == nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPHPVersion, line)
+	}
+
+	v := m[1]
+	if i := strings.IndexAny(v, "-+~"); i > 0 {
+		v = v[:i]
+	}
+	if _, err := version.Normalize(v); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPHPVersion, err)
+	}
+
+	build := strings.ToUpper(line)
+	return &Runtime{
+		PHP:   v,
+		ZTS:   strings.Contains(build, "ZTS"),
+		Debug: strings.Contains(build, "DEBUG"),
+	}, nil
+}
+
+// ParseExtensions 解析已加载的扩展列表
+//
+// 支持以下格式：
+//   - `php -m`的输出，忽略"[PHP Modules]"、"[Zend Modules]"等标题和空行
+//   - get_loaded_extensions()的JSON数组，如["Core","json","intl"]
+//   - 扩展名到版本的JSON对象，如{"json":"8.2.12","redis":"6.0.2"}
+//
+// 返回:
+//   - map[string]string: key为小写的扩展名，value为版本，前两种格式中版本为空
+//   - error: 如果JSON无效，返回ErrInvalidExtensions
+//
+// 示例:
+//
+//	out, _ := os.ReadFile("php-m.txt")
+//	extensions, _ := platform.ParseExtensions(out)
+//	fmt.Println(extensions["zend opcache"]) // ""
+func ParseExtensions(data []byte) (map[string]string, error) {
+	extensions := make(map[string]string)
+	trimmed := strings.TrimSpace(string(data))
+
+	switch {
+	case strings.HasPrefix(trimmed, "["+`"`) || trimmed == "[]":
+		var names []string
+		if err := json.Unmarshal([]byte(trimmed), &names); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidExtensions, err)
+		}
+		for _, name := range names {
+			extensions[strings.ToLower(name)] = ""
+		}
+	case strings.HasPrefix(trimmed, "{"):
+		var versions map[string]interface{}
+		if err := json.Unmarshal([]byte(trimmed), &versions); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidExtensions, err)
+		}
+		for name, v := range versions {
+			// phpversion()对没有版本的扩展返回false
+			s, _ := v.(string)
+			extensions[strings.ToLower(name)] = s
+		}
+	default:
+		for _, line := range strings.Split(trimmed, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "[") {
+				continue
+			}
+			extensions[strings.ToLower(line)] = ""
+		}
+	}
+	return extensions, nil
+}
+
+// LoadRuntime 从保存的命令输出文件中读取运行环境
+//
+// 参数:
+//   - versionFile: `php -v`的输出
+//   - extensionsFile: `php -m`的输出或扩展列表的JSON，为空时不读取扩展
+//
+// 返回:
+//   - *Runtime: 运行环境，Libraries和Composer相关的版本需要调用方自行补充
+//   - error: 如果读取或解析失败，返回错误
+//
+// 示例:
+//
+//	// php -v > php-v.txt
+//	// php -r 'echo json_encode(get_loaded_extensions());' > extensions.json
+//	rt, err := platform.LoadRuntime("php-v.txt", "extensions.json")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	rt.Is64Bit = true
+func LoadRuntime(versionFile, extensionsFile string) (*Runtime, error) {
+	data, err := os.ReadFile(versionFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", versionFile, err)
+	}
+	rt, err := ParsePHPVersion(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if extensionsFile != "" {
+		data, err := os.ReadFile(extensionsFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", extensionsFile, err)
+		}
+		if rt.Extensions, err = ParseExtensions(data); err != nil {
+			return nil, err
+		}
+	}
+	return rt, nil
+}
+
+// sortedKeys 返回按字母顺序排列的键
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,126 @@
+package platform
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const phpV = `PHP 8.1.2-1ubuntu2.14 (cli) (built: Aug 18 2023 11:41:11) (NTS)
+Copyright (c) The PHP Group
+Zend Engine v4.1.2, Copyright (c) Zend Technologies
+    with Zend OPcache v8.1.2-1ubuntu2.14, Copyright (c), by Zend Technologies
+`
+
+const phpM = `[PHP Modules]
+Core
+date
+intl
+json
+Xdebug
+Zend OPcache
+
+[Zend Modules]
+Xdebug
+Zend OPcache
+`
+
+func TestParsePHPVersion(t *testing.T) {
+	rt, err := ParsePHPVersion(phpV)
+	if err != nil {
+		t.Fatalf("ParsePHPVersion() error = %v", err)
+	}
+	if rt.PHP != "8.1.2" || rt.ZTS || rt.Debug {
+		t.Errorf("ParsePHPVersion() = %+v, want PHP 8.1.2 NTS", rt)
+	}
+
+	rt, err = ParsePHPVersion("PHP 8.3.0 (cli) (built: Nov 21 2023) (ZTS DEBUG)")
+	if err != nil {
+		t.Fatalf("ParsePHPVersion() error = %v", err)
+	}
+	if !rt.ZTS || !rt.Debug {
+		t.Errorf("ParsePHPVersion() = %+v, want ZTS and Debug", rt)
+	}
+
+	if _, err := ParsePHPVersion("bash: php: command not found"); !errors.Is(err, ErrInvalidPHPVersion) {
+		t.Errorf("ParsePHPVersion() error = %v, want ErrInvalidPHPVersion", err)
+	}
+}
+
+func TestParseExtensions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+	}{
+		{"php -m", phpM, map[string]string{"core": "", "date": "", "intl": "", "json": "", "xdebug": "", "zend opcache": ""}},
+		{"json list", `["Core","json","Zend OPcache"]`, map[string]string{"core": "", "json": "", "zend opcache": ""}},
+		{"json versions", `{"json":"8.2.12","redis":"6.0.2","mysqlnd":false}`, map[string]string{"json": "8.2.12", "redis": "6.0.2", "mysqlnd": ""}},
+		{"empty", "[]", map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExtensions([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("ParseExtensions() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseExtensions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseExtensions([]byte(`{"json":`)); !errors.Is(err, ErrInvalidExtensions) {
+		t.Errorf("ParseExtensions() error = %v, want ErrInvalidExtensions", err)
+	}
+}
+
+func TestRuntime_Packages(t *testing.T) {
+	rt := &Runtime{
+		PHP:        "8.2.12",
+		Is64Bit:    true,
+		Extensions: map[string]string{"intl": "", "Zend OPcache": "", "redis": "6.0.2"},
+		Libraries:  map[string]string{"ICU": "72.1"},
+		PluginAPI:  "2.6.0",
+	}
+
+	want := map[string]string{
+		"php":                 "8.2.12",
+		"php-64bit":           "8.2.12",
+		"ext-intl":            "8.2.12",
+		"ext-zend-opcache":    "8.2.12",
+		"ext-redis":           "6.0.2",
+		"lib-icu":             "72.1",
+		"composer-plugin-api": "2.6.0",
+	}
+	if got := rt.Packages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Packages() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadRuntime(t *testing.T) {
+	dir := t.TempDir()
+	versionFile := filepath.Join(dir, "php-v.txt")
+	extensionsFile := filepath.Join(dir, "php-m.txt")
+	if err := os.WriteFile(versionFile, []byte(phpV), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(extensionsFile, []byte(phpM), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rt, err := LoadRuntime(versionFile, extensionsFile)
+	if err != nil {
+		t.Fatalf("LoadRuntime() error = %v", err)
+	}
+	if rt.PHP != "8.1.2" || len(rt.Extensions) != 6 {
+		t.Errorf("LoadRuntime() = %+v", rt)
+	}
+
+	if _, err := LoadRuntime(filepath.Join(dir, "missing.txt"), ""); err == nil {
+		t.Error("LoadRuntime() with missing file expected error, got nil")
+	}
+}
@@ -0,0 +1,125 @@
+package composer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/platform"
+)
+
+func TestComposerJSON_AddPlatformDependency(t *testing.T) {
+	c, err := CreateNew("acme/app", "An example application")
+	if err != nil {
+		t.Fatalf("CreateNew() returned unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"php", "ext-intl", "lib-icu", "composer-plugin-api"} {
+		if err := c.AddDependency(name, "*"); err != nil {
+			t.Errorf("AddDependency(%q) returned unexpected error: %v", name, err)
+		}
+	}
+	if err := c.AddDevDependency("ext-xdebug", "^3.0"); err != nil {
+		t.Errorf("AddDevDependency(ext-xdebug) returned unexpected error: %v", err)
+	}
+	if err := c.AddDependency("console", "^6.0"); err == nil {
+		t.Error("AddDependency(console) expected error, got nil")
+	}
+}
+
+func TestComposerJSON_PlatformRequirements(t *testing.T) {
+	c, err := ParseString(`{
+		"name": "acme/app",
+		"require": {"php": ">=8.1", "ext-intl": "*", "monolog/monolog": "^3.0"},
+		"require-dev": {"ext-xdebug": "^3.0", "phpunit/phpunit": "^10.0"}
+	}`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	if got, want := c.PlatformRequirements(false), map[string]string{"php": ">=8.1", "ext-intl": "*"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PlatformRequirements(false) = %v, want %v", got, want)
+	}
+	if got := c.PlatformRequirements(true); len(got) != 3 || got["ext-xdebug"] != "^3.0" {
+		t.Errorf("PlatformRequirements(true) = %v", got)
+	}
+	if got, want := c.PackageRequirements(false), map[string]string{"monolog/monolog": "^3.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PackageRequirements(false) = %v, want %v", got, want)
+	}
+	if got := c.PackageRequirements(true); len(got) != 2 || got["phpunit/phpunit"] != "^10.0" {
+		t.Errorf("PackageRequirements(true) = %v", got)
+	}
+}
+
+func TestComposerJSON_PlatformOverrides(t *testing.T) {
+	c, err := ParseString(`{
+    "name": "acme/app",
+    "config": {
+        "sort-packages": true
+    }
+}`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	if err := c.SetPlatformOverride("php", "8.1.2"); err != nil {
+		t.Fatalf("SetPlatformOverride(php) returned unexpected error: %v", err)
+	}
+	if err := c.SetPlatformOverride("ext-intl", "not a version"); err == nil {
+		t.Error("SetPlatformOverride() with invalid version expected error, got nil")
+	}
+	if err := c.SetPlatformOverride("acme/lib", "1.0.0"); err == nil {
+		t.Error("SetPlatformOverride() with non-platform package expected error, got nil")
+	}
+	if got := c.PlatformOverrides(); !reflect.DeepEqual(got, map[string]string{"php": "8.1.2"}) {
+		t.Errorf("PlatformOverrides() = %v", got)
+	}
+
+	out, err := c.ToJSON(true)
+	if err != nil {
+		t.Fatalf("ToJSON() returned unexpected error: %v", err)
+	}
+	if !strings.Contains(out, `"platform": {`) || !strings.Contains(out, `"php": "8.1.2"`) {
+		t.Errorf("ToJSON() does not contain config.platform.php:\n%s", out)
+	}
+
+	if !c.RemovePlatformOverride("php") {
+		t.Error("RemovePlatformOverride(php) = false, want true")
+	}
+	if c.RemovePlatformOverride("php") {
+		t.Error("RemovePlatformOverride(php) second call = true, want false")
+	}
+	out, _ = c.ToJSON(true)
+	if strings.Contains(out, `"php"`) {
+		t.Errorf("ToJSON() still contains php override:\n%s", out)
+	}
+}
+
+func TestComposerJSON_CheckPlatform(t *testing.T) {
+	c, err := ParseString(`{
+		"name": "acme/app",
+		"require": {"php": "^8.2", "ext-intl": "*"},
+		"require-dev": {"ext-xdebug": "^3.0"},
+		"config": {"platform": {"php": "8.2.0"}}
+	}`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+	l := &lock.Lock{Packages: []lock.LockedPackage{
+		{Name: "acme/lib", Version: "1.0.0", Require: map[string]string{"ext-redis": "^6.0"}},
+	}}
+	rt := &platform.Runtime{PHP: "8.1.2", Extensions: map[string]string{"intl": ""}}
+
+	report := c.CheckPlatform(rt, l, false, platform.Options{})
+	problems := report.Problems()
+	if len(problems) != 1 || problems[0].Package != "ext-redis" || problems[0].Status != platform.StatusMissing {
+		t.Errorf("CheckPlatform() problems = %+v, want only ext-redis missing", problems)
+	}
+
+	report = c.CheckPlatform(rt, l, true, platform.Options{Overrides: map[string]string{}, Ignore: []string{"ext-*"}})
+	problems = report.Problems()
+	if len(problems) != 1 || problems[0].Package != "php" {
+		t.Errorf("CheckPlatform() without config.platform problems = %+v, want only php", problems)
+	}
+}
@@ -0,0 +1,80 @@
+package composer
+
+import (
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/resolver"
+)
+
+// Resolve 根据composer.json中的依赖从本地仓库元数据中选择一组可以同时安装的包，相当于离线执行`composer update`的依赖解析
+//
+// 参数:
+//   - pool: 可供选择的包版本，通常由resolver.LoadRepository从镜像目录或packages.json读取
+//   - opts: 解析选项；Platform不为nil时，config.platform中的版本覆盖其中的同名平台包
+//
+// 返回:
+//   - *resolver.Solution: 选中的包，拆分为packages和packages-dev
+//   - error: 无法解析时返回*resolver.Error
+//
+// 示例:
+//
+//	project, _ := composer.ParseDir(".")
+//	pool, _ := resolver.LoadRepository("mirror/")
+//
+//	solution, err := project.Resolve(pool, resolver.Options{Platform: rt.Packages()})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, pkg := range solution.Packages {
+//		fmt.Println(pkg.Name, pkg.Version)
+//	}
+func (c *ComposerJSON) Resolve(pool *resolver.Pool, opts resolver.Options) (*resolver.Solution, error) {
+	if opts.Platform != nil && len(c.Config.Platform) > 0 {
+		merged := make(map[string]string, len(opts.Platform)+len(c.Config.Platform))
+		for name, v := range opts.Platform {
+			merged[name] = v
+		}
+		for name, v := range c.Config.Platform {
+			merged[name] = v
+		}
+		opts.Platform = merged
+	}
+	return resolver.Resolve(resolver.Root{
+		Package:          c.rootPackage(),
+		MinimumStability: c.MinimumStability,
+		PreferStable:     c.PreferStable,
+	}, pool, opts)
+}
+
+// ResolveLock 解析依赖并生成composer.lock，相当于离线执行`composer update`并只写入锁文件
+//
+// 参数:
+//   - pool: 可供选择的包版本
+//   - opts: 解析选项，规则见Resolve
+//
+// 返回:
+//   - *lock.Lock: 锁文件，content-hash等根包字段由当前的composer.json生成
+//   - error: 无法解析、序列化composer.json失败，或使用opts.NoDev而require-dev不为空（resolver.ErrNoDevLock）时返回错误
+//
+// 示例:
+//
+//	project, _ := composer.ParseDir(".")
+//	pool, _ := resolver.LoadRepository("mirror/")
+//
+//	l, err := project.ResolveLock(pool, resolver.Options{Platform: rt.Packages()})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if err := l.Save("composer.lock"); err != nil {
+//		log.Fatal(err)
+//	}
+func (c *ComposerJSON) ResolveLock(pool *resolver.Pool, opts resolver.Options) (*lock.Lock, error) {
+	solution, err := c.Resolve(pool, opts)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.ToJSON(true)
+	if err != nil {
+		return nil, err
+	}
+	return solution.Lock([]byte(data))
+}
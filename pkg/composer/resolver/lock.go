@@ -0,0 +1,58 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+)
+
+// Lock 把解析结果写成composer.lock
+//
+// 根包相关的字段（content-hash、minimum-stability、stability-flags、prefer-stable、platform、
+// platform-dev和platform-overrides）由lock.New根据composer.json生成；packages、packages-dev、
+// aliases和prefer-lowest来自解析结果。包按名称排列，与Composer写入的顺序一致，
+// 因此相同的composer.json和仓库元数据总是得到相同的锁文件。
+//
+// 使用Options.NoDev解析的结果不包含packages-dev，与Composer的`update --no-dev`一样只能在
+// require-dev为空时写出锁文件，否则锁文件与require-dev不一致。
+//
+// 参数:
+//   - composerJSON: 解析时使用的composer.json的原始内容
+//
+// 返回:
+//   - *lock.Lock: 锁文件，可以用ToJSON或Save输出
+//   - error: 如果composer.json无效，返回错误；使用Options.NoDev解析且require-dev不为空时返回ErrNoDevLock
+//
+// 示例:
+//
+//	data, _ := os.ReadFile("composer.json")
+//	l, err := solution.Lock(data)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if err := l.Save("composer.lock"); err != nil {
+//		log.Fatal(err)
+//	}
+func (s *Solution) Lock(composerJSON []byte) (*lock.Lock, error) {
+	l, err := lock.New(composerJSON)
+	if err != nil {
+		return nil, err
+	}
+	if s.NoDev {
+		var root struct {
+			RequireDev map[string]interface{} `json:"require-dev"`
+		}
+		if err := json.Unmarshal(composerJSON, &root); err != nil {
+			return nil, err
+		}
+		if len(root.RequireDev) > 0 {
+			return nil, fmt.Errorf("%w: require-dev has %d packages", ErrNoDevLock, len(root.RequireDev))
+		}
+	}
+	l.Packages = append(l.Packages, s.Packages...)
+	l.PackagesDev = append(l.PackagesDev, s.PackagesDev...)
+	l.Aliases = append(l.Aliases, s.Aliases...)
+	l.PreferLowest = s.PreferLowest
+	return l, nil
+}
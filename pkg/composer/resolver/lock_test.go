@@ -0,0 +1,96 @@
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+)
+
+func TestSolution_Lock(t *testing.T) {
+	data := []byte(`{
+		"name": "acme/app",
+		"require": {"php": "^8.1", "acme/a": "dev-main as 1.0.0", "acme/b": "^1.0"},
+		"require-dev": {"acme/test": "^2.0"},
+		"minimum-stability": "stable",
+		"config": {"platform": {"php": "8.1.0"}}
+	}`)
+	var root struct {
+		Require    map[string]string `json:"require"`
+		RequireDev map[string]string `json:"require-dev"`
+	}
+	if err := json.Unmarshal(data, &root); err != nil {
+		t.Fatal(err)
+	}
+
+	pool := mustPool(t,
+		pkg("acme/a", "dev-main"),
+		pkg("acme/b", "1.0.0", map[string]string{"acme/a": "^1.0"}),
+		pkg("acme/b", "1.1.0", map[string]string{"acme/a": "^1.0"}),
+		pkg("acme/test", "2.0.0", map[string]string{"acme/b": "^1.0"}),
+	)
+	sol := mustResolve(t, Root{Package: lock.LockedPackage{Name: "acme/app", Require: root.Require, RequireDev: root.RequireDev}}, pool,
+		Options{Platform: map[string]string{"php": "8.1.0"}, PreferLowest: true})
+
+	l, err := sol.Lock(data)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if len(l.Packages) != 2 || l.Packages[0].Name != "acme/a" || l.Packages[1].Version != "1.0.0" {
+		t.Errorf("Packages = %+v", l.Packages)
+	}
+	if len(l.PackagesDev) != 1 || l.PackagesDev[0].Name != "acme/test" {
+		t.Errorf("PackagesDev = %+v", l.PackagesDev)
+	}
+	want := lock.Alias{Package: "acme/a", Version: "dev-main", Alias: "1.0.0", AliasNormalized: "1.0.0.0"}
+	if len(l.Aliases) != 1 || l.Aliases[0] != want {
+		t.Errorf("Aliases = %+v, want %+v", l.Aliases, want)
+	}
+	if !l.PreferLowest || l.StabilityFlags.Stability("acme/a") != "dev" || l.Platform["php"] != "^8.1" {
+		t.Errorf("Lock() root fields = %+v", l)
+	}
+
+	// 写出的锁文件是最新的，且满足composer.json中的依赖
+	out, err := l.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+	parsed, err := lock.ParseBytes(out)
+	if err != nil {
+		t.Fatalf("ParseBytes() error = %v", err)
+	}
+	if freshness, err := lock.CheckFreshness(data, parsed); err != nil || !freshness.Fresh {
+		t.Errorf("CheckFreshness() = %+v, %v, want fresh", freshness, err)
+	}
+	if findings := lock.Verify(parsed, root.Require, root.RequireDev, nil); len(findings) != 0 {
+		t.Errorf("Verify() = %v, want no findings", findings)
+	}
+
+	if _, err := sol.Lock([]byte(`[]`)); err == nil {
+		t.Error("Lock() with invalid composer.json expected error, got nil")
+	}
+}
+
+func TestSolution_Lock_NoDev(t *testing.T) {
+	pool := mustPool(t, pkg("acme/b", "1.0.0"), pkg("acme/test", "2.0.0"))
+	root := Root{Package: lock.LockedPackage{Name: "acme/app", Require: map[string]string{"acme/b": "^1.0"},
+		RequireDev: map[string]string{"acme/test": "^2.0"}}}
+	sol := mustResolve(t, root, pool, Options{NoDev: true})
+	if !sol.NoDev {
+		t.Error("Solution.NoDev = false, want true")
+	}
+
+	data := []byte(`{"require": {"acme/b": "^1.0"}, "require-dev": {"acme/test": "^2.0"}}`)
+	if _, err := sol.Lock(data); !errors.Is(err, ErrNoDevLock) {
+		t.Errorf("Lock() error = %v, want ErrNoDevLock", err)
+	}
+
+	l, err := sol.Lock([]byte(`{"require": {"acme/b": "^1.0"}, "require-dev": {}}`))
+	if err != nil {
+		t.Fatalf("Lock() without require-dev error = %v", err)
+	}
+	if len(l.Packages) != 1 || len(l.PackagesDev) != 0 {
+		t.Errorf("Lock() = %+v", l)
+	}
+}
@@ -0,0 +1,443 @@
+// Package resolver 提供不依赖PHP和网络的离线依赖解析
+//
+// 本包根据根包的依赖和本地的Composer仓库元数据预测`composer update`会选择的包，包括：
+// - 从packages.json、p2目录或包含composer.json文件的目录加载包元数据
+// - 按版本约束、conflict、replace和provide、稳定性和prefer-stable选择版本
+// - 根包require中的内联别名（如"dev-main as 1.0.0"）、extra.branch-alias和默认分支别名
+// - 按是否只被require-dev需要把结果拆分为packages和packages-dev
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/version"
+)
+
+// 错误定义
+var (
+	// ErrInvalidMetadata 表示包元数据无效
+	ErrInvalidMetadata = errors.New("invalid package metadata")
+)
+
+// entry 是池中的一个包版本
+type entry struct {
+	pkg *lock.LockedPackage
+
+	// normalized 规范化后的版本
+	normalized string
+
+	// aliases extra.branch-alias中的分支别名和默认分支别名，已规范化
+	aliases []string
+}
+
+// Pool 是可供解析的包版本集合
+//
+// 同一个包的同一个版本只保留第一次添加的元数据，与Composer中排在前面的仓库优先一致。
+type Pool struct {
+	entries []*entry
+	byName  map[string][]*entry
+	// providers 虚拟包名到replace或provide它的包版本
+	providers map[string][]*entry
+}
+
+// NewPool 创建包含指定包的池
+//
+// 返回:
+//   - *Pool: 包版本集合
+//   - error: 如果有包缺少名称或版本无效，返回ErrInvalidMetadata
+func NewPool(packages ...lock.LockedPackage) (*Pool, error) {
+	p := &Pool{byName: make(map[string][]*entry), providers: make(map[string][]*entry)}
+	for _, pkg := range packages {
+		if err := p.Add(pkg); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Add 向池中添加一个包版本，已存在的版本会被忽略
+//
+// 返回:
+//   - error: 如果包缺少名称或版本无效，返回ErrInvalidMetadata
+func (p *Pool) Add(pkg lock.LockedPackage) error {
+	if pkg.Name == "" || pkg.Version == "" {
+		return fmt.Errorf("%w: package %q has no name or version", ErrInvalidMetadata, pkg.Name+" "+pkg.Version)
+	}
+	normalized, err := version.Normalize(pkg.Version)
+	if err != nil {
+		return fmt.Errorf("%w: %s %s: %v", ErrInvalidMetadata, pkg.Name, pkg.Version, err)
+	}
+
+	key := strings.ToLower(pkg.Name)
+	for _, e := range p.byName[key] {
+		if e.normalized == normalized {
+			return nil
+		}
+	}
+
+	e := &entry{pkg: &pkg, normalized: normalized}
+	if branchAliases, ok := pkg.Extra["branch-alias"].(map[string]interface{}); ok {
+		if alias, ok := branchAliases[pkg.Version].(string); ok {
+			if n, err := version.Normalize(alias); err == nil {
+				e.aliases = append(e.aliases, n)
+			}
+		}
+	}
+	if pkg.DefaultBranch && len(e.aliases) == 0 {
+		e.aliases = append(e.aliases, version.DefaultBranchAlias)
+	}
+
+	p.entries = append(p.entries, e)
+	p.byName[key] = append(p.byName[key], e)
+	for _, links := range []map[string]string{pkg.Replace, pkg.Provide} {
+		for target := range links {
+			target = strings.ToLower(target)
+			if !containsEntry(p.providers[target], e) {
+				p.providers[target] = append(p.providers[target], e)
+			}
+		}
+	}
+	return nil
+}
+
+// Len 返回池中包版本的数量
+func (p *Pool) Len() int {
+	return len(p.entries)
+}
+
+// Names 返回池中所有包名，按字母顺序排列
+func (p *Pool) Names() []string {
+	names := make([]string, 0, len(p.byName))
+	for _, entries := range p.byName {
+		names = append(names, entries[0].pkg.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Versions 返回包（不区分大小写）的所有版本，按版本升序排列
+func (p *Pool) Versions(name string) []lock.LockedPackage {
+	entries := append([]*entry(nil), p.byName[strings.ToLower(name)]...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return compareEntries(entries[i], entries[j]) < 0
+	})
+
+	result := make([]lock.LockedPackage, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, *e.pkg)
+	}
+	return result
+}
+
+// LoadRepository 从本地的Composer仓库元数据加载池
+//
+// 参数:
+//   - path: 元数据的位置，可以是：
+//   - packages.json文件，支持按版本为键的对象和版本列表两种形式，以及includes中引用的文件
+//   - 包含packages.json或p2子目录的目录，p2中的文件按Composer 2的格式读取，支持minified元数据
+//   - 其他目录，递归读取其中所有composer.json文件，每个文件必须包含version
+//
+// 返回:
+//   - *Pool: 包版本集合
+//   - error: 如果读取失败或元数据无效，返回错误
+//
+// 示例:
+//
+//	// 镜像的元数据快照，如Satis的输出目录
+//	pool, err := resolver.LoadRepository("mirror/")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(pool.Len(), "package versions")
+func LoadRepository(path string) (*Pool, error) {
+	pool, err := NewPool()
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.LoadRepository(path); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// LoadRepository 从本地的Composer仓库元数据加载包版本，规则与包级函数LoadRepository相同
+//
+// 多次调用时先加载的仓库优先。
+func (p *Pool) LoadRepository(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("error reading repository %s: %v", path, err)
+	}
+	if !info.IsDir() {
+		return p.loadPackagesJSON(path)
+	}
+
+	found := false
+	if _, err := os.Stat(filepath.Join(path, "packages.json")); err == nil {
+		if err := p.loadPackagesJSON(filepath.Join(path, "packages.json")); err != nil {
+			return err
+		}
+		found = true
+	}
+	if info, err := os.Stat(filepath.Join(path, "p2")); err == nil && info.IsDir() {
+		if err := p.loadP2Dir(filepath.Join(path, "p2")); err != nil {
+			return err
+		}
+		found = true
+	}
+	if found {
+		return nil
+	}
+	return p.loadComposerDir(path)
+}
+
+// loadPackagesJSON 读取packages.json及其includes
+func (p *Pool) loadPackagesJSON(file string) error {
+	var doc struct {
+		Packages json.RawMessage            `json:"packages"`
+		Includes map[string]json.RawMessage `json:"includes"`
+	}
+	if err := readJSON(file, &doc); err != nil {
+		return err
+	}
+
+	if err := p.addPackages(file, doc.Packages, false); err != nil {
+		return err
+	}
+
+	includes := make([]string, 0, len(doc.Includes))
+	for include := range doc.Includes {
+		includes = append(includes, include)
+	}
+	sort.Strings(includes)
+	for _, include := range includes {
+		if err := p.loadPackagesJSON(filepath.Join(filepath.Dir(file), filepath.FromSlash(include))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadP2Dir 读取p2目录中的所有元数据文件，稳定版本的文件在"~dev"文件之前读取
+func (p *Pool) loadP2Dir(dir string) error {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".json") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error reading repository %s: %v", dir, err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		var doc struct {
+			Packages json.RawMessage `json:"packages"`
+			Minified string          `json:"minified"`
+		}
+		if err := readJSON(file, &doc); err != nil {
+			return err
+		}
+		if err := p.addPackages(file, doc.Packages, doc.Minified == "composer/2.0"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadComposerDir 递归读取目录中的所有composer.json文件，跳过vendor目录
+func (p *Pool) loadComposerDir(dir string) error {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == "vendor" && path != dir {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && d.Name() == "composer.json" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error reading repository %s: %v", dir, err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		var raw map[string]interface{}
+		if err := readJSON(file, &raw); err != nil {
+			return err
+		}
+		pkg, err := decodePackage(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if err := p.Add(pkg); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// addPackages 添加packages字段中的包，支持以下形式：
+//   - {"vendor/name": {"1.0.0": {...}}}
+//   - {"vendor/name": [{...}, ...]}，minified为true时按Composer 2的规则展开
+//   - [{...}, ...]
+func (p *Pool) addPackages(file string, data json.RawMessage, minified bool) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+
+	var versions []map[string]interface{}
+	var byName map[string]json.RawMessage
+	if err := json.Unmarshal(data, &byName); err != nil {
+		// 不是对象时按包的列表读取
+		if err := json.Unmarshal(data, &versions); err != nil {
+			return fmt.Errorf("%w: %s: packages must be an object or a list", ErrInvalidMetadata, file)
+		}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var list []map[string]interface{}
+		if err := json.Unmarshal(byName[name], &list); err == nil {
+			if minified {
+				list = expandMinified(list)
+			}
+			versions = append(versions, list...)
+			continue
+		}
+
+		var keyed map[string]map[string]interface{}
+		if err := json.Unmarshal(byName[name], &keyed); err != nil {
+			return fmt.Errorf("%w: %s: versions of %s must be an object or a list", ErrInvalidMetadata, file, name)
+		}
+		keys := make([]string, 0, len(keyed))
+		for v := range keyed {
+			keys = append(keys, v)
+		}
+		sort.Strings(keys)
+		for _, v := range keys {
+			versions = append(versions, keyed[v])
+		}
+	}
+
+	for _, raw := range versions {
+		pkg, err := decodePackage(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if err := p.Add(pkg); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// expandMinified 展开Composer 2的minified元数据：每个版本只记录与前一个版本不同的字段，"__unset"表示删除
+func expandMinified(versions []map[string]interface{}) []map[string]interface{} {
+	expanded := make([]map[string]interface{}, 0, len(versions))
+	var current map[string]interface{}
+	for _, v := range versions {
+		next := make(map[string]interface{}, len(current)+len(v))
+		for key, value := range current {
+			next[key] = value
+		}
+		for key, value := range v {
+			if value == "__unset" {
+				delete(next, key)
+			} else {
+				next[key] = value
+			}
+		}
+		expanded = append(expanded, next)
+		current = next
+	}
+	return expanded
+}
+
+// objectFields 是LockedPackage中必须为对象的字段，元数据中的PHP空数组"[]"等非对象值会被忽略
+var objectFields = []string{"require", "require-dev", "conflict", "provide", "replace", "suggest", "extra", "autoload", "autoload-dev", "support", "source", "dist"}
+
+// decodePackage 把一个版本的元数据转换为LockedPackage，兼容字符串形式的license和bin
+func decodePackage(raw map[string]interface{}) (lock.LockedPackage, error) {
+	for _, key := range objectFields {
+		if _, ok := raw[key].(map[string]interface{}); !ok {
+			delete(raw, key)
+		}
+	}
+	for _, key := range []string{"license", "bin"} {
+		if s, ok := raw[key].(string); ok {
+			raw[key] = []string{s}
+		}
+	}
+
+	var pkg lock.LockedPackage
+	data, err := json.Marshal(raw)
+	if err == nil {
+		err = json.Unmarshal(data, &pkg)
+	}
+	if err != nil {
+		return pkg, fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+	}
+	return pkg, nil
+}
+
+// readJSON 读取并解析JSON文件
+func readJSON(file string, v interface{}) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("error reading repository %s: %v", file, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidMetadata, file, err)
+	}
+	return nil
+}
+
+// compareEntries 按Composer的规则比较两个包版本
+//
+// 有分支别名的版本按别名比较，默认分支排在所有发布版本之后，其他分支排在所有数字版本之前。
+func compareEntries(a, b *entry) int {
+	if c, err := version.Order(a.sortVersion(), b.sortVersion()); err == nil {
+		return c
+	}
+	return strings.Compare(a.normalized, b.normalized)
+}
+
+// sortVersion 返回排序时使用的规范化版本：有别名时使用别名
+func (e *entry) sortVersion() string {
+	if len(e.aliases) > 0 {
+		return e.aliases[0]
+	}
+	return e.normalized
+}
+
+// containsEntry 判断列表中是否包含指定的包版本
+func containsEntry(entries []*entry, e *entry) bool {
+	for _, x := range entries {
+		if x == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package resolver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFiles 在临时目录中写入文件，返回目录
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// versionsOf 返回池中包的所有版本
+func versionsOf(p *Pool, name string) []string {
+	var versions []string
+	for _, pkg := range p.Versions(name) {
+		versions = append(versions, pkg.Version)
+	}
+	return versions
+}
+
+func TestLoadRepository_PackagesJSON(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"packages.json": `{
+			"packages": {
+				"acme/a": {
+					"1.0.0": {"name": "acme/a", "version": "1.0.0", "license": "MIT", "require": {"acme/b": "^1.0"}},
+					"1.1.0": {"name": "acme/a", "version": "1.1.0", "require-dev": [], "extra": []}
+				},
+				"acme/b": [{"name": "acme/b", "version": "v1.0.0"}]
+			},
+			"includes": {"include/all$abc.json": {"sha1": "abc"}}
+		}`,
+		"include/all$abc.json": `{"packages": {"acme/c": {"2.0.0": {"name": "acme/c", "version": "2.0.0"}}}}`,
+	})
+
+	pool, err := LoadRepository(filepath.Join(dir, "packages.json"))
+	if err != nil {
+		t.Fatalf("LoadRepository() error = %v", err)
+	}
+	if pool.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", pool.Len())
+	}
+	if got, want := pool.Names(), []string{"acme/a", "acme/b", "acme/c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	a := pool.Versions("ACME/A")
+	if len(a) != 2 || a[0].Version != "1.0.0" || !reflect.DeepEqual(a[0].License, []string{"MIT"}) || a[0].Require["acme/b"] != "^1.0" {
+		t.Errorf("Versions(acme/a) = %+v", a)
+	}
+}
+
+func TestLoadRepository_P2(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"packages.json": `{"packages": [], "metadata-url": "/p2/%package%.json"}`,
+		"p2/acme/a.json": `{
+			"minified": "composer/2.0",
+			"packages": {"acme/a": [
+				{"name": "acme/a", "version": "2.0.0", "require": {"php": ">=8.1"}, "description": "A"},
+				{"version": "1.0.0", "require": "__unset"}
+			]}
+		}`,
+		"p2/acme/a~dev.json": `{
+			"minified": "composer/2.0",
+			"packages": {"acme/a": [
+				{"name": "acme/a", "version": "dev-main", "default-branch": true}
+			]}
+		}`,
+	})
+
+	pool, err := LoadRepository(dir)
+	if err != nil {
+		t.Fatalf("LoadRepository() error = %v", err)
+	}
+	if got, want := versionsOf(pool, "acme/a"), []string{"1.0.0", "2.0.0", "dev-main"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Versions(acme/a) = %v, want %v", got, want)
+	}
+	v1 := pool.Versions("acme/a")[0]
+	if v1.Require != nil || v1.Description != "A" {
+		t.Errorf("expanded 1.0.0 = %+v, want inherited description and no require", v1)
+	}
+}
+
+func TestLoadRepository_ComposerDir(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a/composer.json":            `{"name": "acme/a", "version": "1.0.0", "license": "MIT"}`,
+		"b/composer.json":            `{"name": "acme/b", "version": "2.0.0", "require": {"acme/a": "^1.0"}}`,
+		"b/vendor/x/y/composer.json": `{"name": "x/y"}`,
+		"a/composer.json.dist":       `{}`,
+	})
+
+	pool, err := LoadRepository(dir)
+	if err != nil {
+		t.Fatalf("LoadRepository() error = %v", err)
+	}
+	if got, want := pool.Names(), []string{"acme/a", "acme/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+
+	dir = writeFiles(t, map[string]string{"a/composer.json": `{"name": "acme/a"}`})
+	if _, err := LoadRepository(dir); !errors.Is(err, ErrInvalidMetadata) {
+		t.Errorf("LoadRepository() without version error = %v, want ErrInvalidMetadata", err)
+	}
+}
+
+func TestPool_FirstRepositoryWins(t *testing.T) {
+	first := writeFiles(t, map[string]string{"packages.json": `{"packages": {"acme/a": {"1.0.0": {"name": "acme/a", "version": "1.0.0", "description": "first"}}}}`})
+	second := writeFiles(t, map[string]string{"packages.json": `{"packages": {"acme/a": {"v1.0": {"name": "acme/a", "version": "v1.0", "description": "second"}}}}`})
+
+	pool, err := LoadRepository(first)
+	if err != nil {
+		t.Fatalf("LoadRepository() error = %v", err)
+	}
+	if err := pool.LoadRepository(second); err != nil {
+		t.Fatalf("LoadRepository() error = %v", err)
+	}
+	if v := pool.Versions("acme/a"); len(v) != 1 || v[0].Description != "first" {
+		t.Errorf("Versions(acme/a) = %+v, want only the first repository's metadata", v)
+	}
+}
+
+func TestLoadRepository_Errors(t *testing.T) {
+	if _, err := LoadRepository(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("LoadRepository() with missing path expected error, got nil")
+	}
+
+	dir := writeFiles(t, map[string]string{"packages.json": `{"packages": "nope"}`})
+	if _, err := LoadRepository(dir); !errors.Is(err, ErrInvalidMetadata) {
+		t.Errorf("LoadRepository() error = %v, want ErrInvalidMetadata", err)
+	}
+
+	if _, err := NewPool(pkg("acme/a", "not a version")); !errors.Is(err, ErrInvalidMetadata) {
+		t.Errorf("NewPool() error = %v, want ErrInvalidMetadata", err)
+	}
+}
@@ -0,0 +1,652 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/constraint"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/dependency"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/stability"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/version"
+)
+
+// DefaultMaxSteps 是Options.MaxSteps为0时使用的最大尝试次数
+const DefaultMaxSteps = 100000
+
+// ErrTooComplex 表示解析超过了Options.MaxSteps的限制
+var ErrTooComplex = errors.New("dependency resolution exceeded the step limit")
+
+// ErrNoDevLock 表示使用Options.NoDev解析的结果不能写成require-dev不为空的锁文件
+var ErrNoDevLock = errors.New("cannot write a lock file from a resolution without require-dev")
+
+// Error 表示依赖无法解析为一组可安装的包
+type Error struct {
+	// Problem 解析走得最远时遇到的问题，如"Root composer.json requires acme/lib ^2.0, it could not be found in the pool"
+	Problem string
+}
+
+// Error 返回错误描述
+func (e *Error) Error() string {
+	return "your requirements could not be resolved to an installable set of packages: " + e.Problem
+}
+
+// Root 是需要解析的根包
+type Root struct {
+	// Package 根包，使用其Name、Version、Require、RequireDev、Conflict、Replace和Provide
+	Package lock.LockedPackage
+
+	// MinimumStability 根包的minimum-stability，为空时使用"stable"
+	MinimumStability string
+
+	// PreferStable 根包的prefer-stable
+	PreferStable bool
+}
+
+// Options 控制依赖解析
+type Options struct {
+	// Platform 平台包的版本，如{"php": "8.2.12", "ext-intl": "8.2.12"}，可以由platform.Runtime的Packages和config.platform合并得到
+	//
+	// 为nil时忽略所有平台包依赖，相当于--ignore-platform-reqs；否则没有列出的平台包视为缺失。
+	Platform map[string]string
+
+	// NoDev 不解析根包的require-dev，相当于`composer update --no-dev`
+	NoDev bool
+
+	// PreferLowest 优先选择最低的版本，相当于`composer update --prefer-lowest`
+	PreferLowest bool
+
+	// MaxSteps 最大尝试次数，为0时使用DefaultMaxSteps
+	MaxSteps int
+}
+
+// Solution 是解析的结果
+type Solution struct {
+	// Packages 生产环境需要的包，按包名排序
+	Packages []lock.LockedPackage
+
+	// PackagesDev 只被根包require-dev需要的包，按包名排序
+	PackagesDev []lock.LockedPackage
+
+	// Aliases 根包require中的内联别名，如"dev-main as 1.0.0"，包名为小写
+	Aliases []lock.Alias
+
+	// PreferLowest 解析时是否使用了Options.PreferLowest
+	PreferLowest bool
+
+	// NoDev 解析时是否使用了Options.NoDev，此时PackagesDev为空
+	NoDev bool
+}
+
+// Find 按包名（不区分大小写）查找选中的包
+//
+// 返回:
+//   - *lock.LockedPackage: 选中的包，不存在时为nil
+//   - bool: 包是否只被require-dev需要
+func (s *Solution) Find(name string) (*lock.LockedPackage, bool) {
+	for i := range s.Packages {
+		if strings.EqualFold(s.Packages[i].Name, name) {
+			return &s.Packages[i], false
+		}
+	}
+	for i := range s.PackagesDev {
+		if strings.EqualFold(s.PackagesDev[i].Name, name) {
+			return &s.PackagesDev[i], true
+		}
+	}
+	return nil, false
+}
+
+// job 是一个待满足的依赖
+type job struct {
+	name       string
+	pretty     string
+	constraint constraint.Constraint
+	source     string
+}
+
+// solver 保存一次解析的状态
+type solver struct {
+	pool      *Pool
+	root      Root
+	opts      Options
+	stability *stability.Resolver
+
+	// rootAliases 小写包名到规范化版本到规范化别名
+	rootAliases map[string]map[string]string
+	aliases     []lock.Alias
+
+	installed map[string]*entry
+	order     []*entry
+
+	steps      int
+	tooComplex bool
+	failDepth  int
+	failure    string
+}
+
+// Resolve 根据根包的依赖从池中选择一组可以同时安装的包
+//
+// 参数:
+//   - root: 根包及其稳定性设置
+//   - pool: 可供选择的包版本
+//   - opts: 平台包、--no-dev和--prefer-lowest等选项
+//
+// 返回:
+//   - *Solution: 选中的包，拆分为packages和packages-dev
+//   - error: 根包的约束无效时返回错误；无法解析时返回*Error；超过MaxSteps时返回ErrTooComplex
+//
+// 选择规则:
+//   - 按先根包、后依赖的顺序逐个满足依赖，无法满足时回溯
+//   - 包名比较不区分大小写，每个包名只选择一个版本
+//   - 包自身的版本、根包的内联别名、extra.branch-alias和默认分支别名都可以匹配约束；replace和provide的约束与依赖相交时也可以满足依赖
+//   - 优先选择与依赖同名的包，其次是与依赖同一供应商的替代包；同名的包按prefer-stable和版本从高到低（PreferLowest时从低到高）排列
+//   - 两个包之间或与根包之间的conflict、replace同一个包的两个包，以及被replace的包与替代它的包不能同时选择
+//   - 不满足minimum-stability和稳定性标志的版本不参与选择
+//
+// 示例:
+//
+//	pool, _ := resolver.LoadRepository("mirror/")
+//	solution, err := resolver.Resolve(resolver.Root{
+//		Package: lock.LockedPackage{
+//			Name:    "acme/app",
+//			Require: map[string]string{"php": "^8.1", "monolog/monolog": "^3.0"},
+//		},
+//		PreferStable: true,
+//	}, pool, resolver.Options{Platform: map[string]string{"php": "8.2.12"}})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, pkg := range solution.Packages {
+//		fmt.Println(pkg.Name, pkg.Version)
+//	}
+func Resolve(root Root, pool *Pool, opts Options) (*Solution, error) {
+	requireDev := root.Package.RequireDev
+	if opts.NoDev {
+		requireDev = nil
+	}
+	rules, err := stability.NewResolver(root.MinimumStability, root.PreferStable, root.Package.Require, requireDev)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxSteps == 0 {
+		opts.MaxSteps = DefaultMaxSteps
+	}
+
+	s := &solver{
+		pool:        pool,
+		root:        root,
+		opts:        opts,
+		stability:   rules,
+		rootAliases: make(map[string]map[string]string),
+		installed:   make(map[string]*entry),
+		failDepth:   -1,
+	}
+
+	var jobs []job
+	for _, require := range []map[string]string{root.Package.Require, requireDev} {
+		for _, name := range sortedKeys(require) {
+			j, err := newJob(name, require[name], "Root composer.json")
+			if err != nil {
+				return nil, err
+			}
+			jobs = append(jobs, j)
+			s.addRootAlias(name, require[name])
+		}
+	}
+
+	if !s.solve(jobs) {
+		if s.tooComplex {
+			return nil, fmt.Errorf("%w (%d)", ErrTooComplex, opts.MaxSteps)
+		}
+		return nil, &Error{Problem: s.failure}
+	}
+	return s.solution(), nil
+}
+
+// rootAliasRegex 匹配根包require中的内联别名，与Composer的RootPackageLoader一致
+var rootAliasRegex = regexp.MustCompile(`^([^,\s#]+)(?:#[^ ]+)? +as +([^,\s]+)$`)
+
+// addRootAlias 记录根包require中的内联别名
+func (s *solver) addRootAlias(name, c string) {
+	m := rootAliasRegex.FindStringSubmatch(strings.TrimSpace(c))
+	if m == nil {
+		return
+	}
+	v, err := version.Normalize(m[1])
+	if err != nil {
+		return
+	}
+	alias, err := version.Normalize(m[2])
+	if err != nil {
+		return
+	}
+
+	key := strings.ToLower(name)
+	if s.rootAliases[key] == nil {
+		s.rootAliases[key] = make(map[string]string)
+	}
+	s.rootAliases[key][v] = alias
+	s.aliases = append(s.aliases, lock.Alias{Package: key, Version: v, Alias: m[2], AliasNormalized: alias})
+}
+
+// newJob 创建一个待满足的依赖
+func newJob(name, c, source string) (job, error) {
+	parsed, err := constraint.Parse(c)
+	if err != nil {
+		return job{}, fmt.Errorf("%s requires %s: %w", source, name, err)
+	}
+	return job{name: name, pretty: c, constraint: parsed, source: source}, nil
+}
+
+// solve 按顺序满足pending中的依赖，成功时返回true；失败时撤销本次调用中的所有选择
+func (s *solver) solve(pending []job) bool {
+	s.steps++
+	if s.steps > s.opts.MaxSteps {
+		s.tooComplex = true
+		return false
+	}
+
+	// 跳过已经满足的依赖
+	for len(pending) > 0 {
+		satisfied, problem := s.satisfied(pending[0])
+		if problem != "" {
+			s.fail(problem)
+			return false
+		}
+		if !satisfied {
+			break
+		}
+		pending = pending[1:]
+	}
+	if len(pending) == 0 {
+		return true
+	}
+
+	j, rest := pending[0], pending[1:]
+	candidates, problem := s.candidates(j)
+	if len(candidates) == 0 {
+		s.fail(problem)
+		return false
+	}
+
+	for _, e := range candidates {
+		if problem := s.conflicts(e); problem != "" {
+			s.fail(fmt.Sprintf("%s requires %s %s -> %s", j.source, j.name, j.pretty, problem))
+			continue
+		}
+
+		requires, err := s.requires(e)
+		if err != nil {
+			s.fail(err.Error())
+			continue
+		}
+
+		s.installed[strings.ToLower(e.pkg.Name)] = e
+		s.order = append(s.order, e)
+
+		// 提前排除依赖无法满足的版本，避免在更深的位置才回溯
+		if problem := s.lookahead(requires); problem != "" {
+			s.fail(problem)
+			delete(s.installed, strings.ToLower(e.pkg.Name))
+			s.order = s.order[:len(s.order)-1]
+			continue
+		}
+
+		next := make([]job, 0, len(rest)+len(requires))
+		next = append(append(next, rest...), requires...)
+		if s.solve(next) {
+			return true
+		}
+
+		delete(s.installed, strings.ToLower(e.pkg.Name))
+		s.order = s.order[:len(s.order)-1]
+		if s.tooComplex {
+			return false
+		}
+	}
+	return false
+}
+
+// lookahead 检查依赖是否可能被满足：已经满足，或池中有满足稳定性要求的候选版本
+func (s *solver) lookahead(jobs []job) string {
+	for _, j := range jobs {
+		satisfied, problem := s.satisfied(j)
+		if problem != "" {
+			return problem
+		}
+		if satisfied {
+			continue
+		}
+		if candidates, problem := s.candidates(j); len(candidates) == 0 {
+			return problem
+		}
+	}
+	return ""
+}
+
+// fail 记录问题，保留已选择的包最多时遇到的问题
+func (s *solver) fail(problem string) {
+	if len(s.order) >= s.failDepth {
+		s.failDepth = len(s.order)
+		s.failure = problem
+	}
+}
+
+// satisfied 判断依赖是否已被根包、平台包或已选择的包满足；依赖不可能再被满足时返回问题描述
+func (s *solver) satisfied(j job) (bool, string) {
+	key := strings.ToLower(j.name)
+
+	if dependency.IsPlatformPackage(j.name) {
+		if s.opts.Platform == nil {
+			return true, ""
+		}
+		for name, v := range s.opts.Platform {
+			if strings.EqualFold(name, j.name) {
+				if j.constraint.Matches(v) {
+					return true, ""
+				}
+				return false, fmt.Sprintf("%s requires %s %s but your %s version (%s) does not satisfy that requirement", j.source, j.name, j.pretty, j.name, v)
+			}
+		}
+		return false, fmt.Sprintf("%s requires %s %s but it is missing from your system", j.source, j.name, j.pretty)
+	}
+
+	if s.rootSatisfies(j) {
+		return true, ""
+	}
+
+	for _, e := range s.order {
+		if s.matches(e, j) {
+			return true, ""
+		}
+	}
+	if e, ok := s.installed[key]; ok {
+		return false, fmt.Sprintf("%s requires %s %s, but %s %s is already selected", j.source, j.name, j.pretty, e.pkg.Name, e.pkg.Version)
+	}
+	return false, ""
+}
+
+// rootSatisfies 判断根包自身或其replace、provide是否满足依赖
+func (s *solver) rootSatisfies(j job) bool {
+	root := s.root.Package
+	if root.Name != "" && strings.EqualFold(root.Name, j.name) {
+		return root.Version == "" || j.constraint.Matches(root.Version)
+	}
+	for _, links := range []map[string]string{root.Replace, root.Provide} {
+		if link, ok := lookup(links, j.name); ok {
+			if link == "self.version" {
+				link = root.Version
+			}
+			return linkIntersects(link, j.constraint)
+		}
+	}
+	return false
+}
+
+// candidates 返回可以满足依赖的包版本，按优先顺序排列；没有时返回问题描述
+func (s *solver) candidates(j job) ([]*entry, string) {
+	key := strings.ToLower(j.name)
+
+	var candidates, unstable []*entry
+	for _, e := range append(append([]*entry(nil), s.pool.byName[key]...), s.pool.providers[key]...) {
+		if !s.matches(e, j) {
+			continue
+		}
+		if ok, _ := s.stability.Allows(e.pkg.Name, e.pkg.Version); !ok {
+			unstable = append(unstable, e)
+			continue
+		}
+		candidates = append(candidates, e)
+	}
+
+	if len(candidates) == 0 {
+		prefix := fmt.Sprintf("%s requires %s %s", j.source, j.name, j.pretty)
+		switch {
+		case len(unstable) > 0:
+			return nil, fmt.Sprintf("%s, found %s but it does not match your minimum-stability", prefix, describe(unstable))
+		case len(s.pool.byName[key]) > 0:
+			return nil, fmt.Sprintf("%s, found %s but it does not match the constraint", prefix, describe(s.pool.byName[key]))
+		default:
+			return nil, fmt.Sprintf("%s, it could not be found in the pool", prefix)
+		}
+	}
+
+	vendor := ""
+	if i := strings.Index(key, "/"); i > 0 {
+		vendor = key[:i+1]
+	}
+	group := func(e *entry) int {
+		name := strings.ToLower(e.pkg.Name)
+		switch {
+		case name == key:
+			return 0
+		case vendor != "" && strings.HasPrefix(name, vendor):
+			return 1
+		}
+		return 2
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		ea, eb := candidates[a], candidates[b]
+		if ga, gb := group(ea), group(eb); ga != gb {
+			return ga < gb
+		}
+		if na, nb := strings.ToLower(ea.pkg.Name), strings.ToLower(eb.pkg.Name); na != nb {
+			return na < nb
+		}
+		if s.root.PreferStable {
+			if c := stability.Compare(version.ParseStability(ea.normalized), version.ParseStability(eb.normalized)); c != 0 {
+				return c < 0
+			}
+		}
+		c := compareEntries(ea, eb)
+		if s.opts.PreferLowest {
+			return c < 0
+		}
+		return c > 0
+	})
+	return candidates, ""
+}
+
+// matches 判断包版本是否满足依赖：同名时比较版本和别名，否则比较replace和provide的约束
+func (s *solver) matches(e *entry, j job) bool {
+	if strings.EqualFold(e.pkg.Name, j.name) {
+		for _, v := range s.versions(e) {
+			if j.constraint.Matches(v) {
+				return true
+			}
+		}
+		return false
+	}
+	return s.provides(e, j.name, j.constraint)
+}
+
+// provides 判断包版本是否通过replace或provide提供与约束相交的虚拟包
+func (s *solver) provides(e *entry, name string, c constraint.Constraint) bool {
+	for _, links := range []map[string]string{e.pkg.Replace, e.pkg.Provide} {
+		if link, ok := lookup(links, name); ok {
+			if link == "self.version" {
+				link = e.pkg.Version
+			}
+			if linkIntersects(link, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// versions 返回包版本可以匹配的所有规范化版本
+func (s *solver) versions(e *entry) []string {
+	versions := append([]string{e.normalized}, e.aliases...)
+	if alias, ok := s.rootAliases[strings.ToLower(e.pkg.Name)][e.normalized]; ok {
+		versions = append(versions, alias)
+	}
+	return versions
+}
+
+// conflicts 检查包版本能否与根包和已选择的包同时安装，不能时返回原因
+func (s *solver) conflicts(e *entry) string {
+	name := strings.ToLower(e.pkg.Name)
+	if other, ok := s.installed[name]; ok {
+		return fmt.Sprintf("%s %s is already selected", other.pkg.Name, other.pkg.Version)
+	}
+
+	root := s.root.Package
+	if _, ok := lookup(root.Replace, e.pkg.Name); ok {
+		return fmt.Sprintf("%s is replaced by the root package", e.pkg.Name)
+	}
+	if target, c, ok := s.conflictWith(root.Conflict, e); ok {
+		return fmt.Sprintf("Root composer.json conflicts with %s %s (%s %s)", e.pkg.Name, e.pkg.Version, target, c)
+	}
+
+	for _, other := range s.order {
+		if target, c, ok := s.conflictWith(other.pkg.Conflict, e); ok {
+			return fmt.Sprintf("%s %s conflicts with %s %s (%s %s)", other.pkg.Name, other.pkg.Version, e.pkg.Name, e.pkg.Version, target, c)
+		}
+		if target, c, ok := s.conflictWith(e.pkg.Conflict, other); ok {
+			return fmt.Sprintf("%s %s conflicts with %s %s (%s %s)", e.pkg.Name, e.pkg.Version, other.pkg.Name, other.pkg.Version, target, c)
+		}
+		if _, ok := lookup(e.pkg.Replace, other.pkg.Name); ok {
+			return fmt.Sprintf("%s %s replaces the selected %s %s", e.pkg.Name, e.pkg.Version, other.pkg.Name, other.pkg.Version)
+		}
+		if _, ok := lookup(other.pkg.Replace, e.pkg.Name); ok {
+			return fmt.Sprintf("%s is replaced by the selected %s %s", e.pkg.Name, other.pkg.Name, other.pkg.Version)
+		}
+		for target := range e.pkg.Replace {
+			if _, ok := lookup(other.pkg.Replace, target); ok {
+				return fmt.Sprintf("%s %s and the selected %s %s both replace %s", e.pkg.Name, e.pkg.Version, other.pkg.Name, other.pkg.Version, target)
+			}
+		}
+	}
+	return ""
+}
+
+// conflictWith 判断conflict中是否有约束命中包版本，命中时返回conflict中的包名和约束
+func (s *solver) conflictWith(conflict map[string]string, e *entry) (string, string, bool) {
+	for _, target := range sortedKeys(conflict) {
+		c, err := constraint.Parse(conflict[target])
+		if err != nil {
+			continue
+		}
+		if s.matches(e, job{name: target, constraint: c}) {
+			return target, conflict[target], true
+		}
+	}
+	return "", "", false
+}
+
+// requires 返回包版本的依赖
+func (s *solver) requires(e *entry) ([]job, error) {
+	source := e.pkg.Name + " " + e.pkg.Version
+	jobs := make([]job, 0, len(e.pkg.Require))
+	for _, name := range sortedKeys(e.pkg.Require) {
+		c := e.pkg.Require[name]
+		if c == "self.version" {
+			c = e.pkg.Version
+		}
+		j, err := newJob(name, c, source)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, nil
+}
+
+// solution 根据已选择的包生成结果，从根包require出发可以到达的包属于packages，其余属于packages-dev
+func (s *solver) solution() *Solution {
+	production := make(map[*entry]bool)
+	queue := make([]map[string]string, 0, len(s.order)+1)
+	queue = append(queue, s.root.Package.Require)
+	for len(queue) > 0 {
+		require := queue[0]
+		queue = queue[1:]
+		for name, c := range require {
+			parsed, err := constraint.Parse(c)
+			if err != nil {
+				continue
+			}
+			for _, e := range s.order {
+				if !production[e] && s.matches(e, job{name: name, constraint: parsed}) {
+					production[e] = true
+					queue = append(queue, selfVersion(e.pkg.Require, e.pkg.Version))
+				}
+			}
+		}
+	}
+
+	sol := &Solution{Packages: []lock.LockedPackage{}, PackagesDev: []lock.LockedPackage{}, Aliases: s.aliases, PreferLowest: s.opts.PreferLowest, NoDev: s.opts.NoDev}
+	for _, e := range s.order {
+		if production[e] {
+			sol.Packages = append(sol.Packages, *e.pkg)
+		} else {
+			sol.PackagesDev = append(sol.PackagesDev, *e.pkg)
+		}
+	}
+	for _, pkgs := range [][]lock.LockedPackage{sol.Packages, sol.PackagesDev} {
+		sort.Slice(pkgs, func(i, j int) bool {
+			return strings.ToLower(pkgs[i].Name) < strings.ToLower(pkgs[j].Name)
+		})
+	}
+	return sol
+}
+
+// selfVersion 返回把"self.version"替换为包版本后的依赖
+func selfVersion(require map[string]string, v string) map[string]string {
+	result := make(map[string]string, len(require))
+	for name, c := range require {
+		if c == "self.version" {
+			c = v
+		}
+		result[name] = c
+	}
+	return result
+}
+
+// linkIntersects 判断replace或provide的约束是否与依赖的约束相交
+func linkIntersects(link string, c constraint.Constraint) bool {
+	parsed, err := constraint.Parse(link)
+	return err == nil && constraint.Intersects(parsed, c)
+}
+
+// lookup 不区分大小写地查找链接
+func lookup(links map[string]string, name string) (string, bool) {
+	if c, ok := links[name]; ok {
+		return c, true
+	}
+	for target, c := range links {
+		if strings.EqualFold(target, name) {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// describe 返回包版本列表的描述，如"monolog/monolog[2.9.1, 3.5.0]"
+func describe(entries []*entry) string {
+	sorted := append([]*entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareEntries(sorted[i], sorted[j]) < 0
+	})
+
+	versions := make([]string, 0, len(sorted))
+	for _, e := range sorted {
+		versions = append(versions, e.pkg.Version)
+	}
+	return fmt.Sprintf("%s[%s]", sorted[0].pkg.Name, strings.Join(versions, ", "))
+}
+
+// sortedKeys 返回按字母顺序排列的键
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
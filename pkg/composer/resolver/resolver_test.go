@@ -0,0 +1,290 @@
+package resolver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+)
+
+// pkg 创建测试用的包版本，links依次为require、conflict、replace、provide
+func pkg(name, v string, links ...map[string]string) lock.LockedPackage {
+	p := lock.LockedPackage{Name: name, Version: v}
+	for i, l := range links {
+		switch i {
+		case 0:
+			p.Require = l
+		case 1:
+			p.Conflict = l
+		case 2:
+			p.Replace = l
+		case 3:
+			p.Provide = l
+		}
+	}
+	return p
+}
+
+func mustPool(t *testing.T, packages ...lock.LockedPackage) *Pool {
+	t.Helper()
+	pool, err := NewPool(packages...)
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	return pool
+}
+
+func mustResolve(t *testing.T, root Root, pool *Pool, opts Options) *Solution {
+	t.Helper()
+	sol, err := Resolve(root, pool, opts)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	return sol
+}
+
+// selected 以"name version"的形式返回选中的包，packages-dev中的包带有" (dev)"后缀
+func selected(sol *Solution) string {
+	var out []string
+	for _, p := range sol.Packages {
+		out = append(out, p.Name+" "+p.Version)
+	}
+	for _, p := range sol.PackagesDev {
+		out = append(out, p.Name+" "+p.Version+" (dev)")
+	}
+	return strings.Join(out, ", ")
+}
+
+func rootRequiring(require map[string]string) Root {
+	return Root{Package: lock.LockedPackage{Name: "acme/app", Require: require}}
+}
+
+func TestResolve_HighestVersionsAndTransitive(t *testing.T) {
+	pool := mustPool(t,
+		pkg("monolog/monolog", "2.9.1", map[string]string{"psr/log": "^1.0 || ^2.0"}),
+		pkg("monolog/monolog", "3.5.0", map[string]string{"psr/log": "^2.0 || ^3.0"}),
+		pkg("psr/log", "1.1.4"),
+		pkg("psr/log", "2.0.0"),
+		pkg("psr/log", "3.0.0"),
+	)
+
+	sol := mustResolve(t, rootRequiring(map[string]string{"monolog/monolog": "^3.0"}), pool, Options{})
+	if got, want := selected(sol), "monolog/monolog 3.5.0, psr/log 3.0.0"; got != want {
+		t.Errorf("Resolve() = %s, want %s", got, want)
+	}
+
+	sol = mustResolve(t, rootRequiring(map[string]string{"monolog/monolog": "*", "psr/log": "^1.0"}), pool, Options{})
+	if got, want := selected(sol), "monolog/monolog 2.9.1, psr/log 1.1.4"; got != want {
+		t.Errorf("Resolve() with psr/log ^1.0 = %s, want %s", got, want)
+	}
+
+	sol = mustResolve(t, rootRequiring(map[string]string{"monolog/monolog": "*"}), pool, Options{PreferLowest: true})
+	if got, want := selected(sol), "monolog/monolog 2.9.1, psr/log 1.1.4"; got != want {
+		t.Errorf("Resolve() with PreferLowest = %s, want %s", got, want)
+	}
+}
+
+func TestResolve_Backtracking(t *testing.T) {
+	pool := mustPool(t,
+		pkg("acme/a", "1.0.0", map[string]string{"acme/b": "^1.0"}),
+		pkg("acme/a", "2.0.0", map[string]string{"acme/b": "^2.0"}),
+		pkg("acme/b", "1.0.0"),
+		pkg("acme/b", "2.0.0", map[string]string{"acme/c": "^1.0"}),
+		pkg("acme/c", "1.0.0", map[string]string{"acme/d": "^1.0"}),
+	)
+
+	// acme/b 2.0.0需要的acme/d不存在，只能回退到acme/a 1.0.0
+	sol := mustResolve(t, rootRequiring(map[string]string{"acme/a": "*"}), pool, Options{})
+	if got, want := selected(sol), "acme/a 1.0.0, acme/b 1.0.0"; got != want {
+		t.Errorf("Resolve() = %s, want %s", got, want)
+	}
+}
+
+func TestResolve_Conflicts(t *testing.T) {
+	pool := mustPool(t,
+		pkg("acme/a", "1.0.0"),
+		pkg("acme/a", "1.1.0"),
+		pkg("acme/b", "1.0.0", nil, map[string]string{"acme/a": ">=1.1"}),
+	)
+
+	root := rootRequiring(map[string]string{"acme/a": "^1.0"})
+	root.Package.Conflict = map[string]string{"acme/a": "1.1.0"}
+	if got, want := selected(mustResolve(t, root, pool, Options{})), "acme/a 1.0.0"; got != want {
+		t.Errorf("Resolve() with root conflict = %s, want %s", got, want)
+	}
+
+	sol := mustResolve(t, rootRequiring(map[string]string{"acme/a": "^1.0", "acme/b": "^1.0"}), pool, Options{})
+	if got, want := selected(sol), "acme/a 1.0.0, acme/b 1.0.0"; got != want {
+		t.Errorf("Resolve() with package conflict = %s, want %s", got, want)
+	}
+
+	_, err := Resolve(rootRequiring(map[string]string{"acme/a": "^1.1", "acme/b": "^1.0"}), pool, Options{})
+	var resolveErr *Error
+	if !errors.As(err, &resolveErr) || !strings.Contains(resolveErr.Problem, "conflicts with") {
+		t.Errorf("Resolve() error = %v, want conflict problem", err)
+	}
+}
+
+func TestResolve_ReplaceAndProvide(t *testing.T) {
+	pool := mustPool(t,
+		pkg("monolog/monolog", "3.5.0", map[string]string{"psr/log": "^3.0"}, nil, nil, map[string]string{"psr/log-implementation": "3.0.0"}),
+		pkg("psr/log", "3.0.0"),
+		pkg("acme/log-bundle", "1.0.0", nil, nil, nil, map[string]string{"psr/log-implementation": "3.0.0"}),
+		pkg("symfony/symfony", "6.4.0", nil, nil, map[string]string{"symfony/console": "self.version"}),
+		pkg("symfony/console", "6.4.0"),
+		pkg("symfony/console", "5.4.0"),
+	)
+
+	// 虚拟包由提供它的包满足，不同供应商的包按名称排序
+	sol := mustResolve(t, rootRequiring(map[string]string{"psr/log-implementation": "^3.0"}), pool, Options{})
+	if got, want := selected(sol), "acme/log-bundle 1.0.0"; got != want {
+		t.Errorf("Resolve() virtual package = %s, want %s", got, want)
+	}
+
+	// 被替代的包与替代它的包不能同时选择
+	sol = mustResolve(t, rootRequiring(map[string]string{"symfony/symfony": "^6.4", "symfony/console": "^6.0"}), pool, Options{})
+	if got, want := selected(sol), "symfony/symfony 6.4.0"; got != want {
+		t.Errorf("Resolve() replaced package = %s, want %s", got, want)
+	}
+	if _, err := Resolve(rootRequiring(map[string]string{"symfony/symfony": "^6.4", "symfony/console": "^5.4"}), pool, Options{}); err == nil {
+		t.Error("Resolve() with console ^5.4 and symfony/symfony 6.4 expected error, got nil")
+	}
+
+	// 同名的包优先于替代它的包
+	sol = mustResolve(t, rootRequiring(map[string]string{"symfony/console": "^6.0"}), pool, Options{})
+	if got, want := selected(sol), "symfony/console 6.4.0"; got != want {
+		t.Errorf("Resolve() original package = %s, want %s", got, want)
+	}
+
+	// 根包replace的包不会被选择
+	root := rootRequiring(map[string]string{"symfony/console": "^6.0"})
+	root.Package.Replace = map[string]string{"symfony/console": "*"}
+	if sol := mustResolve(t, root, pool, Options{}); len(sol.Packages) != 0 {
+		t.Errorf("Resolve() with root replace = %s, want nothing", selected(sol))
+	}
+}
+
+func TestResolve_Stability(t *testing.T) {
+	pool := mustPool(t,
+		pkg("acme/a", "1.0.0"),
+		pkg("acme/a", "1.1.0-beta1"),
+		pkg("acme/a", "dev-main"),
+	)
+
+	tests := []struct {
+		name string
+		root Root
+		want string
+	}{
+		{"stable", rootRequiring(map[string]string{"acme/a": "*"}), "acme/a 1.0.0"},
+		{"flag", rootRequiring(map[string]string{"acme/a": "^1.0@beta"}), "acme/a 1.1.0-beta1"},
+		{"minimum beta", Root{Package: rootRequiring(map[string]string{"acme/a": "^1.0"}).Package, MinimumStability: "beta"}, "acme/a 1.1.0-beta1"},
+		{"prefer-stable", Root{Package: rootRequiring(map[string]string{"acme/a": "^1.0"}).Package, MinimumStability: "dev", PreferStable: true}, "acme/a 1.0.0"},
+		{"branch", rootRequiring(map[string]string{"acme/a": "dev-main"}), "acme/a dev-main"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selected(mustResolve(t, tt.root, pool, Options{})); got != tt.want {
+				t.Errorf("Resolve() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	_, err := Resolve(rootRequiring(map[string]string{"acme/a": "^1.1"}), pool, Options{})
+	if err == nil || !strings.Contains(err.Error(), "minimum-stability") {
+		t.Errorf("Resolve() error = %v, want minimum-stability problem", err)
+	}
+}
+
+func TestResolve_Aliases(t *testing.T) {
+	main := pkg("acme/a", "dev-main")
+	main.Extra = map[string]interface{}{"branch-alias": map[string]interface{}{"dev-main": "2.x-dev"}}
+	pool := mustPool(t,
+		main,
+		pkg("acme/a", "dev-feature"),
+		pkg("acme/b", "1.0.0", map[string]string{"acme/a": "^1.0"}),
+		pkg("acme/c", "1.0.0", map[string]string{"acme/a": "^2.0@dev"}),
+	)
+
+	// 根包的内联别名让dev-feature满足acme/b的^1.0
+	root := rootRequiring(map[string]string{"acme/a": "dev-feature as 1.0.0", "acme/b": "^1.0"})
+	sol := mustResolve(t, root, pool, Options{})
+	if got, want := selected(sol), "acme/a dev-feature, acme/b 1.0.0"; got != want {
+		t.Errorf("Resolve() = %s, want %s", got, want)
+	}
+	if len(sol.Aliases) != 1 || sol.Aliases[0].Alias != "1.0.0" || sol.Aliases[0].AliasNormalized != "1.0.0.0" || sol.Aliases[0].Version != "dev-feature" {
+		t.Errorf("Aliases = %+v", sol.Aliases)
+	}
+
+	// 分支别名
+	root = Root{Package: rootRequiring(map[string]string{"acme/c": "^1.0"}).Package, MinimumStability: "dev"}
+	if got, want := selected(mustResolve(t, root, pool, Options{})), "acme/a dev-main, acme/c 1.0.0"; got != want {
+		t.Errorf("Resolve() with branch alias = %s, want %s", got, want)
+	}
+}
+
+func TestResolve_PlatformAndDev(t *testing.T) {
+	pool := mustPool(t,
+		pkg("acme/a", "1.0.0", map[string]string{"php": ">=8.0"}),
+		pkg("acme/a", "2.0.0", map[string]string{"php": ">=8.2", "ext-intl": "*"}),
+		pkg("phpunit/phpunit", "10.5.0", map[string]string{"acme/shared": "^1.0"}),
+		pkg("acme/shared", "1.0.0"),
+	)
+
+	root := rootRequiring(map[string]string{"acme/a": "*"})
+	root.Package.RequireDev = map[string]string{"phpunit/phpunit": "^10.0"}
+
+	sol := mustResolve(t, root, pool, Options{Platform: map[string]string{"php": "8.1.2"}})
+	if got, want := selected(sol), "acme/a 1.0.0, acme/shared 1.0.0 (dev), phpunit/phpunit 10.5.0 (dev)"; got != want {
+		t.Errorf("Resolve() = %s, want %s", got, want)
+	}
+	if p, dev := sol.Find("PHPUnit/PHPUnit"); p == nil || !dev {
+		t.Errorf("Find(phpunit) = %v, %v", p, dev)
+	}
+
+	sol = mustResolve(t, root, pool, Options{Platform: map[string]string{"php": "8.2.0", "ext-intl": "8.2.0"}, NoDev: true})
+	if got, want := selected(sol), "acme/a 2.0.0"; got != want {
+		t.Errorf("Resolve() with NoDev = %s, want %s", got, want)
+	}
+
+	// 不提供平台包时忽略平台包依赖
+	sol = mustResolve(t, root, pool, Options{NoDev: true})
+	if got, want := selected(sol), "acme/a 2.0.0"; got != want {
+		t.Errorf("Resolve() without platform = %s, want %s", got, want)
+	}
+
+	_, err := Resolve(rootRequiring(map[string]string{"php": "^8.3"}), pool, Options{Platform: map[string]string{"php": "8.2.0"}})
+	if err == nil || !strings.Contains(err.Error(), "does not satisfy") {
+		t.Errorf("Resolve() error = %v, want platform problem", err)
+	}
+}
+
+func TestResolve_Errors(t *testing.T) {
+	pool := mustPool(t, pkg("acme/a", "1.0.0"))
+
+	_, err := Resolve(rootRequiring(map[string]string{"acme/missing": "^1.0"}), pool, Options{})
+	var resolveErr *Error
+	if !errors.As(err, &resolveErr) || resolveErr.Problem != "Root composer.json requires acme/missing ^1.0, it could not be found in the pool" {
+		t.Errorf("Resolve() error = %v", err)
+	}
+
+	_, err = Resolve(rootRequiring(map[string]string{"acme/a": "^2.0"}), pool, Options{})
+	if err == nil || !strings.Contains(err.Error(), "found acme/a[1.0.0] but it does not match the constraint") {
+		t.Errorf("Resolve() error = %v", err)
+	}
+
+	if _, err := Resolve(rootRequiring(map[string]string{"acme/a": "~>1.0"}), pool, Options{}); err == nil || errors.As(err, &resolveErr) {
+		t.Errorf("Resolve() with invalid constraint error = %v", err)
+	}
+
+	var packages []lock.LockedPackage
+	for _, v := range []string{"1.0.0", "1.1.0", "1.2.0", "1.3.0"} {
+		packages = append(packages, pkg("acme/x", v), pkg("acme/y", v))
+	}
+	packages = append(packages, pkg("acme/z", "1.0.0", map[string]string{"acme/x": "<1.0"}))
+	_, err = Resolve(rootRequiring(map[string]string{"acme/x": "*", "acme/y": "*", "acme/z": "*"}), mustPool(t, packages...), Options{MaxSteps: 5})
+	if !errors.Is(err, ErrTooComplex) {
+		t.Errorf("Resolve() with MaxSteps error = %v, want ErrTooComplex", err)
+	}
+}
@@ -0,0 +1,80 @@
+package composer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/lock"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/resolver"
+)
+
+func TestComposerJSON_Resolve(t *testing.T) {
+	c, err := ParseString(`{
+		"name": "acme/app",
+		"require": {"php": "^8.2", "acme/lib": "^1.0"},
+		"require-dev": {"acme/test": "^2.0"},
+		"minimum-stability": "beta",
+		"config": {"platform": {"php": "8.2.0"}}
+	}`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+	pool, err := resolver.NewPool(
+		lock.LockedPackage{Name: "acme/lib", Version: "1.0.0"},
+		lock.LockedPackage{Name: "acme/lib", Version: "1.1.0-beta1"},
+		lock.LockedPackage{Name: "acme/test", Version: "2.0.0", Require: map[string]string{"acme/lib": "*"}},
+	)
+	if err != nil {
+		t.Fatalf("NewPool() returned unexpected error: %v", err)
+	}
+
+	solution, err := c.Resolve(pool, resolver.Options{Platform: map[string]string{"php": "8.1.0"}})
+	if err != nil {
+		t.Fatalf("Resolve() returned unexpected error: %v", err)
+	}
+	if len(solution.Packages) != 1 || solution.Packages[0].Version != "1.1.0-beta1" {
+		t.Errorf("Resolve() packages = %+v, want acme/lib 1.1.0-beta1", solution.Packages)
+	}
+	if len(solution.PackagesDev) != 1 || solution.PackagesDev[0].Name != "acme/test" {
+		t.Errorf("Resolve() packages-dev = %+v, want acme/test", solution.PackagesDev)
+	}
+
+	c.Config.Platform = nil
+	var resolveErr *resolver.Error
+	if _, err := c.Resolve(pool, resolver.Options{Platform: map[string]string{"php": "8.1.0"}}); !errors.As(err, &resolveErr) {
+		t.Errorf("Resolve() without config.platform error = %v, want *resolver.Error", err)
+	}
+}
+
+func TestComposerJSON_ResolveLock(t *testing.T) {
+	c, err := ParseString(`{
+    "name": "acme/app",
+    "require": {
+        "acme/lib": "^1.0"
+    }
+}`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+	pool, err := resolver.NewPool(lock.LockedPackage{Name: "acme/lib", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("NewPool() returned unexpected error: %v", err)
+	}
+
+	l, err := c.ResolveLock(pool, resolver.Options{})
+	if err != nil {
+		t.Fatalf("ResolveLock() returned unexpected error: %v", err)
+	}
+	if len(l.Packages) != 1 || l.Packages[0].Name != "acme/lib" {
+		t.Errorf("ResolveLock() packages = %+v, want acme/lib", l.Packages)
+	}
+	freshness, err := IsLockFresh(c, l)
+	if err != nil || !freshness.Fresh {
+		t.Errorf("IsLockFresh() = %+v, %v, want fresh", freshness, err)
+	}
+
+	c.Require["acme/missing"] = "^1.0"
+	if _, err := c.ResolveLock(pool, resolver.Options{}); err == nil {
+		t.Error("ResolveLock() with an unresolvable requirement expected error, got nil")
+	}
+}
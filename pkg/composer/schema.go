@@ -0,0 +1,61 @@
+package composer
+
+import (
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/parser"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/schema"
+)
+
+// ValidateSchema 按Composer发布的JSON Schema（composer-schema.json）校验composer.json的结构
+//
+// 参数:
+//   - opts: 校验选项，Lax为true时允许额外的顶层属性，不要求name和description
+//
+// 返回:
+//   - []schema.Violation: 所有违规，每个违规包括JSON指针、Schema关键字和说明；文档有效时返回nil
+//   - error: 如果序列化composer.json失败，返回错误
+//
+// 校验使用ToJSON(true)的输出，因此保留了原始文档中未映射到结构体的字段。
+//
+// 示例:
+//
+//	project, _ := composer.ParseDir(".")
+//
+//	violations, err := project.ValidateSchema(schema.Options{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, v := range violations {
+//		fmt.Printf("%s (%s)\n", v, v.Keyword)
+//	}
+func (c *ComposerJSON) ValidateSchema(opts schema.Options) ([]schema.Violation, error) {
+	data, err := c.ToJSON(true)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := parser.ParseString(data)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Validate(raw, opts), nil
+}
+
+// ValidateSchemaFile 按Composer发布的JSON Schema校验composer.json文件的原始内容
+//
+// 参数:
+//   - filePath: composer.json文件路径
+//   - opts: 校验选项
+//
+// 返回:
+//   - []schema.Violation: 所有违规；文档有效时返回nil
+//   - error: 如果读取文件失败或内容不是有效的JSON对象，返回错误
+//
+// 示例:
+//
+//	violations, err := composer.ValidateSchemaFile("./composer.json", schema.Options{Lax: true})
+func ValidateSchemaFile(filePath string, opts schema.Options) ([]schema.Violation, error) {
+	raw, err := parser.ParseFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Validate(raw, opts), nil
+}
@@ -0,0 +1,76 @@
+package schema
+
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+// composerSchemaData 是Composer发布的composer.json的JSON Schema（res/composer-schema.json）
+//
+//go:embed composer-schema.json
+var composerSchemaData []byte
+
+// composerSchema 和 composerLaxSchema 是编译后的严格模式和宽松模式Schema
+var composerSchema, composerLaxSchema = loadComposerSchema()
+
+// loadComposerSchema 编译内嵌的Schema，宽松模式与Composer的JsonFile::LAX_SCHEMA一致，
+// 允许额外的属性并且不要求name和description
+func loadComposerSchema() (*Schema, *Schema) {
+	strict, err := Compile(composerSchemaData)
+	if err != nil {
+		panic("schema: invalid embedded composer schema: " + err.Error())
+	}
+
+	var root map[string]interface{}
+	if err := json.Unmarshal(composerSchemaData, &root); err != nil {
+		panic("schema: invalid embedded composer schema: " + err.Error())
+	}
+	root["additionalProperties"] = true
+	delete(root, "required")
+	lax, err := compile(root)
+	if err != nil {
+		panic("schema: invalid embedded composer schema: " + err.Error())
+	}
+	return strict, lax
+}
+
+// ComposerSchema 返回内嵌的composer-schema.json的内容
+//
+// 示例:
+//
+//	os.WriteFile("composer-schema.json", schema.ComposerSchema(), 0644)
+func ComposerSchema() []byte {
+	return append([]byte(nil), composerSchemaData...)
+}
+
+// Options 是按Composer的Schema校验时的选项
+type Options struct {
+	// Lax 使用宽松模式：允许Schema中未定义的顶层属性，不要求name和description；
+	// 与`composer validate`对无法发布的项目（如只有require的应用）使用的模式一致
+	Lax bool
+}
+
+// Validate 按内嵌的Composer JSON Schema校验composer.json
+//
+// 参数:
+//   - instance: 解析后的原始composer.json，如parser.Parse或parser.ParseBytes的结果
+//   - opts: 校验选项
+//
+// 返回:
+//   - []Violation: 所有违规，每个违规包括JSON指针、Schema关键字和说明；文档有效时返回nil
+//
+// 示例:
+//
+//	raw, err := parser.ParseFile("composer.json")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, v := range schema.Validate(raw, schema.Options{}) {
+//		fmt.Printf("%s (%s)\n", v, v.Keyword) // /autoload/psr-4: String value found, but an object is required (type)
+//	}
+func Validate(instance interface{}, opts Options) []Violation {
+	if opts.Lax {
+		return composerLaxSchema.Validate(instance)
+	}
+	return composerSchema.Validate(instance)
+}
@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestValidate_Valid(t *testing.T) {
+	instance := decode(t, `{
+    "name": "acme/app",
+    "description": "An example application",
+    "type": "project",
+    "license": ["MIT"],
+    "homepage": "https://example.com",
+    "authors": [{"name": "Jane", "email": "jane@example.com", "role": "Developer"}],
+    "support": {"issues": "https://github.com/acme/app/issues", "irc": "irc://irc.libera.chat/acme"},
+    "require": {"php": ">=8.1", "psr/log": "^3.0"},
+    "require-dev": {"phpunit/phpunit": "^10.5"},
+    "autoload": {"psr-4": {"Acme\\": ["src/", "lib/"]}, "files": ["helpers.php"]},
+    "repositories": [
+        {"type": "composer", "url": "https://repo.example.com"},
+        {"type": "vcs", "url": "https://github.com/acme/lib"},
+        {"type": "path", "url": "../lib", "options": {"symlink": true}},
+        {"type": "package", "package": {"name": "acme/blob", "version": "1.0.0"}},
+        {"packagist.org": false}
+    ],
+    "minimum-stability": "dev",
+    "prefer-stable": true,
+    "config": {"sort-packages": true, "process-timeout": 600, "platform": {"php": "8.2.0", "ext-mongo": false}},
+    "scripts": {"test": "phpunit", "post-install-cmd": ["@test"]},
+    "extra": {"branch-alias": {"dev-main": "1.x-dev"}}
+}`)
+	if got := Validate(instance, Options{}); got != nil {
+		t.Errorf("Validate() = %v, want no violations", got)
+	}
+}
+
+func TestValidate_Violations(t *testing.T) {
+	instance := decode(t, `{
+    "name": "Acme/App",
+    "type": "project",
+    "unknown": true,
+    "autoload": {"psr-4": "src/", "classmap": "lib/"},
+    "repositories": [{"type": "vcs"}, {"type": "unknown", "url": "x"}, {"packagist.org": true}],
+    "scripts": {"test": 1},
+    "minimum-stability": "unstable",
+    "config": {"process-timeout": "600"}
+}`)
+
+	want := []Violation{
+		{"/description", "required", "/required", "The property description is required"},
+		{"/autoload/classmap", "type", "/definitions/autoload/properties/classmap/type", "String value found, but an array is required"},
+		{"/autoload/psr-4", "type", "/definitions/autoload/properties/psr-4/type", "String value found, but an object is required"},
+		{"/config/process-timeout", "type", "/properties/config/properties/process-timeout/type", "String value found, but an integer is required"},
+		{"/minimum-stability", "enum", "/properties/minimum-stability/enum", `Does not have a value in the enumeration ["dev","alpha","beta","rc","RC","stable"]`},
+		{"/name", "pattern", "/properties/name/pattern", "Does not match the regex pattern ^[a-z0-9]([_.-]?[a-z0-9]+)*/[a-z0-9](([_.]|-{1,2})?[a-z0-9]+)*$"},
+		{"/repositories/0", "anyOf", "/properties/repositories/items/anyOf", "Failed to match at least one schema"},
+		{"/repositories/1", "anyOf", "/properties/repositories/items/anyOf", "Failed to match at least one schema"},
+		{"/repositories/2", "anyOf", "/properties/repositories/items/anyOf", "Failed to match at least one schema"},
+		{"/scripts/test", "type", "/properties/scripts/additionalProperties/type", "Integer value found, but a string or an array is required"},
+		{"/unknown", "additionalProperties", "/additionalProperties", "The property unknown is not defined and the definition does not allow additional properties"},
+	}
+	got := Validate(instance, Options{})
+	if len(got) != len(want) {
+		t.Fatalf("Validate() = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("Validate()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestValidate_Lax(t *testing.T) {
+	instance := decode(t, `{"require": {"psr/log": "^3.0"}, "custom": true, "autoload": {"files": "x.php"}}`)
+
+	strict := Validate(instance, Options{})
+	if len(strict) != 4 {
+		t.Errorf("Validate() strict = %v, want 4 violations", strict)
+	}
+
+	lax := Validate(instance, Options{Lax: true})
+	if len(lax) != 1 || lax[0].Pointer != "/autoload/files" {
+		t.Errorf("Validate() lax = %v, want only the /autoload/files violation", lax)
+	}
+}
+
+func TestComposerSchema(t *testing.T) {
+	data := ComposerSchema()
+	if !bytes.Contains(data, []byte(`"title": "Composer Package"`)) {
+		t.Error("ComposerSchema() does not return the embedded schema")
+	}
+	data[0] = 'x'
+	if ComposerSchema()[0] == 'x' {
+		t.Error("ComposerSchema() must return a copy")
+	}
+}
@@ -0,0 +1,205 @@
+// Package schema 提供JSON Schema（draft-04）校验，并内嵌Composer发布的composer-schema.json
+//
+// validation包按`composer validate`的规则逐项检查composer.json，只在需要某个值时才检查它的类型；
+// 本包则按Composer的JSON Schema检查整个文档的结构，例如autoload中类型错误的值、
+// 无效的repositories项、scripts中既不是字符串也不是数组的值，并报告每一处违规的JSON指针和Schema关键字。
+//
+// 校验器实现了draft-04的全部校验关键字（type、enum、allOf、anyOf、oneOf、not、字符串、数值、数组和对象的约束），
+// 以及文档内的$ref引用和email、uri、date-time格式。错误信息使用Composer所用的justinrainbow/json-schema的措辞。
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/parser"
+)
+
+// ErrInvalidSchema 表示Schema本身无效，例如不是JSON对象、正则表达式无法编译或$ref无法解析
+var ErrInvalidSchema = errors.New("invalid JSON schema")
+
+// Violation 是实例违反Schema的一处问题
+type Violation struct {
+	// Pointer 违规值的JSON指针，如"/autoload/psr-4"；缺少必需属性或出现不允许的属性时指向该属性，
+	// 如"/description"；违规的是根对象时为空字符串
+	Pointer string
+
+	// Keyword 违反的Schema关键字，如"type"、"required"、"additionalProperties"
+	Keyword string
+
+	// SchemaPointer 违反的关键字在Schema中的JSON指针，$ref会被解析，如"/definitions/autoload/properties/psr-4/type"
+	SchemaPointer string
+
+	// Message 问题说明，如"String value found, but an object is required"
+	Message string
+}
+
+// String 把违规格式化为"pointer: message"
+func (v Violation) String() string {
+	if v.Pointer == "" {
+		return v.Message
+	}
+	return fmt.Sprintf("%s: %s", v.Pointer, v.Message)
+}
+
+// Schema 是编译后的JSON Schema，可以并发使用
+type Schema struct {
+	root interface{}
+
+	// patterns 缓存pattern和patternProperties中编译后的正则表达式
+	patterns map[string]*regexp.Regexp
+}
+
+// Compile 编译JSON Schema
+//
+// 参数:
+//   - data: draft-04 JSON Schema文档
+//
+// 返回:
+//   - *Schema: 编译后的Schema
+//   - error: 如果data不是JSON对象、正则表达式无法编译或$ref无法解析，返回包装了ErrInvalidSchema的错误
+//
+// 示例:
+//
+//	s, err := schema.Compile([]byte(`{"type": "object", "required": ["name"]}`))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, v := range s.Validate(map[string]interface{}{}) {
+//		fmt.Println(v) // /name: The property name is required
+//	}
+func Compile(data []byte) (*Schema, error) {
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	return compile(root)
+}
+
+// compile 编译已经解码的Schema
+func compile(root interface{}) (*Schema, error) {
+	if _, ok := root.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("%w: schema must be an object", ErrInvalidSchema)
+	}
+	s := &Schema{root: root, patterns: make(map[string]*regexp.Regexp)}
+	if err := s.prepare(root, ""); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// prepare 递归地编译正则表达式并检查$ref，path是value在Schema中的JSON指针
+func (s *Schema) prepare(value interface{}, path string) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			if _, _, err := s.resolve(ref); err != nil {
+				return fmt.Errorf("%w at %s", err, path)
+			}
+		}
+		if pattern, ok := v["pattern"].(string); ok {
+			if err := s.addPattern(pattern); err != nil {
+				return fmt.Errorf("%w at %s/pattern", err, path)
+			}
+		}
+		if props, ok := v["patternProperties"].(map[string]interface{}); ok {
+			for pattern := range props {
+				if err := s.addPattern(pattern); err != nil {
+					return fmt.Errorf("%w at %s/patternProperties", err, path)
+				}
+			}
+		}
+		for key, child := range v {
+			switch key {
+			case "enum", "default":
+				// enum和default中的值是数据而不是Schema
+				continue
+			case "properties", "patternProperties", "definitions", "dependencies":
+				// 这些关键字的值是名称到Schema的映射，名称可能与关键字相同，如名为"default"的属性
+				if schemas, ok := child.(map[string]interface{}); ok {
+					for name, sub := range schemas {
+						if err := s.prepare(sub, path+"/"+key+"/"+parser.EscapePointer(name)); err != nil {
+							return err
+						}
+					}
+					continue
+				}
+			}
+			if err := s.prepare(child, path+"/"+parser.EscapePointer(key)); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			if err := s.prepare(child, fmt.Sprintf("%s/%d", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pattern 返回编译后的正则表达式；prepare没有缓存的（如$ref指向的未遍历的位置）现场编译，无法编译时返回错误
+func (s *Schema) pattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := s.patterns[pattern]; ok {
+		return re, nil
+	}
+	return regexp.Compile(pattern)
+}
+
+// addPattern 编译并缓存正则表达式
+func (s *Schema) addPattern(pattern string) error {
+	if _, ok := s.patterns[pattern]; ok {
+		return nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	s.patterns[pattern] = re
+	return nil
+}
+
+// resolve 解析文档内的$ref，如"#/definitions/authors"，返回目标Schema和它的JSON指针
+func (s *Schema) resolve(ref string) (interface{}, string, error) {
+	if ref != "#" && !strings.HasPrefix(ref, "#/") {
+		return nil, "", fmt.Errorf("%w: unsupported $ref %q, only references within the schema are supported", ErrInvalidSchema, ref)
+	}
+
+	target := s.root
+	path := strings.TrimPrefix(ref, "#")
+	if path == "" {
+		return target, "", nil
+	}
+	for _, token := range strings.Split(path[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch t := target.(type) {
+		case map[string]interface{}:
+			target = t[token]
+		default:
+			target = nil
+		}
+		if target == nil {
+			return nil, "", fmt.Errorf("%w: unresolvable $ref %q", ErrInvalidSchema, ref)
+		}
+	}
+	return target, path, nil
+}
+
+// Validate 按Schema校验实例
+//
+// 参数:
+//   - instance: 由encoding/json解码的值，如parser.ParseBytes的结果；数字可以是float64、json.Number、int或int64
+//
+// 返回:
+//   - []Violation: 所有违规，按文档中的位置排列，对象的属性按名称排序；实例有效时返回nil
+//
+// 实例的类型不符合type时不再检查该值的其他关键字；anyOf、oneOf和not只报告组合关键字本身，不报告各分支的问题。
+func (s *Schema) Validate(instance interface{}) []Violation {
+	v := &validator{schema: s}
+	v.validate(instance, s.root, "", "")
+	return v.violations
+}
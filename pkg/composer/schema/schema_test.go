@@ -0,0 +1,267 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+)
+
+// decode 解码测试用的JSON实例
+func decode(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("invalid test JSON %s: %v", s, err)
+	}
+	return v
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []string{
+		`not json`,
+		`[]`,
+		`{"pattern": "("}`,
+		`{"patternProperties": {"[": {}}}`,
+		`{"$ref": "#/definitions/missing"}`,
+		`{"$ref": "http://json-schema.org/draft-04/schema#"}`,
+		`{"properties": {"a": {"$ref": "#/definitions/a"}}}`,
+	}
+	for _, data := range tests {
+		if _, err := Compile([]byte(data)); !errors.Is(err, ErrInvalidSchema) {
+			t.Errorf("Compile(%s) error = %v, want ErrInvalidSchema", data, err)
+		}
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		instance string
+		want     []Violation
+	}{
+		{
+			name:     "type",
+			schema:   `{"type": "object"}`,
+			instance: `"x"`,
+			want:     []Violation{{"", "type", "/type", "String value found, but an object is required"}},
+		},
+		{
+			name:     "multiple types",
+			schema:   `{"type": ["string", "array"]}`,
+			instance: `1`,
+			want:     []Violation{{"", "type", "/type", "Integer value found, but a string or an array is required"}},
+		},
+		{
+			name:     "integer is a number",
+			schema:   `{"type": "number", "minimum": 1}`,
+			instance: `2`,
+		},
+		{
+			name:     "integer",
+			schema:   `{"type": "integer"}`,
+			instance: `1.5`,
+			want:     []Violation{{"", "type", "/type", "Number value found, but an integer is required"}},
+		},
+		{
+			name:     "enum",
+			schema:   `{"enum": ["dev", "stable"]}`,
+			instance: `"beta"`,
+			want:     []Violation{{"", "enum", "/enum", `Does not have a value in the enumeration ["dev","stable"]`}},
+		},
+		{
+			name:     "string constraints",
+			schema:   `{"minLength": 3, "maxLength": 1, "pattern": "^[a-z]+$"}`,
+			instance: `"A1"`,
+			want: []Violation{
+				{"", "minLength", "/minLength", "Must be at least 3 characters long"},
+				{"", "maxLength", "/maxLength", "Must be at most 1 characters long"},
+				{"", "pattern", "/pattern", "Does not match the regex pattern ^[a-z]+$"},
+			},
+		},
+		{
+			name:     "formats",
+			schema:   `{"items": [{"format": "email"}, {"format": "uri"}, {"format": "date-time"}, {"format": "uri"}]}`,
+			instance: `["not an email", "example.com", "2024-01-01", "irc://irc.libera.chat/composer"]`,
+			want: []Violation{
+				{"/0", "format", "/items/0/format", "Invalid email"},
+				{"/1", "format", "/items/1/format", "Invalid URL format"},
+				{"/2", "format", "/items/2/format", `Invalid date-time "2024-01-01", expected format YYYY-MM-DDThh:mm:ssZ or YYYY-MM-DDThh:mm:ss+hh:mm`},
+			},
+		},
+		{
+			name:     "numbers",
+			schema:   `{"items": {"minimum": 10, "maximum": 20, "exclusiveMaximum": true, "multipleOf": 5}}`,
+			instance: `[5, 20, 12]`,
+			want: []Violation{
+				{"/0", "minimum", "/items/minimum", "Must have a minimum value of 10"},
+				{"/1", "maximum", "/items/maximum", "Must have a maximum value less than 20"},
+				{"/2", "multipleOf", "/items/multipleOf", "Must be a multiple of 5"},
+			},
+		},
+		{
+			name:     "arrays",
+			schema:   `{"minItems": 4, "uniqueItems": true, "items": [{"type": "string"}], "additionalItems": false}`,
+			instance: `["a", 1, 1.0]`,
+			want: []Violation{
+				{"", "minItems", "/minItems", "There must be a minimum of 4 items in the array"},
+				{"", "uniqueItems", "/uniqueItems", "There are no duplicates allowed in the array"},
+				{"/1", "additionalItems", "/additionalItems", "The item 1 is not defined and the definition does not allow additional items"},
+				{"/2", "additionalItems", "/additionalItems", "The item 2 is not defined and the definition does not allow additional items"},
+			},
+		},
+		{
+			name: "objects",
+			schema: `{
+				"required": ["name"],
+				"maxProperties": 2,
+				"properties": {"a/b": {"type": "string"}},
+				"patternProperties": {"^x-": {"type": "boolean"}},
+				"additionalProperties": false
+			}`,
+			instance: `{"a/b": 1, "x-flag": true, "other": null}`,
+			want: []Violation{
+				{"", "maxProperties", "/maxProperties", "Must contain no more than 2 properties"},
+				{"/name", "required", "/required", "The property name is required"},
+				{"/a~1b", "type", "/properties/a~1b/type", "Integer value found, but a string is required"},
+				{"/other", "additionalProperties", "/additionalProperties", "The property other is not defined and the definition does not allow additional properties"},
+			},
+		},
+		{
+			name:     "additionalProperties schema",
+			schema:   `{"additionalProperties": {"type": "string"}}`,
+			instance: `{"psr/log": "^3.0", "acme/lib": []}`,
+			want:     []Violation{{"/acme~1lib", "type", "/additionalProperties/type", "Array value found, but a string is required"}},
+		},
+		{
+			name:     "dependencies",
+			schema:   `{"dependencies": {"a": ["b"], "c": {"required": ["d"]}}}`,
+			instance: `{"a": 1, "c": 2}`,
+			want: []Violation{
+				{"/b", "dependencies", "/dependencies", "a depends on b, which is missing"},
+				{"/d", "required", "/dependencies/c/required", "The property d is required"},
+			},
+		},
+		{
+			name:     "combinators",
+			schema:   `{"items": [{"anyOf": [{"type": "string"}, {"type": "boolean"}]}, {"oneOf": [{"type": "number"}, {"type": "integer"}]}, {"not": {"type": "null"}}, {"allOf": [{"minimum": 1}]}]}`,
+			instance: `[1, 2, null, 0]`,
+			want: []Violation{
+				{"/0", "anyOf", "/items/0/anyOf", "Failed to match at least one schema"},
+				{"/1", "oneOf", "/items/1/oneOf", "Failed to match exactly one schema"},
+				{"/2", "not", "/items/2/not", "Matched a schema which it should not"},
+				{"/3", "minimum", "/items/3/allOf/0/minimum", "Must have a minimum value of 1"},
+			},
+		},
+		{
+			name:     "ref",
+			schema:   `{"properties": {"authors": {"$ref": "#/definitions/authors", "type": "string"}}, "definitions": {"authors": {"type": "array"}}}`,
+			instance: `{"authors": {}}`,
+			want:     []Violation{{"/authors", "type", "/definitions/authors/type", "Object value found, but an array is required"}},
+		},
+		{
+			name:     "recursive ref",
+			schema:   `{"type": "object", "additionalProperties": {"$ref": "#"}}`,
+			instance: `{"a": {"b": {"c": 1}}}`,
+			want:     []Violation{{"/a/b/c", "type", "/type", "Integer value found, but an object is required"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Compile([]byte(tt.schema))
+			if err != nil {
+				t.Fatalf("Compile() returned unexpected error: %v", err)
+			}
+			got := s.Validate(decode(t, tt.instance))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Validate() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Validate()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSchema_Validate_NumberTypes(t *testing.T) {
+	s, err := Compile([]byte(`{"additionalProperties": {"type": "integer", "minimum": 1}}`))
+	if err != nil {
+		t.Fatalf("Compile() returned unexpected error: %v", err)
+	}
+	instance := map[string]interface{}{
+		"float":  float64(2),
+		"number": json.Number("3"),
+		"int":    4,
+		"int64":  int64(0),
+	}
+	got := s.Validate(instance)
+	if len(got) != 1 || got[0].Pointer != "/int64" || got[0].Keyword != "minimum" {
+		t.Errorf("Validate() = %v, want a single minimum violation at /int64", got)
+	}
+}
+
+func TestViolation_String(t *testing.T) {
+	v := Violation{Pointer: "/name", Keyword: "required", Message: "The property name is required"}
+	if got := v.String(); got != "/name: The property name is required" {
+		t.Errorf("String() = %q", got)
+	}
+	v.Pointer = ""
+	if got := v.String(); got != "The property name is required" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestSchema_Validate_KeywordNamedProperties(t *testing.T) {
+	s, err := Compile([]byte(`{
+		"properties": {
+			"default": {"type": "string", "pattern": "^a"},
+			"enum": {"type": "object", "patternProperties": {"^x-": {"type": "boolean"}}}
+		},
+		"definitions": {"default": {"pattern": "^b"}}
+	}`))
+	if err != nil {
+		t.Fatalf("Compile() returned unexpected error: %v", err)
+	}
+	got := s.Validate(decode(t, `{"default": "b", "enum": {"x-flag": 1}}`))
+	want := []Violation{
+		{"/default", "pattern", "/properties/default/pattern", "Does not match the regex pattern ^a"},
+		{"/enum/x-flag", "type", "/properties/enum/patternProperties/^x-/type", "Integer value found, but a boolean is required"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Validate() = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("Validate()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := Compile([]byte(`{"properties": {"default": {"pattern": "("}}}`)); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("Compile() with an invalid pattern under a property named default error = %v, want ErrInvalidSchema", err)
+	}
+}
+
+func TestSchema_Validate_UncompiledPattern(t *testing.T) {
+	// 没有经过prepare的正则表达式现场编译，无法编译时报告违规而不是panic
+	s := &Schema{
+		root:     decode(t, `{"items": [{"pattern": "^a"}, {"pattern": "("}, {"patternProperties": {"(": {}}}]}`),
+		patterns: map[string]*regexp.Regexp{},
+	}
+	got := s.Validate(decode(t, `["b", "c", {"d": 1}]`))
+	want := []Violation{
+		{"/0", "pattern", "/items/0/pattern", "Does not match the regex pattern ^a"},
+		{"/1", "pattern", "/items/1/pattern", "Invalid regex pattern ("},
+		{"/2/d", "patternProperties", "/items/2/patternProperties", "Invalid regex pattern ("},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Validate() = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("Validate()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
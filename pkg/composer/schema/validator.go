@@ -0,0 +1,466 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/parser"
+)
+
+// validator 收集一次校验中发现的违规
+type validator struct {
+	schema     *Schema
+	violations []Violation
+}
+
+// report 记录一处违规
+func (v *validator) report(pointer, schemaPointer, keyword, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{
+		Pointer:       pointer,
+		Keyword:       keyword,
+		SchemaPointer: schemaPointer + "/" + keyword,
+		Message:       fmt.Sprintf(format, args...),
+	})
+}
+
+// valid 判断实例是否满足子Schema，不记录违规
+func (v *validator) valid(instance, schema interface{}, pointer, schemaPointer string) bool {
+	sub := &validator{schema: v.schema}
+	sub.validate(instance, schema, pointer, schemaPointer)
+	return len(sub.violations) == 0
+}
+
+// validate 按schema校验instance，pointer和schemaPointer分别是两者的JSON指针
+func (v *validator) validate(instance, schema interface{}, pointer, schemaPointer string) {
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	// draft-04中$ref会忽略同级的其他关键字
+	if ref, ok := s["$ref"].(string); ok {
+		target, targetPointer, _ := v.schema.resolve(ref)
+		v.validate(instance, target, pointer, targetPointer)
+		return
+	}
+
+	if types, ok := s["type"]; ok && !v.checkType(instance, types, pointer, schemaPointer) {
+		return
+	}
+	if enum, ok := s["enum"].([]interface{}); ok {
+		v.checkEnum(instance, enum, pointer, schemaPointer)
+	}
+	v.checkCombinators(instance, s, pointer, schemaPointer)
+
+	switch value := instance.(type) {
+	case string:
+		v.checkString(value, s, pointer, schemaPointer)
+	case []interface{}:
+		v.checkArray(value, s, pointer, schemaPointer)
+	case map[string]interface{}:
+		v.checkObject(value, s, pointer, schemaPointer)
+	default:
+		if n, ok := toNumber(instance); ok {
+			v.checkNumber(n, s, pointer, schemaPointer)
+		}
+	}
+}
+
+// checkType 校验type关键字，返回实例的类型是否符合
+func (v *validator) checkType(instance, types interface{}, pointer, schemaPointer string) bool {
+	var names []string
+	switch t := types.(type) {
+	case string:
+		names = []string{t}
+	case []interface{}:
+		for _, name := range t {
+			if s, ok := name.(string); ok {
+				names = append(names, s)
+			}
+		}
+	}
+
+	actual := typeOf(instance)
+	for _, name := range names {
+		if name == actual || (name == "number" && actual == "integer") || name == "any" {
+			return true
+		}
+	}
+
+	wording := make([]string, 0, len(names))
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		article := "a"
+		if strings.ContainsRune("aeiou", rune(name[0])) {
+			article = "an"
+		}
+		if w := article + " " + name; !contains(wording, w) {
+			wording = append(wording, w)
+		}
+	}
+	found := strings.ToUpper(actual[:1]) + actual[1:]
+	v.report(pointer, schemaPointer, "type", "%s value found, but %s is required", found, joinOr(wording))
+	return false
+}
+
+// checkEnum 校验enum关键字
+func (v *validator) checkEnum(instance interface{}, enum []interface{}, pointer, schemaPointer string) {
+	for _, allowed := range enum {
+		if equal(instance, allowed) {
+			return
+		}
+	}
+	data, _ := json.Marshal(enum)
+	v.report(pointer, schemaPointer, "enum", "Does not have a value in the enumeration %s", data)
+}
+
+// checkCombinators 校验allOf、anyOf、oneOf和not关键字
+func (v *validator) checkCombinators(instance interface{}, s map[string]interface{}, pointer, schemaPointer string) {
+	if schemas, ok := s["allOf"].([]interface{}); ok {
+		for i, sub := range schemas {
+			v.validate(instance, sub, pointer, fmt.Sprintf("%s/allOf/%d", schemaPointer, i))
+		}
+	}
+
+	if schemas, ok := s["anyOf"].([]interface{}); ok {
+		matched := false
+		for i, sub := range schemas {
+			if v.valid(instance, sub, pointer, fmt.Sprintf("%s/anyOf/%d", schemaPointer, i)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.report(pointer, schemaPointer, "anyOf", "Failed to match at least one schema")
+		}
+	}
+
+	if schemas, ok := s["oneOf"].([]interface{}); ok {
+		matched := 0
+		for i, sub := range schemas {
+			if v.valid(instance, sub, pointer, fmt.Sprintf("%s/oneOf/%d", schemaPointer, i)) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			v.report(pointer, schemaPointer, "oneOf", "Failed to match exactly one schema")
+		}
+	}
+
+	if sub, ok := s["not"]; ok && v.valid(instance, sub, pointer, schemaPointer+"/not") {
+		v.report(pointer, schemaPointer, "not", "Matched a schema which it should not")
+	}
+}
+
+// regexp 返回keyword中的正则表达式，无法编译时报告违规并返回false
+func (v *validator) regexp(pattern, pointer, schemaPointer, keyword string) (*regexp.Regexp, bool) {
+	re, err := v.schema.pattern(pattern)
+	if err != nil {
+		v.report(pointer, schemaPointer, keyword, "Invalid regex pattern %s", pattern)
+		return nil, false
+	}
+	return re, true
+}
+
+// checkString 校验字符串的minLength、maxLength、pattern和format关键字
+func (v *validator) checkString(value string, s map[string]interface{}, pointer, schemaPointer string) {
+	length := utf8.RuneCountInString(value)
+	if min, ok := toNumber(s["minLength"]); ok && float64(length) < min {
+		v.report(pointer, schemaPointer, "minLength", "Must be at least %s characters long", formatNumber(min))
+	}
+	if max, ok := toNumber(s["maxLength"]); ok && float64(length) > max {
+		v.report(pointer, schemaPointer, "maxLength", "Must be at most %s characters long", formatNumber(max))
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		if re, ok := v.regexp(pattern, pointer, schemaPointer, "pattern"); ok && !re.MatchString(value) {
+			v.report(pointer, schemaPointer, "pattern", "Does not match the regex pattern %s", pattern)
+		}
+	}
+	if format, ok := s["format"].(string); ok {
+		if message := checkFormat(format, value); message != "" {
+			v.report(pointer, schemaPointer, "format", "%s", message)
+		}
+	}
+}
+
+// checkFormat 校验format关键字，返回问题说明；不认识的格式总是有效
+func checkFormat(format, value string) string {
+	switch format {
+	case "email":
+		if addr, err := mail.ParseAddress(value); err != nil || addr.Address != value {
+			return "Invalid email"
+		}
+	case "uri":
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+			return "Invalid URL format"
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Sprintf("Invalid date-time %q, expected format YYYY-MM-DDThh:mm:ssZ or YYYY-MM-DDThh:mm:ss+hh:mm", value)
+		}
+	}
+	return ""
+}
+
+// checkNumber 校验数值的minimum、maximum和multipleOf关键字
+func (v *validator) checkNumber(value float64, s map[string]interface{}, pointer, schemaPointer string) {
+	if min, ok := toNumber(s["minimum"]); ok {
+		if exclusive, _ := s["exclusiveMinimum"].(bool); exclusive && value <= min {
+			v.report(pointer, schemaPointer, "minimum", "Must have a minimum value greater than %s", formatNumber(min))
+		} else if value < min {
+			v.report(pointer, schemaPointer, "minimum", "Must have a minimum value of %s", formatNumber(min))
+		}
+	}
+	if max, ok := toNumber(s["maximum"]); ok {
+		if exclusive, _ := s["exclusiveMaximum"].(bool); exclusive && value >= max {
+			v.report(pointer, schemaPointer, "maximum", "Must have a maximum value less than %s", formatNumber(max))
+		} else if value > max {
+			v.report(pointer, schemaPointer, "maximum", "Must have a maximum value of %s", formatNumber(max))
+		}
+	}
+	if divisor, ok := toNumber(s["multipleOf"]); ok && divisor > 0 {
+		if q := value / divisor; math.Abs(q-math.Round(q)) > 1e-9 {
+			v.report(pointer, schemaPointer, "multipleOf", "Must be a multiple of %s", formatNumber(divisor))
+		}
+	}
+}
+
+// checkArray 校验数组的minItems、maxItems、uniqueItems、items和additionalItems关键字
+func (v *validator) checkArray(value []interface{}, s map[string]interface{}, pointer, schemaPointer string) {
+	if min, ok := toNumber(s["minItems"]); ok && float64(len(value)) < min {
+		v.report(pointer, schemaPointer, "minItems", "There must be a minimum of %s items in the array", formatNumber(min))
+	}
+	if max, ok := toNumber(s["maxItems"]); ok && float64(len(value)) > max {
+		v.report(pointer, schemaPointer, "maxItems", "There must be a maximum of %s items in the array", formatNumber(max))
+	}
+	if unique, _ := s["uniqueItems"].(bool); unique && hasDuplicates(value) {
+		v.report(pointer, schemaPointer, "uniqueItems", "There are no duplicates allowed in the array")
+	}
+
+	switch items := s["items"].(type) {
+	case map[string]interface{}:
+		for i, item := range value {
+			v.validate(item, items, fmt.Sprintf("%s/%d", pointer, i), schemaPointer+"/items")
+		}
+	case []interface{}:
+		for i, item := range value {
+			itemPointer := fmt.Sprintf("%s/%d", pointer, i)
+			if i < len(items) {
+				v.validate(item, items[i], itemPointer, fmt.Sprintf("%s/items/%d", schemaPointer, i))
+				continue
+			}
+			switch additional := s["additionalItems"].(type) {
+			case bool:
+				if !additional {
+					v.report(itemPointer, schemaPointer, "additionalItems",
+						"The item %d is not defined and the definition does not allow additional items", i)
+				}
+			case map[string]interface{}:
+				v.validate(item, additional, itemPointer, schemaPointer+"/additionalItems")
+			}
+		}
+	}
+}
+
+// checkObject 校验对象的minProperties、maxProperties、required、dependencies、
+// properties、patternProperties和additionalProperties关键字
+func (v *validator) checkObject(value map[string]interface{}, s map[string]interface{}, pointer, schemaPointer string) {
+	if min, ok := toNumber(s["minProperties"]); ok && float64(len(value)) < min {
+		v.report(pointer, schemaPointer, "minProperties", "Must contain a minimum of %s properties", formatNumber(min))
+	}
+	if max, ok := toNumber(s["maxProperties"]); ok && float64(len(value)) > max {
+		v.report(pointer, schemaPointer, "maxProperties", "Must contain no more than %s properties", formatNumber(max))
+	}
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				if _, present := value[name]; !present {
+					v.report(pointer+"/"+parser.EscapePointer(name), schemaPointer, "required", "The property %s is required", name)
+				}
+			}
+		}
+	}
+
+	properties, _ := s["properties"].(map[string]interface{})
+	patternProperties, _ := s["patternProperties"].(map[string]interface{})
+	dependencies, _ := s["dependencies"].(map[string]interface{})
+
+	for _, name := range sortedKeys(value) {
+		child := value[name]
+		childPointer := pointer + "/" + parser.EscapePointer(name)
+
+		if dependency, ok := dependencies[name]; ok {
+			v.checkDependency(value, name, dependency, pointer, schemaPointer)
+		}
+
+		matched := false
+		if sub, ok := properties[name]; ok {
+			matched = true
+			v.validate(child, sub, childPointer, schemaPointer+"/properties/"+parser.EscapePointer(name))
+		}
+		for _, pattern := range sortedKeys(patternProperties) {
+			if re, ok := v.regexp(pattern, childPointer, schemaPointer, "patternProperties"); ok && re.MatchString(name) {
+				matched = true
+				v.validate(child, patternProperties[pattern], childPointer, schemaPointer+"/patternProperties/"+parser.EscapePointer(pattern))
+			}
+		}
+		if matched {
+			continue
+		}
+
+		switch additional := s["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.report(childPointer, schemaPointer, "additionalProperties",
+					"The property %s is not defined and the definition does not allow additional properties", name)
+			}
+		case map[string]interface{}:
+			v.validate(child, additional, childPointer, schemaPointer+"/additionalProperties")
+		}
+	}
+}
+
+// checkDependency 校验dependencies关键字中name属性的依赖，依赖可以是属性列表或Schema
+func (v *validator) checkDependency(value map[string]interface{}, name string, dependency interface{}, pointer, schemaPointer string) {
+	switch d := dependency.(type) {
+	case []interface{}:
+		for _, other := range d {
+			if other, ok := other.(string); ok {
+				if _, present := value[other]; !present {
+					v.report(pointer+"/"+parser.EscapePointer(other), schemaPointer, "dependencies", "%s depends on %s, which is missing", name, other)
+				}
+			}
+		}
+	case map[string]interface{}:
+		v.validate(value, d, pointer, schemaPointer+"/dependencies/"+parser.EscapePointer(name))
+	}
+}
+
+// typeOf 返回实例的JSON Schema类型，整数值返回"integer"
+func typeOf(instance interface{}) string {
+	switch instance.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	if n, ok := toNumber(instance); ok {
+		if n == math.Trunc(n) && !math.IsInf(n, 0) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", instance)
+}
+
+// toNumber 把数字转换为float64
+func toNumber(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// formatNumber 不使用科学计数法格式化数字
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// equal 按JSON的语义比较两个值，数字按数值比较
+func equal(a, b interface{}) bool {
+	if x, ok := toNumber(a); ok {
+		y, ok := toNumber(b)
+		return ok && x == y
+	}
+	switch x := a.(type) {
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			other, ok := y[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// hasDuplicates 判断数组中是否有相等的元素
+func hasDuplicates(items []interface{}) bool {
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			if equal(items[i], items[j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sortedKeys 返回排序后的对象键
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// contains 判断字符串切片中是否包含s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// joinOr 把["a string", "an array"]连接为"a string or an array"
+func joinOr(words []string) string {
+	if len(words) <= 1 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " or " + words[len(words)-1]
+}
@@ -0,0 +1,62 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/schema"
+)
+
+func TestComposerJSON_ValidateSchema(t *testing.T) {
+	c, err := ParseString(`{
+    "name": "acme/app",
+    "description": "An example application",
+    "require": {
+        "psr/log": "^3.0"
+    },
+    "autoload": {
+        "psr-4": {"Acme\\": "src/"}
+    }
+}`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	violations, err := c.ValidateSchema(schema.Options{})
+	if err != nil {
+		t.Fatalf("ValidateSchema() returned unexpected error: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("ValidateSchema() = %v, want no violations", violations)
+	}
+
+	c.Description = ""
+	c.Name = "Acme/App"
+	violations, err = c.ValidateSchema(schema.Options{Lax: true})
+	if err != nil {
+		t.Fatalf("ValidateSchema() returned unexpected error: %v", err)
+	}
+	if len(violations) != 1 || violations[0].Pointer != "/name" || violations[0].Keyword != "pattern" {
+		t.Errorf("ValidateSchema() = %v, want a single pattern violation at /name", violations)
+	}
+}
+
+func TestValidateSchemaFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "composer.json")
+	if err := os.WriteFile(path, []byte(`{"name": "acme/app", "description": "App", "autoload": {"psr-4": "src/"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	violations, err := ValidateSchemaFile(path, schema.Options{})
+	if err != nil {
+		t.Fatalf("ValidateSchemaFile() returned unexpected error: %v", err)
+	}
+	if len(violations) != 1 || violations[0].Pointer != "/autoload/psr-4" || violations[0].Keyword != "type" {
+		t.Errorf("ValidateSchemaFile() = %v, want a single type violation at /autoload/psr-4", violations)
+	}
+
+	if _, err := ValidateSchemaFile(filepath.Join(t.TempDir(), "missing.json"), schema.Options{}); err == nil {
+		t.Error("ValidateSchemaFile() with a missing file expected error, got nil")
+	}
+}
@@ -0,0 +1,173 @@
+package spdx
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Proprietary 是Composer接受的表示闭源软件的许可证，它不是SPDX标识符
+const Proprietary = "proprietary"
+
+// Operator 是连接两个表达式的运算符
+type Operator string
+
+// 运算符
+const (
+	// And 要求同时遵守两个许可证
+	And Operator = "AND"
+
+	// Or 可以选择遵守其中一个许可证
+	Or Operator = "OR"
+)
+
+// Expression 是解析后的许可证表达式，是*Simple、*With或*Compound之一
+type Expression interface {
+	// String 返回规范的写法：标识符使用许可证列表中的大小写，运算符大写，只在需要时加括号
+	String() string
+
+	// Licenses 返回表达式中的所有许可证，按出现的顺序排列
+	Licenses() []*Simple
+}
+
+// Simple 是单个许可证，如"MIT"、"GPL-2.0+"、"LicenseRef-Acme"、"proprietary"
+type Simple struct {
+	// License 许可证，列表中的标识符使用列表中的写法；LicenseRef引用、"proprietary"、
+	// "NONE"和"NOASSERTION"不在列表中，Deprecated为false
+	License
+
+	// OrLater 标识符带有"+"后缀，表示该版本或更高的版本
+	OrLater bool
+}
+
+// String 实现Expression接口
+func (s *Simple) String() string {
+	if s.OrLater {
+		return s.ID + "+"
+	}
+	return s.ID
+}
+
+// Licenses 实现Expression接口
+func (s *Simple) Licenses() []*Simple {
+	return []*Simple{s}
+}
+
+// IsRef 判断是否是LicenseRef-或DocumentRef-引用
+func (s *Simple) IsRef() bool {
+	return licenseRefRegex.MatchString(s.ID)
+}
+
+// IsProprietary 判断是否是Composer的"proprietary"
+func (s *Simple) IsProprietary() bool {
+	return s.ID == Proprietary
+}
+
+// With 是带例外的许可证，如"GPL-2.0-or-later WITH Classpath-exception-2.0"
+type With struct {
+	// License 许可证
+	License *Simple
+
+	// Exception 例外
+	Exception License
+}
+
+// String 实现Expression接口
+func (w *With) String() string {
+	return w.License.String() + " WITH " + w.Exception.ID
+}
+
+// Licenses 实现Expression接口
+func (w *With) Licenses() []*Simple {
+	return []*Simple{w.License}
+}
+
+// Compound 是用AND或OR连接的两个表达式
+type Compound struct {
+	// Operator 运算符
+	Operator Operator
+
+	// Left 和 Right 是运算符两侧的表达式
+	Left, Right Expression
+}
+
+// String 实现Expression接口，AND中的OR表达式加括号
+func (c *Compound) String() string {
+	return c.operand(c.Left) + " " + string(c.Operator) + " " + c.operand(c.Right)
+}
+
+// operand 格式化运算符一侧的表达式
+func (c *Compound) operand(e Expression) string {
+	if inner, ok := e.(*Compound); ok && c.Operator == And && inner.Operator == Or {
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
+
+// Licenses 实现Expression接口
+func (c *Compound) Licenses() []*Simple {
+	return append(c.Left.Licenses(), c.Right.Licenses()...)
+}
+
+// Alternatives 把顶层用OR连接的表达式拆分为可选的各项，如"MIT OR (Apache-2.0 AND ISC)"拆分为"MIT"和
+// "Apache-2.0 AND ISC"；顶层不是OR时返回只包含e的切片
+func Alternatives(e Expression) []Expression {
+	if c, ok := e.(*Compound); ok && c.Operator == Or {
+		return append(Alternatives(c.Left), Alternatives(c.Right)...)
+	}
+	return []Expression{e}
+}
+
+// deprecatedGNURegex 匹配已废弃的GNU许可证标识符，如"GPL-2.0"、"LGPL-2.1+"
+var deprecatedGNURegex = regexp.MustCompile(`^([AL]?GPL-[123](?:\.[01])?)(\+?)$`)
+
+// Deprecation 是表达式中已废弃的许可证或例外
+type Deprecation struct {
+	// ID 已废弃的标识符，如"GPL-2.0"
+	ID string
+
+	// Replacements 建议替换为的标识符，如["GPL-2.0-only", "GPL-2.0-or-later"]；没有明确替代时为空
+	Replacements []string
+}
+
+// String 返回说明，如`"GPL-2.0" is a deprecated SPDX license identifier, use "GPL-2.0-only" or "GPL-2.0-or-later" instead`
+func (d Deprecation) String() string {
+	if len(d.Replacements) == 0 {
+		return `"` + d.ID + `" is a deprecated SPDX license identifier, see https://spdx.org/licenses/`
+	}
+	return `"` + d.ID + `" is a deprecated SPDX license identifier, use "` + strings.Join(d.Replacements, `" or "`) + `" instead`
+}
+
+// Deprecations 返回表达式中已被SPDX废弃的许可证和例外，按出现的顺序排列
+//
+// 示例:
+//
+//	expr, _ := spdx.Parse("GPL-2.0 OR MIT")
+//	for _, d := range spdx.Deprecations(expr) {
+//		fmt.Println(d) // "GPL-2.0" is a deprecated SPDX license identifier, use "GPL-2.0-only" or "GPL-2.0-or-later" instead
+//	}
+func Deprecations(e Expression) []Deprecation {
+	switch e := e.(type) {
+	case *Simple:
+		if !e.Deprecated {
+			return nil
+		}
+		d := Deprecation{ID: e.String()}
+		if m := deprecatedGNURegex.FindStringSubmatch(d.ID); m != nil {
+			if m[2] == "+" || e.OrLater {
+				d.Replacements = []string{m[1] + "-or-later"}
+			} else {
+				d.Replacements = []string{m[1] + "-only", m[1] + "-or-later"}
+			}
+		}
+		return []Deprecation{d}
+	case *With:
+		deprecations := Deprecations(e.License)
+		if e.Exception.Deprecated {
+			deprecations = append(deprecations, Deprecation{ID: e.Exception.ID})
+		}
+		return deprecations
+	case *Compound:
+		return append(Deprecations(e.Left), Deprecations(e.Right)...)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package spdx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+		licenses   []string
+	}{
+		{"MIT", "MIT", []string{"MIT"}},
+		{"mit", "MIT", []string{"MIT"}},
+		{"none", "NONE", []string{"NONE"}},
+		{"proprietary", "proprietary", []string{"proprietary"}},
+		{"GPL-2.0+", "GPL-2.0+", []string{"GPL-2.0+"}},
+		{"GPL-3.0-only+", "GPL-3.0-only+", []string{"GPL-3.0-only"}},
+		{"(MIT or GPL-3.0-or-later)", "MIT OR GPL-3.0-or-later", []string{"MIT", "GPL-3.0-or-later"}},
+		{"MIT OR Apache-2.0 AND ISC", "MIT OR Apache-2.0 AND ISC", []string{"MIT", "Apache-2.0", "ISC"}},
+		{"(MIT OR Apache-2.0) AND ISC", "(MIT OR Apache-2.0) AND ISC", []string{"MIT", "Apache-2.0", "ISC"}},
+		{"ISC and (MIT or proprietary)", "ISC AND (MIT OR proprietary)", []string{"ISC", "MIT", "proprietary"}},
+		{"gpl-2.0-or-later with classpath-exception-2.0", "GPL-2.0-or-later WITH Classpath-exception-2.0", []string{"GPL-2.0-or-later"}},
+		{"LicenseRef-Acme-1.0 OR MIT", "LicenseRef-Acme-1.0 OR MIT", []string{"LicenseRef-Acme-1.0", "MIT"}},
+	}
+	for _, tt := range tests {
+		expr, err := Parse(tt.expression)
+		if err != nil {
+			t.Errorf("Parse(%q) returned unexpected error: %v", tt.expression, err)
+			continue
+		}
+		if got := expr.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.expression, got, tt.want)
+		}
+		var licenses []string
+		for _, l := range expr.Licenses() {
+			licenses = append(licenses, l.ID)
+		}
+		if !reflect.DeepEqual(licenses, tt.licenses) {
+			t.Errorf("Parse(%q).Licenses() = %v, want %v", tt.expression, licenses, tt.licenses)
+		}
+	}
+
+	for _, expression := range []string{"", "MIT-2", "Proprietary", "MIT OR", "(MIT", "MIT WITH MIT"} {
+		if _, err := Parse(expression); !errors.Is(err, ErrInvalidExpression) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidExpression", expression, err)
+		}
+	}
+}
+
+func TestParse_Tree(t *testing.T) {
+	expr, err := Parse("MIT AND ISC OR Apache-2.0 WITH LLVM-exception")
+	if err != nil {
+		t.Fatalf("Parse() returned unexpected error: %v", err)
+	}
+	or, ok := expr.(*Compound)
+	if !ok || or.Operator != Or {
+		t.Fatalf("Parse() = %#v, want an OR compound", expr)
+	}
+	if and, ok := or.Left.(*Compound); !ok || and.Operator != And {
+		t.Errorf("Left = %#v, want an AND compound", or.Left)
+	}
+	with, ok := or.Right.(*With)
+	if !ok || with.License.ID != "Apache-2.0" || with.Exception.ID != "LLVM-exception" {
+		t.Errorf("Right = %#v, want Apache-2.0 WITH LLVM-exception", or.Right)
+	}
+
+	ref, _ := Parse("LicenseRef-Acme")
+	if s := ref.(*Simple); !s.IsRef() || s.IsProprietary() {
+		t.Errorf("IsRef() = %v, IsProprietary() = %v", s.IsRef(), s.IsProprietary())
+	}
+}
+
+func TestAlternatives(t *testing.T) {
+	expr, err := Parse("MIT OR (Apache-2.0 AND ISC) OR GPL-3.0-only")
+	if err != nil {
+		t.Fatalf("Parse() returned unexpected error: %v", err)
+	}
+	var got []string
+	for _, e := range Alternatives(expr) {
+		got = append(got, e.String())
+	}
+	if want := []string{"MIT", "Apache-2.0 AND ISC", "GPL-3.0-only"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Alternatives() = %v, want %v", got, want)
+	}
+
+	and, _ := Parse("(MIT OR ISC) AND Apache-2.0")
+	if got := Alternatives(and); len(got) != 1 || got[0] != and {
+		t.Errorf("Alternatives(%s) = %v, want the expression itself", and, got)
+	}
+}
+
+func TestDeprecations(t *testing.T) {
+	expr, err := Parse("GPL-2.0 OR LGPL-2.1+ OR MIT OR eCos-2.0 OR GPL-2.0-only WITH Nokia-Qt-exception-1.1")
+	if err != nil {
+		t.Fatalf("Parse() returned unexpected error: %v", err)
+	}
+	var got []string
+	for _, d := range Deprecations(expr) {
+		got = append(got, d.String())
+	}
+	want := []string{
+		`"GPL-2.0" is a deprecated SPDX license identifier, use "GPL-2.0-only" or "GPL-2.0-or-later" instead`,
+		`"LGPL-2.1+" is a deprecated SPDX license identifier, use "LGPL-2.1-or-later" instead`,
+		`"eCos-2.0" is a deprecated SPDX license identifier, see https://spdx.org/licenses/`,
+		`"Nokia-Qt-exception-1.1" is a deprecated SPDX license identifier, see https://spdx.org/licenses/`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Deprecations() = %v, want %v", got, want)
+	}
+
+	current, _ := Parse("MIT OR Apache-2.0")
+	if got := Deprecations(current); got != nil {
+		t.Errorf("Deprecations(%s) = %v, want nil", current, got)
+	}
+}
@@ -0,0 +1,316 @@
+// Package spdx 提供SPDX许可证表达式的校验
+//
+// composer.json的license字段使用SPDX许可证标识符，如"MIT"、"GPL-3.0-or-later"，
+// 也可以是"(MIT or GPL-3.0-or-later)"、"Apache-2.0 WITH LLVM-exception"这样的表达式。
+// 本包内嵌SPDX许可证列表和例外列表，校验规则与Composer使用的composer/spdx-licenses一致：
+// - 许可证标识符、例外标识符和AND、OR、WITH运算符都不区分大小写
+// - 支持"+"后缀、LicenseRef-和DocumentRef-引用、括号以及NONE和NOASSERTION
+// - 已废弃的标识符（如"GPL-2.0"）仍然有效，可以通过Lookup或Deprecations判断
+//
+// Validate只校验表达式；Parse把表达式解析为Expression，可以遍历其中的许可证、按OR拆分或格式化为规范的写法。
+package spdx
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidExpression 表示许可证表达式不符合SPDX语法或使用了未知的标识符
+var ErrInvalidExpression = errors.New("invalid SPDX license expression")
+
+//go:embed licenses.json
+var listData []byte
+
+// License 是SPDX许可证列表或例外列表中的一项
+type License struct {
+	// ID 列表中的标识符，如"GPL-2.0-or-later"
+	ID string
+
+	// Deprecated 标识符是否已被SPDX废弃，如"GPL-2.0"应改为"GPL-2.0-only"或"GPL-2.0-or-later"
+	Deprecated bool
+}
+
+// licenses 和 exceptions 是内嵌的许可证列表和例外列表，key为小写的标识符
+var licenses, exceptions = loadList()
+
+// loadList 解析内嵌的列表
+func loadList() (map[string]License, map[string]License) {
+	var raw struct {
+		Licenses   map[string]bool `json:"licenses"`
+		Exceptions map[string]bool `json:"exceptions"`
+	}
+	if err := json.Unmarshal(listData, &raw); err != nil {
+		panic("spdx: invalid embedded license list: " + err.Error())
+	}
+
+	index := func(m map[string]bool) map[string]License {
+		result := make(map[string]License, len(m))
+		for id, deprecated := range m {
+			result[strings.ToLower(id)] = License{ID: id, Deprecated: deprecated}
+		}
+		return result
+	}
+	return index(raw.Licenses), index(raw.Exceptions)
+}
+
+// Lookup 按标识符（不区分大小写）查找许可证
+//
+// 示例:
+//
+//	l, ok := spdx.Lookup("gpl-2.0")
+//	fmt.Println(l.ID, l.Deprecated, ok) // GPL-2.0 true true
+func Lookup(id string) (License, bool) {
+	l, ok := licenses[strings.ToLower(id)]
+	return l, ok
+}
+
+// LookupException 按标识符（不区分大小写）查找许可证例外，如"Classpath-exception-2.0"
+func LookupException(id string) (License, bool) {
+	l, ok := exceptions[strings.ToLower(id)]
+	return l, ok
+}
+
+// Valid 判断许可证表达式是否有效，规则见Validate
+func Valid(expression string) bool {
+	return Validate(expression) == nil
+}
+
+// Validate 校验SPDX许可证表达式
+//
+// 参数:
+//   - expression: 许可证表达式，如"MIT"、"(MIT or GPL-3.0-or-later)"、"GPL-2.0-or-later WITH Classpath-exception-2.0"
+//
+// 返回:
+//   - error: 表达式无效时返回包装了ErrInvalidExpression的错误，说明第一个问题
+//
+// 与Composer一致，表达式首尾不能有空白，运算符两侧必须有空白；"proprietary"不是SPDX标识符，
+// 需要由调用方单独处理，或者使用接受它的Parse。
+//
+// 示例:
+//
+//	spdx.Validate("MIT")                 // nil
+//	spdx.Validate("(MIT or Apache-2.0)") // nil
+//	spdx.Validate("MIT-2")               // invalid SPDX license expression: unknown license identifier "MIT-2"
+func Validate(expression string) error {
+	_, err := parse(expression, false)
+	return err
+}
+
+// Parse 解析SPDX许可证表达式
+//
+// 参数:
+//   - expression: 许可证表达式，如"(MIT or GPL-3.0-or-later)"
+//
+// 返回:
+//   - Expression: 解析后的表达式，AND的优先级高于OR，WITH的优先级最高
+//   - error: 表达式无效时返回包装了ErrInvalidExpression的错误
+//
+// 语法规则与Validate相同，另外与Composer一样接受表示闭源软件的"proprietary"（见Proprietary）。
+//
+// 示例:
+//
+//	expr, err := spdx.Parse("(mit or GPL-3.0-or-later)")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(expr) // MIT OR GPL-3.0-or-later
+//	for _, l := range expr.Licenses() {
+//		fmt.Println(l.ID) // MIT, GPL-3.0-or-later
+//	}
+func Parse(expression string) (Expression, error) {
+	return parse(expression, true)
+}
+
+// parse 解析表达式，proprietary为true时接受"proprietary"
+func parse(expression string, proprietary bool) (Expression, error) {
+	if strings.EqualFold(expression, "NONE") || strings.EqualFold(expression, "NOASSERTION") {
+		return &Simple{License: License{ID: strings.ToUpper(expression)}}, nil
+	}
+	if strings.TrimSpace(expression) != expression {
+		return nil, fmt.Errorf("%w: leading or trailing whitespace", ErrInvalidExpression)
+	}
+
+	p := &parser{tokens: tokenize(expression), proprietary: proprietary}
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := p.peek(); ok {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidExpression, t.text)
+	}
+	return expr, nil
+}
+
+// token 是表达式中的一个词
+type token struct {
+	text string
+
+	// spaceBefore 词前面是否有空白
+	spaceBefore bool
+}
+
+// tokenize 按空白和括号把表达式拆分为词
+func tokenize(s string) []token {
+	var tokens []token
+	space := false
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, token{text: s[start:end], spaceBefore: space})
+			start, space = -1, false
+		}
+	}
+	for i, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+			flush(i)
+			space = true
+		case r == '(' || r == ')':
+			flush(i)
+			tokens = append(tokens, token{text: string(r), spaceBefore: space})
+			space = false
+		case start < 0:
+			start = i
+		}
+	}
+	flush(len(s))
+	return tokens
+}
+
+// licenseRefRegex 匹配[DocumentRef-idstring:]LicenseRef-idstring
+var licenseRefRegex = regexp.MustCompile(`(?i)^(?:DocumentRef-[\pL\pN.-]+:)?LicenseRef-[\pL\pN.-]+$`)
+
+// parser 按SPDX的语法递归下降地解析表达式
+//
+//	or     := and [OR or]
+//	and    := head [AND and]
+//	head   := simple [WITH exception] | "(" or ")"
+//	simple := license-id ["+"] | license-ref
+type parser struct {
+	tokens []token
+	pos    int
+
+	// proprietary 是否接受"proprietary"
+	proprietary bool
+}
+
+// peek 返回下一个词
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// next 返回并跳过下一个词
+func (p *parser) next() (token, error) {
+	t, ok := p.peek()
+	if !ok {
+		return token{}, fmt.Errorf("%w: unexpected end of expression", ErrInvalidExpression)
+	}
+	p.pos++
+	return t, nil
+}
+
+// operator 在下一个词是指定的运算符时跳过它，运算符两侧必须有空白
+func (p *parser) operator(name string) (bool, error) {
+	t, ok := p.peek()
+	if !ok || !strings.EqualFold(t.text, name) {
+		return false, nil
+	}
+	p.pos++
+	if after, ok := p.peek(); !t.spaceBefore || (ok && !after.spaceBefore) {
+		return false, fmt.Errorf("%w: %s must be surrounded by whitespace", ErrInvalidExpression, name)
+	}
+	return true, nil
+}
+
+// or 解析用OR连接的表达式
+func (p *parser) or() (Expression, error) {
+	return p.binary(Or, p.and)
+}
+
+// and 解析用AND连接的表达式
+func (p *parser) and() (Expression, error) {
+	return p.binary(And, p.head)
+}
+
+// binary 解析用op连接的operand
+func (p *parser) binary(op Operator, operand func() (Expression, error)) (Expression, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	ok, err := p.operator(string(op))
+	if err != nil || !ok {
+		return left, err
+	}
+	right, err := p.binary(op, operand)
+	if err != nil {
+		return nil, err
+	}
+	return &Compound{Operator: op, Left: left, Right: right}, nil
+}
+
+// head 解析带可选例外的简单表达式或括号中的表达式
+func (p *parser) head() (Expression, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if t.text == "(" {
+		expr, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t, err := p.next(); err != nil || t.text != ")" {
+			return nil, fmt.Errorf("%w: missing closing parenthesis", ErrInvalidExpression)
+		}
+		return expr, nil
+	}
+
+	license, err := p.simple(t.text)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := p.operator("WITH")
+	if err != nil || !ok {
+		return license, err
+	}
+	t, err = p.next()
+	if err != nil {
+		return nil, err
+	}
+	exception, ok := LookupException(t.text)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown license exception %q", ErrInvalidExpression, t.text)
+	}
+	return &With{License: license, Exception: exception}, nil
+}
+
+// simple 解析许可证标识符或LicenseRef引用
+func (p *parser) simple(s string) (*Simple, error) {
+	if licenseRefRegex.MatchString(s) {
+		return &Simple{License: License{ID: s}}, nil
+	}
+	if p.proprietary && s == Proprietary {
+		return &Simple{License: License{ID: s}}, nil
+	}
+	if l, ok := Lookup(s); ok {
+		return &Simple{License: l}, nil
+	}
+	if id := strings.TrimSuffix(s, "+"); id != s {
+		if l, ok := Lookup(id); ok {
+			return &Simple{License: l, OrLater: true}, nil
+		}
+	}
+	if s == ")" || strings.EqualFold(s, "AND") || strings.EqualFold(s, "OR") || strings.EqualFold(s, "WITH") {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidExpression, s)
+	}
+	return nil, fmt.Errorf("%w: unknown license identifier %q", ErrInvalidExpression, s)
+}
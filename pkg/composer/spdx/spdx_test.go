@@ -0,0 +1,92 @@
+package spdx
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		id         string
+		want       string
+		deprecated bool
+		found      bool
+	}{
+		{"MIT", "MIT", false, true},
+		{"mit", "MIT", false, true},
+		{"GPL-2.0", "GPL-2.0", true, true},
+		{"GPL-2.0+", "GPL-2.0+", true, true},
+		{"gpl-3.0-or-later", "GPL-3.0-or-later", false, true},
+		{"proprietary", "", false, false},
+		{"Classpath-exception-2.0", "", false, false},
+	}
+	for _, tt := range tests {
+		l, ok := Lookup(tt.id)
+		if ok != tt.found || l.ID != tt.want || l.Deprecated != tt.deprecated {
+			t.Errorf("Lookup(%q) = %+v, %v, want %q deprecated=%v found=%v", tt.id, l, ok, tt.want, tt.deprecated, tt.found)
+		}
+	}
+
+	if e, ok := LookupException("classpath-exception-2.0"); !ok || e.ID != "Classpath-exception-2.0" {
+		t.Errorf("LookupException() = %+v, %v", e, ok)
+	}
+	if _, ok := LookupException("MIT"); ok {
+		t.Error("LookupException(MIT) should not find a license")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := []string{
+		"MIT",
+		"mit",
+		"NONE",
+		"NOASSERTION",
+		"GPL-2.0+",
+		"GPL-3.0-only",
+		"LGPL-2.1-or-later",
+		"(MIT or GPL-3.0-or-later)",
+		"(MIT OR Apache-2.0) AND BSD-3-Clause",
+		"( MIT and Apache-2.0 )",
+		"GPL-2.0-or-later WITH Classpath-exception-2.0",
+		"Apache-2.0 with LLVM-exception OR MIT",
+		"((MIT OR ISC) AND (Apache-2.0))",
+		"LicenseRef-Acme-1.0",
+		"DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2",
+	}
+	for _, expr := range valid {
+		if err := Validate(expr); err != nil {
+			t.Errorf("Validate(%q) returned unexpected error: %v", expr, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		" MIT",
+		"MIT ",
+		"MIT-2",
+		"proprietary",
+		"MIT OR",
+		"OR MIT",
+		"MIT AND AND ISC",
+		"MIT ISC",
+		"(MIT OR ISC",
+		"MIT OR ISC)",
+		"()",
+		"MIT WITH",
+		"MIT WITH MIT",
+		"MIT WITH Classpath-exception-2.0 WITH LLVM-exception",
+		"(MIT)AND(ISC)",
+		"MIT AND(ISC)",
+		"LicenseRef-",
+		"LicenseRef-Acme+",
+	}
+	for _, expr := range invalid {
+		err := Validate(expr)
+		if !errors.Is(err, ErrInvalidExpression) {
+			t.Errorf("Validate(%q) = %v, want ErrInvalidExpression", expr, err)
+		}
+		if Valid(expr) {
+			t.Errorf("Valid(%q) = true, want false", expr)
+		}
+	}
+}
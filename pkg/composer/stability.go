@@ -0,0 +1,53 @@
+package composer
+
+import (
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/stability"
+)
+
+// StabilityResolver 根据minimum-stability、prefer-stable以及require和require-dev中的稳定性标志创建稳定性解析器
+//
+// 返回:
+//   - *stability.Resolver: 稳定性解析器
+//   - error: 如果minimum-stability无效，返回错误
+//
+// 示例:
+//
+//	composer, _ := composer.ParseFile("./composer.json")
+//
+//	resolver, err := composer.StabilityResolver()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	// require中为"^2.0@beta"时允许RC版本
+//	ok, _ := resolver.Allows("monolog/monolog", "2.1.0-RC1")
+//	fmt.Println(ok) // 输出: true
+func (c *ComposerJSON) StabilityResolver() (*stability.Resolver, error) {
+	return stability.NewResolver(c.MinimumStability, c.PreferStable, c.Require, c.RequireDev)
+}
+
+// EffectiveStabilities 返回require和require-dev中每个包实际允许的最低稳定性
+//
+// 返回:
+//   - map[string]string: key为包名，value为"stable"、"RC"、"beta"、"alpha"或"dev"
+//   - error: 如果minimum-stability无效，返回错误
+//
+// 示例:
+//
+//	// minimum-stability为"stable"，require为{"monolog/monolog": "^2.0@beta", "psr/log": "^3.0"}
+//	stabilities, _ := composer.EffectiveStabilities()
+//	fmt.Println(stabilities) // 输出: map[monolog/monolog:beta psr/log:stable]
+func (c *ComposerJSON) EffectiveStabilities() (map[string]string, error) {
+	resolver, err := c.StabilityResolver()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(c.Require)+len(c.RequireDev))
+	for _, require := range []map[string]string{c.Require, c.RequireDev} {
+		for name := range require {
+			result[name] = resolver.For(name)
+		}
+	}
+	return result, nil
+}
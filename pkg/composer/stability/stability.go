@@ -0,0 +1,246 @@
+// Package stability 提供Composer稳定性规则的解析功能
+//
+// 本包处理minimum-stability、prefer-stable和依赖约束中的稳定性标志，包括：
+// - 稳定性名称的规范化和比较
+// - 从require约束中提取每个包的稳定性标志，如"^2.0@beta"、"@dev"、"1.0.0-RC1"
+// - 计算每个包实际允许的最低稳定性，并判断候选版本是否被允许
+// - 按prefer-stable规则在候选版本中选择最合适的版本
+//
+// 规则与Composer的RootPackageLoader::extractStabilityFlags和RepositorySet::isPackageAcceptable一致。
+package stability
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/version"
+)
+
+// levels 是各稳定性的级别，数值越大越不稳定，与Composer的BasePackage::STABILITIES一致
+var levels = map[string]int{
+	version.StabilityStable: 0,
+	version.StabilityRC:     5,
+	version.StabilityBeta:   10,
+	version.StabilityAlpha:  15,
+	version.StabilityDev:    20,
+}
+
+var (
+	orSplitRegex = regexp.MustCompile(`\s*\|\|?\s*`)
+	flagRegex    = regexp.MustCompile(`(?i)^[^@]*?@(stable|RC|beta|alpha|dev)$`)
+	aliasRegex   = regexp.MustCompile(`^([^,\s@]+) as .+$`)
+	plainRegex   = regexp.MustCompile(`^[^,\s@]+$`)
+)
+
+// Normalize 规范化稳定性名称，如"rc"变为"RC"、"Beta"变为"beta"
+//
+// 返回:
+//   - string: 规范化后的稳定性
+//   - error: 如果不是dev、alpha、beta、RC、stable之一，返回错误
+func Normalize(s string) (string, error) {
+	normalized := strings.ToLower(s)
+	if normalized == "rc" {
+		normalized = version.StabilityRC
+	}
+	if _, ok := levels[normalized]; !ok {
+		return "", fmt.Errorf("invalid stability '%s', should be one of: dev, alpha, beta, RC, stable", s)
+	}
+	return normalized, nil
+}
+
+// Compare 比较两个规范化后的稳定性，a比b更稳定时返回负数，相同时返回0，更不稳定时返回正数
+func Compare(a, b string) int {
+	return levels[a] - levels[b]
+}
+
+// ExtractFlags 从require约束中提取每个包的稳定性标志
+//
+// 参数:
+//   - require: 依赖映射，key为包名，value为版本约束
+//   - minimumStability: 根包的minimum-stability，已规范化
+//
+// 返回:
+//   - map[string]string: key为小写包名，value为该包的稳定性标志
+//
+// 规则:
+//   - 显式标志（如"^2.0@beta"、"@dev"）总是生效，同一约束中有多个标志时取最不稳定的
+//   - 没有显式标志时，从不稳定的版本推断（如"1.0.0-RC1"、"dev-main"），但只在比minimumStability更不稳定时生效
+//
+// 示例:
+//
+//	flags := stability.ExtractFlags(map[string]string{
+//		"monolog/monolog": "^2.0@beta",
+//		"acme/tool":       "dev-main",
+//		"psr/log":         "^3.0",
+//	}, "stable")
+//	// map[acme/tool:dev monolog/monolog:beta]
+func ExtractFlags(require map[string]string, minimumStability string) map[string]string {
+	flags := make(map[string]string)
+	minimum := levels[minimumStability]
+
+	// 按包名排序，使结果与map的遍历顺序无关
+	names := make([]string, 0, len(require))
+	for name := range require {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		constraints := splitConstraints(require[name])
+		key := strings.ToLower(name)
+
+		matched := false
+		for _, c := range constraints {
+			m := flagRegex.FindStringSubmatch(c)
+			if m == nil {
+				continue
+			}
+			stability, _ := Normalize(m[1])
+			if current, ok := flags[key]; ok && levels[current] > levels[stability] {
+				continue
+			}
+			flags[key] = stability
+			matched = true
+		}
+		if matched {
+			continue
+		}
+
+		for _, c := range constraints {
+			c = aliasRegex.ReplaceAllString(c, "$1")
+			if !plainRegex.MatchString(c) {
+				continue
+			}
+			stability := version.ParseStability(c)
+			if stability == version.StabilityStable {
+				continue
+			}
+			if current, ok := flags[key]; (ok && levels[current] > levels[stability]) || minimum > levels[stability] {
+				continue
+			}
+			flags[key] = stability
+		}
+	}
+	return flags
+}
+
+// splitConstraints 将约束按或、与拆分为单个约束，别名（"1.0.x-dev as 1.0.0"）保持为一个整体
+func splitConstraints(s string) []string {
+	var result []string
+	for _, orPart := range orSplitRegex.Split(strings.TrimSpace(s), -1) {
+		tokens := strings.FieldsFunc(orPart, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+		for i := 0; i < len(tokens); i++ {
+			token := tokens[i]
+			for i+2 < len(tokens) && (tokens[i+1] == "as" || tokens[i+1] == "-") {
+				token += " " + tokens[i+1] + " " + tokens[i+2]
+				i += 2
+			}
+			result = append(result, token)
+		}
+	}
+	return result
+}
+
+// Resolver 根据minimum-stability、prefer-stable和稳定性标志判断包的候选版本
+type Resolver struct {
+	// MinimumStability 根包的minimum-stability，已规范化
+	MinimumStability string
+
+	// PreferStable 根包的prefer-stable
+	PreferStable bool
+
+	// Flags 每个包的稳定性标志，key为小写包名
+	Flags map[string]string
+}
+
+// NewResolver 创建Resolver
+//
+// 参数:
+//   - minimumStability: 根包的minimum-stability，为空时使用"stable"
+//   - preferStable: 根包的prefer-stable
+//   - requires: 用于提取稳定性标志的依赖映射，如require和require-dev
+//
+// 返回:
+//   - *Resolver: 稳定性解析器
+//   - error: 如果minimumStability无效，返回错误
+func NewResolver(minimumStability string, preferStable bool, requires ...map[string]string) (*Resolver, error) {
+	if minimumStability == "" {
+		minimumStability = version.StabilityStable
+	}
+	minimum, err := Normalize(minimumStability)
+	if err != nil {
+		return nil, fmt.Errorf("minimum-stability: %w", err)
+	}
+
+	merged := make(map[string]string)
+	for _, require := range requires {
+		for name, c := range require {
+			merged[name] = c
+		}
+	}
+
+	return &Resolver{
+		MinimumStability: minimum,
+		PreferStable:     preferStable,
+		Flags:            ExtractFlags(merged, minimum),
+	}, nil
+}
+
+// For 返回包实际允许的最低稳定性：有稳定性标志时使用标志，否则使用MinimumStability
+func (r *Resolver) For(packageName string) string {
+	if flag, ok := r.Flags[strings.ToLower(packageName)]; ok {
+		return flag
+	}
+	return r.MinimumStability
+}
+
+// Allows 判断包的候选版本是否满足稳定性要求
+//
+// 参数:
+//   - packageName: 包名
+//   - candidate: 候选版本，如"2.1.0-RC1"、"dev-main"
+//
+// 返回:
+//   - bool: 候选版本的稳定性不低于包实际允许的最低稳定性时返回true
+//   - error: 如果候选版本无效，返回错误
+func (r *Resolver) Allows(packageName, candidate string) (bool, error) {
+	normalized, err := version.Normalize(candidate)
+	if err != nil {
+		return false, err
+	}
+	return Compare(version.ParseStability(normalized), r.For(packageName)) <= 0, nil
+}
+
+// Select 从候选版本中选择包应使用的版本
+//
+// 只考虑满足稳定性要求的候选版本；PreferStable为true时先选择最稳定的一级，再在其中选择最高的版本，
+// 否则直接选择最高的版本。版本的比较规则与version.Order一致。
+//
+// 返回:
+//   - string: 选中的候选版本，保持原来的写法
+//   - bool: 没有满足要求的候选版本时返回false
+//   - error: 如果有无效的候选版本，返回错误
+func (r *Resolver) Select(packageName string, candidates []string) (string, bool, error) {
+	sorted, err := version.Sort(candidates)
+	if err != nil {
+		return "", false, err
+	}
+
+	best, bestStability := "", ""
+	for i := len(sorted) - 1; i >= 0; i-- {
+		candidate := sorted[i]
+		if ok, _ := r.Allows(packageName, candidate); !ok {
+			continue
+		}
+		stability := version.ParseStability(candidate)
+		if best == "" || (r.PreferStable && Compare(stability, bestStability) < 0) {
+			best, bestStability = candidate, stability
+		}
+	}
+	return best, best != "", nil
+}
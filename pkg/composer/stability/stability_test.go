@@ -0,0 +1,136 @@
+package stability
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{"rc": "RC", "RC": "RC", "Beta": "beta", "dev": "dev", "STABLE": "stable"}
+	for input, want := range tests {
+		if got, err := Normalize(input); err != nil || got != want {
+			t.Errorf("Normalize(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := Normalize("unstable"); err == nil {
+		t.Errorf("Normalize() should reject unknown stabilities")
+	}
+}
+
+func TestExtractFlags(t *testing.T) {
+	require := map[string]string{
+		"Monolog/Monolog": "^2.0@beta",
+		"acme/any":        "@dev",
+		"acme/tool":       "dev-main",
+		"acme/rc":         "1.0.0-RC1",
+		"acme/alias":      "1.0.x-dev as 1.0.0",
+		"acme/multi":      "^1.0@alpha || ^2.0@RC",
+		"acme/explicit":   "^3.0@stable, 3.1.0-beta1",
+		"psr/log":         "^3.0",
+	}
+
+	tests := []struct {
+		minimum string
+		want    map[string]string
+	}{
+		{
+			minimum: "stable",
+			want: map[string]string{
+				"monolog/monolog": "beta",
+				"acme/any":        "dev",
+				"acme/tool":       "dev",
+				"acme/rc":         "RC",
+				"acme/alias":      "dev",
+				"acme/multi":      "alpha",
+				"acme/explicit":   "stable",
+			},
+		},
+		{
+			// 推断的标志只在比minimum-stability更不稳定时生效，显式标志总是生效
+			minimum: "beta",
+			want: map[string]string{
+				"monolog/monolog": "beta",
+				"acme/any":        "dev",
+				"acme/tool":       "dev",
+				"acme/alias":      "dev",
+				"acme/multi":      "alpha",
+				"acme/explicit":   "stable",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.minimum, func(t *testing.T) {
+			if got := ExtractFlags(require, tt.minimum); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractFlags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver(t *testing.T) {
+	if _, err := NewResolver("unstable", false); err == nil {
+		t.Errorf("NewResolver() should reject an invalid minimum-stability")
+	}
+
+	r, err := NewResolver("", false, map[string]string{"monolog/monolog": "^2.0@beta"}, map[string]string{"phpunit/phpunit": "^10.0@dev"})
+	if err != nil {
+		t.Fatalf("NewResolver() returned unexpected error: %v", err)
+	}
+	if r.MinimumStability != "stable" || r.For("Monolog/Monolog") != "beta" || r.For("psr/log") != "stable" || r.For("phpunit/phpunit") != "dev" {
+		t.Errorf("NewResolver() = %+v", r)
+	}
+
+	tests := []struct {
+		pkg       string
+		candidate string
+		want      bool
+	}{
+		{"monolog/monolog", "2.1.0-RC1", true},
+		{"monolog/monolog", "2.1.0-beta2", true},
+		{"monolog/monolog", "2.1.0-alpha1", false},
+		{"psr/log", "3.0.0", true},
+		{"psr/log", "3.0.0-p1", true},
+		{"psr/log", "3.1.0-RC1", false},
+		{"psr/log", "dev-main", false},
+		{"phpunit/phpunit", "dev-main", true},
+	}
+	for _, tt := range tests {
+		got, err := r.Allows(tt.pkg, tt.candidate)
+		if err != nil {
+			t.Fatalf("Allows() returned unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Allows(%q, %q) = %v, want %v", tt.pkg, tt.candidate, got, tt.want)
+		}
+	}
+	if _, err := r.Allows("psr/log", "not a version"); err == nil {
+		t.Errorf("Allows() should fail for invalid versions")
+	}
+}
+
+func TestSelect(t *testing.T) {
+	candidates := []string{"1.0.0", "1.1.0", "2.0.0-beta1", "dev-main"}
+
+	tests := []struct {
+		minimum      string
+		preferStable bool
+		want         string
+	}{
+		{"stable", false, "1.1.0"},
+		{"dev", false, "2.0.0-beta1"},
+		{"dev", true, "1.1.0"},
+	}
+	for _, tt := range tests {
+		r, _ := NewResolver(tt.minimum, tt.preferStable)
+		got, ok, err := r.Select("acme/pkg", candidates)
+		if err != nil || !ok || got != tt.want {
+			t.Errorf("Select() with %s/%v = %q, %v, %v, want %q", tt.minimum, tt.preferStable, got, ok, err, tt.want)
+		}
+	}
+
+	r, _ := NewResolver("stable", true)
+	if _, ok, _ := r.Select("acme/pkg", []string{"dev-main", "1.0.0-RC1"}); ok {
+		t.Errorf("Select() should not find a stable candidate")
+	}
+}
@@ -0,0 +1,44 @@
+package composer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComposerJSON_EffectiveStabilities(t *testing.T) {
+	composer, err := ParseString(`{
+		"name": "vendor/project",
+		"minimum-stability": "RC",
+		"prefer-stable": true,
+		"require": {"monolog/monolog": "^2.0@beta", "psr/log": "^3.0"},
+		"require-dev": {"acme/tool": "dev-main"}
+	}`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	got, err := composer.EffectiveStabilities()
+	if err != nil {
+		t.Fatalf("EffectiveStabilities() returned unexpected error: %v", err)
+	}
+	want := map[string]string{"monolog/monolog": "beta", "psr/log": "RC", "acme/tool": "dev"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EffectiveStabilities() = %v, want %v", got, want)
+	}
+
+	resolver, err := composer.StabilityResolver()
+	if err != nil {
+		t.Fatalf("StabilityResolver() returned unexpected error: %v", err)
+	}
+	if !resolver.PreferStable {
+		t.Errorf("StabilityResolver() should use prefer-stable")
+	}
+	if ok, _ := resolver.Allows("psr/log", "3.1.0-beta1"); ok {
+		t.Errorf("psr/log should not allow beta versions")
+	}
+
+	composer.MinimumStability = "unstable"
+	if _, err := composer.EffectiveStabilities(); err == nil {
+		t.Errorf("EffectiveStabilities() should fail for an invalid minimum-stability")
+	}
+}
@@ -0,0 +1,156 @@
+package composer
+
+import (
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/document"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/manipulator"
+)
+
+// LinksSorted 判断链接部分是否已按Composer的sort-packages规则排序：平台包在前，其余按包名排序
+//
+// 参数:
+//   - linkType: 链接部分，如"require"、"require-dev"
+//
+// 返回:
+//   - bool: 链接部分已排序或不存在时返回true
+//
+// 判断基于ToJSON(true)的输出，因此与保存后文件中的顺序一致。
+//
+// 示例:
+//
+//	composer, _ := composer.ParseFile("./composer.json")
+//	if !composer.LinksSorted("require") {
+//		composer.SortLinks("require")
+//	}
+func (c *ComposerJSON) LinksSorted(linkType string) bool {
+	data, err := c.ToJSON(true)
+	if err != nil {
+		return true
+	}
+	m, err := manipulator.New([]byte(data))
+	if err != nil {
+		return true
+	}
+	return !m.SortLinks(linkType)
+}
+
+// SortLinks 按Composer的sort-packages规则对链接部分排序，保存时只有移动的行产生差异
+//
+// 参数:
+//   - linkType: 链接部分，如"require"、"require-dev"
+//
+// 返回:
+//   - bool: 如果顺序发生了变化返回true
+//
+// 示例:
+//
+//	composer, _ := composer.ParseFile("./composer.json")
+//	composer.SortLinks("require")
+//	composer.SortLinks("require-dev")
+//	composer.Save("./composer.json", true)
+func (c *ComposerJSON) SortLinks(linkType string) bool {
+	if c.LinksSorted(linkType) {
+		return false
+	}
+	if !c.rebaseSource() {
+		return false
+	}
+
+	sorted := false
+	c.editSource(func(m *manipulator.Manipulator) error {
+		sorted = m.SortLinks(linkType) || sorted
+		return nil
+	})
+	return sorted
+}
+
+// EmptySections 返回值为空对象或空数组的顶层键，如`"require-dev": {}`、`"keywords": []`
+//
+// 返回:
+//   - []string: 空的顶层键，按ToJSON(true)输出中的顺序排列
+//
+// 示例:
+//
+//	composer, _ := composer.ParseFile("./composer.json")
+//	fmt.Println(composer.EmptySections()) // [require-dev autoload-dev]
+func (c *ComposerJSON) EmptySections() []string {
+	data, err := c.ToJSON(true)
+	if err != nil {
+		return nil
+	}
+	doc, err := document.Parse([]byte(data))
+	if err != nil || doc.Root.Kind != document.Object {
+		return nil
+	}
+
+	var keys []string
+	for _, member := range doc.Root.Members {
+		switch v := member.Value; {
+		case v.Kind == document.Object && len(v.Members) == 0, v.Kind == document.Array && len(v.Elements) == 0:
+			keys = append(keys, member.Key)
+		}
+	}
+	return keys
+}
+
+// RemoveEmptySections 删除所有值为空对象或空数组的顶层键
+//
+// 返回:
+//   - []string: 被删除的键
+//
+// 示例:
+//
+//	composer, _ := composer.ParseFile("./composer.json")
+//	removed := composer.RemoveEmptySections()
+//	fmt.Println("已删除:", removed)
+//	composer.Save("./composer.json", true)
+func (c *ComposerJSON) RemoveEmptySections() []string {
+	var removed []string
+	for _, key := range c.EmptySections() {
+		if c.RemoveEmptySection(key) {
+			removed = append(removed, key)
+		}
+	}
+	return removed
+}
+
+// RemoveEmptySection 在顶层键的值为空对象或空数组时删除它
+//
+// 参数:
+//   - key: 顶层键，如"require-dev"
+//
+// 返回:
+//   - bool: 如果键存在、值为空并被删除返回true
+func (c *ComposerJSON) RemoveEmptySection(key string) bool {
+	empty := false
+	for _, k := range c.EmptySections() {
+		empty = empty || k == key
+	}
+	if !empty || !c.rebaseSource() {
+		return false
+	}
+
+	// 只从原始文档中删除：结构体类型的字段（如autoload）总是序列化为对象，
+	// 基准值中保留它们，合并时才不会把空对象重新写回
+	source, err := manipulator.New(c.source.Source)
+	if err != nil || !source.RemoveMainKey(key) {
+		return false
+	}
+	doc, err := document.Parse(source.Contents())
+	if err != nil {
+		return false
+	}
+	doc.Format = c.source.Format
+	c.source = doc
+	delete(c.Unknown, key)
+	return true
+}
+
+// rebaseSource 以ToJSON(true)的当前输出作为原始文档，使结构体上的修改和新建的结构体也可以就地编辑
+func (c *ComposerJSON) rebaseSource() bool {
+	data, err := c.ToJSON(true)
+	if err != nil {
+		return false
+	}
+	c.attachSource([]byte(data))
+	return c.source != nil
+}
@@ -0,0 +1,123 @@
+package composer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComposerJSON_SortLinks(t *testing.T) {
+	c, err := ParseString(`{
+    "name": "acme/app",
+    "require": {
+        "symfony/console": "^6.0",
+        "php": "^8.2",
+        "ext-json": "*"
+    },
+    "require-dev": {
+        "phpunit/phpunit": "^10.5"
+    }
+}
+`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	if c.LinksSorted("require") || !c.LinksSorted("require-dev") || !c.LinksSorted("conflict") {
+		t.Errorf("LinksSorted() = %v, %v, %v", c.LinksSorted("require"), c.LinksSorted("require-dev"), c.LinksSorted("conflict"))
+	}
+	if c.SortLinks("require-dev") {
+		t.Error("SortLinks() returned true for sorted links")
+	}
+
+	c.Require["acme/lib"] = "^1.0"
+	if !c.SortLinks("require") {
+		t.Fatal("SortLinks() returned false for unsorted links")
+	}
+	if !c.LinksSorted("require") {
+		t.Error("LinksSorted() = false after SortLinks()")
+	}
+
+	got, err := c.ToJSON(true)
+	if err != nil {
+		t.Fatalf("ToJSON() returned unexpected error: %v", err)
+	}
+	want := `{
+    "name": "acme/app",
+    "require": {
+        "php": "^8.2",
+        "ext-json": "*",
+        "acme/lib": "^1.0",
+        "symfony/console": "^6.0"
+    },
+    "require-dev": {
+        "phpunit/phpunit": "^10.5"
+    }
+}
+`
+	if got != want {
+		t.Errorf("ToJSON() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestComposerJSON_SortLinks_NewStruct(t *testing.T) {
+	c := &ComposerJSON{Name: "acme/app", Require: map[string]string{"acme/lib": "^1.0", "ext-json": "*", "php": "^8.2"}}
+	if !c.SortLinks("require") {
+		t.Fatal("SortLinks() returned false for unsorted links")
+	}
+	got, err := c.ToJSON(true)
+	if err != nil {
+		t.Fatalf("ToJSON() returned unexpected error: %v", err)
+	}
+	if strings.Index(got, `"php"`) > strings.Index(got, `"ext-json"`) || strings.Index(got, `"ext-json"`) > strings.Index(got, `"acme/lib"`) {
+		t.Errorf("ToJSON() = %s, want php, ext-json, acme/lib", got)
+	}
+}
+
+func TestComposerJSON_RemoveEmptySections(t *testing.T) {
+	c, err := ParseString(`{
+    "name": "acme/app",
+    "keywords": [],
+    "require": {
+        "php": "^8.2"
+    },
+    "require-dev": {},
+    "autoload": {},
+    "funding": [],
+    "extra": {"branch-alias": {}}
+}
+`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	want := []string{"keywords", "require-dev", "autoload", "funding"}
+	if got := c.EmptySections(); !reflect.DeepEqual(got, want) {
+		t.Errorf("EmptySections() = %v, want %v", got, want)
+	}
+	if got := c.RemoveEmptySections(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RemoveEmptySections() = %v, want %v", got, want)
+	}
+	if got := c.EmptySections(); len(got) != 0 {
+		t.Errorf("EmptySections() after removal = %v", got)
+	}
+
+	got, err := c.ToJSON(true)
+	if err != nil {
+		t.Fatalf("ToJSON() returned unexpected error: %v", err)
+	}
+	wantJSON := `{
+    "name": "acme/app",
+    "require": {
+        "php": "^8.2"
+    },
+    "extra": {"branch-alias": {}}
+}
+`
+	if got != wantJSON {
+		t.Errorf("ToJSON() =\n%s\nwant\n%s", got, wantJSON)
+	}
+	if _, ok := c.GetUnknown("funding"); ok {
+		t.Error("RemoveEmptySections() kept the unknown funding key")
+	}
+}
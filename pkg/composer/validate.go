@@ -0,0 +1,61 @@
+package composer
+
+import (
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/parser"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/validation"
+)
+
+// Validate 按`composer validate`的规则检查composer.json
+//
+// 参数:
+//   - opts: 要跳过的检查，与--no-check-all、--no-check-publish和--no-check-version对应
+//
+// 返回:
+//   - validation.Diagnostics: 发现的问题，每个问题包括严重程度（error、publish-error、warning）、JSON指针和说明；
+//     按错误、发布错误、警告的顺序排列
+//   - error: 如果序列化composer.json失败，返回错误
+//
+// 检查使用ToJSON(true)的输出，解析后重复的键已经合并；需要检查重复的键时使用ValidateFile。
+//
+// 示例:
+//
+//	project, _ := composer.ParseDir(".")
+//
+//	diags, err := project.Validate(validation.Options{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, d := range diags {
+//		fmt.Println(d) // warning /license: No license specified, ...
+//	}
+//	if diags.HasErrors() {
+//		os.Exit(2)
+//	}
+func (c *ComposerJSON) Validate(opts validation.Options) (validation.Diagnostics, error) {
+	data, err := c.ToJSON(true)
+	if err != nil {
+		return nil, err
+	}
+	return validation.Validate([]byte(data), opts)
+}
+
+// ValidateFile 按`composer validate`的规则检查composer.json文件的原始内容，规则见validation.Validate
+//
+// 参数:
+//   - filePath: composer.json文件路径
+//   - opts: 要跳过的检查
+//
+// 返回:
+//   - validation.Diagnostics: 发现的问题
+//   - error: 如果读取文件失败或内容不是JSON对象，返回错误
+//
+// 示例:
+//
+//	diags, err := composer.ValidateFile("./composer.json", validation.Options{NoCheckPublish: true})
+func ValidateFile(filePath string, opts validation.Options) (validation.Diagnostics, error) {
+	data, err := parser.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return validation.Validate(data, opts)
+}
@@ -0,0 +1,72 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/validation"
+)
+
+func TestComposerJSON_Validate(t *testing.T) {
+	c, err := ParseString(`{
+    "name": "acme/app",
+    "description": "An example application",
+    "license": "MIT",
+    "require": {
+        "psr/log": "^3.0"
+    }
+}`)
+	if err != nil {
+		t.Fatalf("ParseString() returned unexpected error: %v", err)
+	}
+
+	diags, err := c.Validate(validation.Options{})
+	if err != nil {
+		t.Fatalf("Validate() returned unexpected error: %v", err)
+	}
+	if len(diags) != 0 {
+		t.Errorf("Validate() = %v, want no diagnostics", diags)
+	}
+
+	c.Require["acme/tool"] = "*"
+	c.License = nil
+	diags, err = c.Validate(validation.Options{})
+	if err != nil {
+		t.Fatalf("Validate() returned unexpected error: %v", err)
+	}
+	want := map[string]bool{"/require/acme~1tool": true, "/license": true}
+	if len(diags) != len(want) {
+		t.Fatalf("Validate() = %v, want diagnostics at %v", diags, want)
+	}
+	for _, d := range diags {
+		if !want[d.Pointer] || d.Severity != validation.SeverityWarning {
+			t.Errorf("unexpected diagnostic %v", d)
+		}
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "composer.json")
+	if err := os.WriteFile(path, []byte("{\n    \"name\": \"acme/app\",\n    \"name\": \"acme/app\"\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	diags, err := ValidateFile(path, validation.Options{NoCheckPublish: true})
+	if err != nil {
+		t.Fatalf("ValidateFile() returned unexpected error: %v", err)
+	}
+	found := false
+	for _, d := range diags {
+		if d.Pointer == "/name" && d.Message == "Key name is a duplicate at line 3" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ValidateFile() = %v, want the duplicate name key", diags)
+	}
+
+	if _, err := ValidateFile(filepath.Join(t.TempDir(), "missing.json"), validation.Options{}); err == nil {
+		t.Error("ValidateFile() with a missing file expected error, got nil")
+	}
+}
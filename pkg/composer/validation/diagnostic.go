@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/parser"
+)
+
+// Severity is the severity of a diagnostic, matching the sections of `composer validate` output
+type Severity string
+
+// Severity levels
+const (
+	// SeverityError makes the package unusable, e.g. an invalid version constraint
+	SeverityError Severity = "error"
+
+	// SeverityPublishError prevents the package from being published on Packagist, e.g. a missing description
+	SeverityPublishError Severity = "publish-error"
+
+	// SeverityWarning points at a bad practice, e.g. a missing license or an unbound constraint
+	SeverityWarning Severity = "warning"
+)
+
+// rank orders severities from the most to the least severe
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 0
+	case SeverityPublishError:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Diagnostic is a single problem found in a composer.json document
+type Diagnostic struct {
+	// Severity of the problem
+	Severity Severity
+
+	// Pointer is the RFC 6901 JSON pointer of the offending value, e.g. "/require/acme~1lib";
+	// it may point at a missing key such as "/license"
+	Pointer string
+
+	// Message describes the problem in the words Composer uses
+	Message string
+}
+
+// String formats the diagnostic as "severity pointer: message"
+func (d Diagnostic) String() string {
+	if d.Pointer == "" {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", d.Severity, d.Pointer, d.Message)
+}
+
+// Diagnostics is a list of diagnostics, errors first, then publish errors, then warnings
+type Diagnostics []Diagnostic
+
+// Filter returns the diagnostics with the given severity
+func (ds Diagnostics) Filter(severity Severity) Diagnostics {
+	var result Diagnostics
+	for _, d := range ds {
+		if d.Severity == severity {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// HasErrors reports whether any diagnostic is an error
+func (ds Diagnostics) HasErrors() bool {
+	return len(ds.Filter(SeverityError)) > 0
+}
+
+// sort orders the diagnostics by severity, keeping the order of checks within a severity
+func (ds Diagnostics) sort() {
+	sort.SliceStable(ds, func(i, j int) bool {
+		return ds[i].Severity.rank() < ds[j].Severity.rank()
+	})
+}
+
+// Pointer builds an RFC 6901 JSON pointer from unescaped reference tokens
+//
+// Example:
+//
+//	validation.Pointer("require", "acme/lib") // "/require/acme~1lib"
+func Pointer(tokens ...string) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteByte('/')
+		b.WriteString(parser.EscapePointer(t))
+	}
+	return b.String()
+}
@@ -0,0 +1,656 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/constraint"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/dependency"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/document"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/spdx"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/stability"
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/version"
+)
+
+// Options control which checks Validate runs, mirroring the flags of `composer validate`
+type Options struct {
+	// NoCheckAll skips the warnings about unbound and exact version constraints (--no-check-all)
+	NoCheckAll bool
+
+	// NoCheckPublish omits publish errors (--no-check-publish)
+	NoCheckPublish bool
+
+	// NoCheckVersion skips the warning about the version field (--no-check-version)
+	NoCheckVersion bool
+}
+
+// linkTypes are the sections mapping package names to version constraints, in Composer's order
+var linkTypes = []string{"require", "require-dev", "conflict", "replace", "provide"}
+
+// autoloadTypes are the valid keys of autoload and autoload-dev
+var autoloadTypes = []string{"psr-0", "psr-4", "classmap", "files", "exclude-from-classmap"}
+
+// Validate runs the checks of `composer validate` against the raw content of a composer.json file
+//
+// The checks reproduce Composer's ConfigValidator and ValidatingArrayLoader:
+//   - duplicate keys, a missing name or description, and name casing
+//   - a missing, deprecated or invalid SPDX license ("proprietary" is accepted)
+//   - the presence and validity of the version field, the deprecated composer-installer type
+//   - invalid or self-referencing links, invalid constraints, unbound ("*", ">=1.0") and exact
+//     require constraints, constraints that cannot match anything, and conflicts with replaced packages
+//   - packages required in both require and require-dev, requirements satisfied by the package's own
+//     provide or replace, and commit references ("dev-main#abc123")
+//   - descriptions and aliases of missing scripts, unknown autoload types, PSR-4 namespaces without a
+//     trailing separator, empty namespace prefixes and the deprecated PSR-0 autoloader
+//   - invalid minimum-stability, time, homepage, keywords, authors, support and extra.branch-alias values
+//
+// Structural problems that the JSON schema catches, such as a string where an object is expected,
+// are only reported where the checks above need the value; use the schema package to check the
+// whole document against Composer's JSON schema.
+//
+// Parameters:
+//   - data: the raw composer.json content
+//   - opts: the checks to skip
+//
+// Returns:
+//   - Diagnostics: the problems found, errors first, then publish errors, then warnings
+//   - error: if data is not a JSON object
+//
+// Example:
+//
+//	data, _ := os.ReadFile("composer.json")
+//	diags, err := validation.Validate(data, validation.Options{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, d := range diags {
+//		fmt.Println(d) // warning /require/acme~1lib: unbound version constraints (*) should be avoided
+//	}
+func Validate(data []byte, opts Options) (Diagnostics, error) {
+	doc, err := document.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Root.Kind != document.Object {
+		return nil, fmt.Errorf("composer.json must be an object, got %s", doc.Root.Kind)
+	}
+
+	c := &checker{opts: opts, root: doc.Root, source: data}
+	c.checkDuplicateKeys(doc.Root, "")
+	c.checkName()
+	c.checkDescription()
+	c.checkLicense()
+	c.checkVersion()
+	c.checkType()
+	c.checkMinimumStability()
+	c.checkLinks()
+	c.checkRequireOverrides()
+	c.checkSatisfiedByPackage()
+	c.checkCommitReferences()
+	c.checkScripts()
+	c.checkAutoload()
+	c.checkTime()
+	c.checkHomepage()
+	c.checkKeywords()
+	c.checkAuthors()
+	c.checkSupport()
+	c.checkBranchAliases()
+
+	c.diags.sort()
+	return c.diags, nil
+}
+
+// checker collects the diagnostics of one document
+type checker struct {
+	opts   Options
+	root   *document.Node
+	source []byte
+	diags  Diagnostics
+}
+
+// add records a diagnostic, dropping publish errors when they are disabled
+func (c *checker) add(severity Severity, pointer, format string, args ...interface{}) {
+	if severity == SeverityPublishError && c.opts.NoCheckPublish {
+		return
+	}
+	c.diags = append(c.diags, Diagnostic{Severity: severity, Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// isEmpty reports whether a value is absent or empty in PHP's sense: null, false, "", [] or {}
+func isEmpty(n *document.Node) bool {
+	if n == nil {
+		return true
+	}
+	switch n.Kind {
+	case document.Null:
+		return true
+	case document.Bool:
+		return string(n.Raw) == "false"
+	case document.String:
+		s, _ := n.StringValue()
+		return s == ""
+	case document.Array:
+		return len(n.Elements) == 0
+	case document.Object:
+		return len(n.Members) == 0
+	}
+	return false
+}
+
+// isMap reports whether a value can hold a PHP associative array: an object or an empty array
+func isMap(n *document.Node) bool {
+	return n != nil && (n.Kind == document.Object || (n.Kind == document.Array && len(n.Elements) == 0))
+}
+
+// line returns the 1-based line of a byte offset in the source
+func (c *checker) line(offset int) int {
+	return strings.Count(string(c.source[:offset]), "\n") + 1
+}
+
+// checkDuplicateKeys reports keys defined more than once in the same object
+func (c *checker) checkDuplicateKeys(n *document.Node, pointer string) {
+	switch n.Kind {
+	case document.Object:
+		seen := make(map[string]bool)
+		for _, m := range n.Members {
+			p := pointer + Pointer(m.Key)
+			if seen[m.Key] {
+				c.add(SeverityWarning, p, "Key %s is a duplicate at line %d", m.Key, c.line(m.KeyStart))
+			}
+			seen[m.Key] = true
+			c.checkDuplicateKeys(m.Value, p)
+		}
+	case document.Array:
+		for i, e := range n.Elements {
+			c.checkDuplicateKeys(e, pointer+Pointer(strconv.Itoa(i)))
+		}
+	}
+}
+
+// packageNameRegex matches valid package names, as Composer's ValidatingArrayLoader
+var packageNameRegex = regexp.MustCompile(`(?i)^[a-z0-9](?:[_.-]?[a-z0-9]+)*/[a-z0-9](?:(?:[_.]|-{1,2})?[a-z0-9]+)*$`)
+
+// reservedNames cannot be used as vendor or package names because they are reserved on Windows
+var reservedNames = []string{"nul", "con", "prn", "aux", "com1", "com2", "com3", "com4", "com5", "com6", "com7", "com8", "com9", "lpt1", "lpt2", "lpt3", "lpt4", "lpt5", "lpt6", "lpt7", "lpt8", "lpt9"}
+
+// namingError returns Composer's message for an invalid package name, or "" if the name is valid.
+// Uppercase characters are only reported here for links; the casing of the root name is a publish error.
+func namingError(name string, isLink bool) string {
+	if dependency.IsPlatformPackage(name) {
+		return ""
+	}
+	if !packageNameRegex.MatchString(name) {
+		return name + ` is invalid, it should have a vendor name, a forward slash, and a package name. The vendor and package name can be words separated by -, . or _. The complete name should match "^[a-z0-9]([_.-]?[a-z0-9]+)*/[a-z0-9](([_.]?|-{0,2})[a-z0-9]+)*$".`
+	}
+	vendor, project, _ := strings.Cut(strings.ToLower(name), "/")
+	for _, reserved := range reservedNames {
+		if vendor == reserved || project == reserved {
+			return name + " is reserved, package and vendor names can not match any of: " + strings.Join(reservedNames, ", ") + "."
+		}
+	}
+	if strings.HasSuffix(name, ".json") {
+		return name + " is invalid, package names can not end in .json, consider renaming it or perhaps using a -json suffix instead."
+	}
+	if isLink && strings.ToLower(name) != name {
+		return name + " is invalid, it should not contain uppercase characters. Please use " + strings.ToLower(name) + " instead."
+	}
+	return ""
+}
+
+// camelCaseRegex finds the word boundaries Composer turns into dashes when suggesting a lowercase name
+var camelCaseRegex = regexp.MustCompile(`([a-z])([A-Z])|([A-Z])([A-Z][a-z])`)
+
+// checkName checks that the name is present, valid and lowercase
+func (c *checker) checkName() {
+	n := c.root.Get("name")
+	if isEmpty(n) {
+		c.add(SeverityPublishError, "/name", "The property name is required")
+		return
+	}
+	name, ok := n.StringValue()
+	if !ok {
+		c.add(SeverityError, "/name", "must be a string, %s given", n.Kind)
+		return
+	}
+	if msg := namingError(name, false); msg != "" {
+		c.add(SeverityError, "/name", "%s", msg)
+	}
+	if strings.ToLower(name) != name {
+		suggest := strings.ToLower(camelCaseRegex.ReplaceAllString(name, "${1}${3}-${2}${4}"))
+		c.add(SeverityPublishError, "/name", `Name "%s" does not match the best practice (e.g. lower-cased/with-dashes). We suggest using "%s" instead. As such you will not be able to submit it to Packagist.`, name, suggest)
+	}
+}
+
+// checkDescription checks that the description is present
+func (c *checker) checkDescription() {
+	if c.root.Get("description") == nil {
+		c.add(SeverityPublishError, "/description", "The property description is required")
+	}
+}
+
+// checkLicense checks that a license is present and that every license is a current SPDX expression
+func (c *checker) checkLicense() {
+	n := c.root.Get("license")
+	if isEmpty(n) {
+		c.add(SeverityWarning, "/license", `No license specified, it is recommended to do so. For closed-source software you may use "proprietary" as license.`)
+		return
+	}
+
+	type license struct {
+		pointer string
+		value   string
+	}
+	var licenses []license
+	switch n.Kind {
+	case document.String:
+		s, _ := n.StringValue()
+		licenses = append(licenses, license{"/license", s})
+	case document.Array:
+		for i, e := range n.Elements {
+			p := Pointer("license", strconv.Itoa(i))
+			s, ok := e.StringValue()
+			if !ok {
+				c.add(SeverityError, p, "must be a string, %s given", e.Kind)
+				continue
+			}
+			licenses = append(licenses, license{p, s})
+		}
+	default:
+		c.add(SeverityError, "/license", "must be a string or an array of strings, %s given", n.Kind)
+		return
+	}
+
+	for _, l := range licenses {
+		// proprietary is not an SPDX identifier but Composer accepts it
+		if l.value == "proprietary" {
+			continue
+		}
+
+		if expr, err := spdx.Parse(l.value); err == nil {
+			for _, d := range spdx.Deprecations(expr) {
+				c.add(SeverityWarning, l.pointer, "License %s", d)
+			}
+		}
+
+		expression := strings.ReplaceAll(l.value, "proprietary", "MIT")
+		if spdx.Valid(expression) {
+			continue
+		}
+		quoted, _ := json.Marshal(l.value)
+		if spdx.Valid(strings.TrimSpace(expression)) {
+			c.add(SeverityWarning, l.pointer, "License %s must not contain extra spaces, make sure to trim it.", quoted)
+		} else {
+			c.add(SeverityWarning, l.pointer, `License %s is not a valid SPDX license identifier, see https://spdx.org/licenses/ if you use an open license. If the software is closed-source, you may use "proprietary" as license.`, quoted)
+		}
+	}
+}
+
+// checkVersion checks that the version field is valid and recommends leaving it out
+func (c *checker) checkVersion() {
+	n := c.root.Get("version")
+	if n == nil {
+		return
+	}
+	if !c.opts.NoCheckVersion {
+		c.add(SeverityWarning, "/version", "The version field is present, it is recommended to leave it out if the package is published on Packagist.")
+	}
+	v, ok := n.StringValue()
+	if !ok {
+		c.add(SeverityError, "/version", "must be a string, %s given", n.Kind)
+		return
+	}
+	if _, err := version.Normalize(v); err != nil {
+		c.add(SeverityError, "/version", "invalid value (%s): %v", v, err)
+	}
+}
+
+// checkType reports the deprecated composer-installer type
+func (c *checker) checkType() {
+	if t, _ := c.root.Get("type").StringValue(); t == "composer-installer" {
+		c.add(SeverityWarning, "/type", "The package type 'composer-installer' is deprecated. Please distribute your custom installers as plugins from now on. See https://getcomposer.org/doc/articles/plugins.md for plugin documentation.")
+	}
+}
+
+// checkMinimumStability checks that minimum-stability is a known stability
+func (c *checker) checkMinimumStability() {
+	n := c.root.Get("minimum-stability")
+	if n == nil {
+		return
+	}
+	s, ok := n.StringValue()
+	if _, err := stability.Normalize(s); !ok || err != nil {
+		c.add(SeverityError, "/minimum-stability", "invalid value (%s), must be one of stable, RC, beta, alpha, dev", strings.Trim(string(n.Raw), `"`))
+	}
+}
+
+// links returns the members of a link section, or nil if it is absent or not an object
+func (c *checker) links(linkType string) []*document.Member {
+	n := c.root.Get(linkType)
+	if n == nil || n.Kind != document.Object {
+		return nil
+	}
+	return n.Members
+}
+
+// unboundVersion is matched by every constraint without an upper bound, as in Composer
+const unboundVersion = "10000000-dev"
+
+// checkLinks checks the package names and constraints of require, require-dev, conflict, replace and provide
+func (c *checker) checkLinks() {
+	rootName, _ := c.root.Get("name").StringValue()
+	replaced := make(map[string]bool)
+	for _, m := range c.links("replace") {
+		replaced[strings.ToLower(m.Key)] = true
+	}
+
+	for _, linkType := range linkTypes {
+		if n := c.root.Get(linkType); n != nil && !isMap(n) {
+			c.add(SeverityError, Pointer(linkType), "must be an object mapping package names to version constraints, %s given", n.Kind)
+			continue
+		}
+
+		for _, m := range c.links(linkType) {
+			p := Pointer(linkType, m.Key)
+			if rootName != "" && strings.EqualFold(m.Key, rootName) {
+				c.add(SeverityError, p, "a package cannot set a %s on itself", linkType)
+				continue
+			}
+			if msg := namingError(m.Key, true); msg != "" {
+				c.add(SeverityWarning, p, "%s", msg)
+			} else if !linkNameRegex.MatchString(m.Key) {
+				c.add(SeverityError, p, "invalid key, package names must be strings containing only [A-Za-z0-9_./-]")
+			}
+
+			s, ok := m.Value.StringValue()
+			if !ok {
+				c.add(SeverityError, p, "invalid value, must be a string containing a version constraint")
+				continue
+			}
+			if s != "self.version" {
+				c.checkConstraint(linkType, m.Key, s, p)
+			}
+
+			if linkType == "conflict" && replaced[strings.ToLower(m.Key)] {
+				c.add(SeverityError, p, "you cannot conflict with a package that is also replaced, as replace already creates an implicit conflict rule")
+			}
+		}
+	}
+}
+
+// linkNameRegex matches the characters allowed in link names
+var linkNameRegex = regexp.MustCompile(`^[A-Za-z0-9_./-]+$`)
+
+// checkConstraint checks a single link constraint
+func (c *checker) checkConstraint(linkType, name, s, pointer string) {
+	parsed, err := constraint.Parse(s)
+	if err != nil {
+		c.add(SeverityError, pointer, "invalid version constraint (%v)", err)
+		return
+	}
+
+	if linkType == "require" && !c.opts.NoCheckAll {
+		if parsed.Matches(unboundVersion) && !dependency.IsPlatformPackage(name) {
+			c.add(SeverityWarning, pointer, "unbound version constraints (%s) should be avoided", s)
+		} else if single, ok := parsed.(*constraint.Single); ok && single.Operator == constraint.OpEqual &&
+			!version.IsBranch(single.Version) && version.Compare(single.Version, "1.0.0.0-dev") >= 0 {
+			c.add(SeverityWarning, pointer, "exact version constraints (%s) should be avoided if the package follows semantic versioning", s)
+		}
+	}
+
+	if constraint.SetOf(parsed).IsEmpty() {
+		c.add(SeverityWarning, pointer, "this version constraint cannot possibly match anything (%s)", s)
+	}
+}
+
+// checkRequireOverrides reports packages listed in both require and require-dev
+func (c *checker) checkRequireOverrides() {
+	require := make(map[string]bool)
+	for _, m := range c.links("require") {
+		require[m.Key] = true
+	}
+	for _, m := range c.links("require-dev") {
+		if require[m.Key] {
+			c.add(SeverityWarning, Pointer("require-dev", m.Key), "%s is required both in require and require-dev, this can lead to unexpected behavior", m.Key)
+		}
+	}
+}
+
+// checkSatisfiedByPackage reports requirements that the package's own provide or replace already satisfies
+func (c *checker) checkSatisfiedByPackage() {
+	for _, linkType := range []string{"provide", "replace"} {
+		for _, requireType := range []string{"require", "require-dev"} {
+			required := make(map[string]bool)
+			for _, m := range c.links(requireType) {
+				required[m.Key] = true
+			}
+			for _, m := range c.links(linkType) {
+				if required[m.Key] {
+					c.add(SeverityWarning, Pointer(linkType, m.Key), "The package %s in %s is also listed in %s which satisfies the requirement. Remove it from %s if you wish to install it.", m.Key, requireType, linkType, linkType)
+				}
+			}
+		}
+	}
+}
+
+// checkCommitReferences reports requirements pinned to a commit
+func (c *checker) checkCommitReferences() {
+	for _, requireType := range []string{"require", "require-dev"} {
+		for _, m := range c.links(requireType) {
+			if s, ok := m.Value.StringValue(); ok && strings.Contains(s, "#") {
+				c.add(SeverityWarning, Pointer(requireType, m.Key), `The package "%s" is pointing to a commit-ref, this is bad practice and can cause unforeseen issues.`, m.Key)
+			}
+		}
+	}
+}
+
+// checkScripts reports descriptions and aliases of scripts that do not exist
+func (c *checker) checkScripts() {
+	scripts := c.root.Get("scripts")
+	for _, section := range []struct{ key, what string }{
+		{"scripts-descriptions", "Description"},
+		{"scripts-aliases", "Aliases"},
+	} {
+		n := c.root.Get(section.key)
+		if n == nil || n.Kind != document.Object {
+			continue
+		}
+		for _, m := range n.Members {
+			if scripts.Member(m.Key) == nil {
+				c.add(SeverityWarning, Pointer(section.key, m.Key), `%s for non-existent script "%s" found in "%s"`, section.what, m.Key, section.key)
+			}
+		}
+	}
+}
+
+// checkAutoload checks the autoload types, namespace prefixes and the use of PSR-0
+func (c *checker) checkAutoload() {
+	for _, section := range []string{"autoload", "autoload-dev"} {
+		n := c.root.Get(section)
+		if n == nil || n.Kind != document.Object {
+			continue
+		}
+		for _, m := range n.Members {
+			if !contains(autoloadTypes, m.Key) {
+				c.add(SeverityError, Pointer(section, m.Key), "invalid value (%s), must be one of %s", m.Key, strings.Join(autoloadTypes, ", "))
+			}
+		}
+
+		for _, psr := range []string{"psr-0", "psr-4"} {
+			prefixes := n.Get(psr)
+			if prefixes == nil {
+				continue
+			}
+			if psr == "psr-0" {
+				c.add(SeverityWarning, Pointer(section, psr), "PSR-0 autoloading is deprecated, use PSR-4 instead")
+			}
+			if prefixes.Kind != document.Object {
+				continue
+			}
+			for _, m := range prefixes.Members {
+				p := Pointer(section, psr, m.Key)
+				if m.Key == "" {
+					c.add(SeverityWarning, p, "Defining %s.%s with an empty namespace prefix is a bad idea for performance", section, psr)
+				} else if psr == "psr-4" && !strings.HasSuffix(m.Key, `\`) {
+					c.add(SeverityError, p, `invalid value (%s), namespaces must end with a namespace separator, should be %s\\`, m.Key, m.Key)
+				}
+			}
+		}
+	}
+}
+
+// timeLayouts are the release date formats accepted for the time field
+var timeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// checkTime checks that the release date can be parsed
+func (c *checker) checkTime() {
+	n := c.root.Get("time")
+	if n == nil {
+		return
+	}
+	s, _ := n.StringValue()
+	for _, layout := range timeLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return
+		}
+	}
+	c.add(SeverityError, "/time", "invalid value (%s), must be a date such as 2024-01-31 or 2024-01-31T12:00:00+00:00", strings.Trim(string(n.Raw), `"`))
+}
+
+// isHTTPURL reports whether s is an absolute http or https URL
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isEmail reports whether s is a bare email address
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+
+// checkURL warns if a string value is not an http or https URL
+func (c *checker) checkURL(n *document.Node, pointer string) {
+	if s, ok := n.StringValue(); ok && !isHTTPURL(s) {
+		c.add(SeverityWarning, pointer, "invalid value (%s), must be an http/https URL", s)
+	}
+}
+
+// checkEmail warns if a string value is not an email address
+func (c *checker) checkEmail(n *document.Node, pointer string) {
+	if s, ok := n.StringValue(); ok && !isEmail(s) {
+		c.add(SeverityWarning, pointer, "invalid value (%s), must be a valid email address", s)
+	}
+}
+
+// checkHomepage checks that the homepage is a URL
+func (c *checker) checkHomepage() {
+	if n := c.root.Get("homepage"); n != nil {
+		c.checkURL(n, "/homepage")
+	}
+}
+
+// keywordRegex matches valid keywords
+var keywordRegex = regexp.MustCompile(`^[\p{N}\p{L} ._-]+$`)
+
+// checkKeywords checks that keywords only contain letters, numbers, spaces, ".", "_" and "-"
+func (c *checker) checkKeywords() {
+	n := c.root.Get("keywords")
+	if n == nil || n.Kind != document.Array {
+		return
+	}
+	for i, e := range n.Elements {
+		if s, ok := e.StringValue(); ok && !keywordRegex.MatchString(s) {
+			c.add(SeverityWarning, Pointer("keywords", strconv.Itoa(i)), `invalid value (%s), must match [\p{N}\p{L} ._-]+`, s)
+		}
+	}
+}
+
+// checkAuthors checks the email and homepage of each author
+func (c *checker) checkAuthors() {
+	n := c.root.Get("authors")
+	if n == nil || n.Kind != document.Array {
+		return
+	}
+	for i, author := range n.Elements {
+		if email := author.Get("email"); email != nil {
+			c.checkEmail(email, Pointer("authors", strconv.Itoa(i), "email"))
+		}
+		if homepage := author.Get("homepage"); homepage != nil {
+			c.checkURL(homepage, Pointer("authors", strconv.Itoa(i), "homepage"))
+		}
+	}
+}
+
+// checkSupport checks the support email, IRC channel and URLs
+func (c *checker) checkSupport() {
+	n := c.root.Get("support")
+	if n == nil || n.Kind != document.Object {
+		return
+	}
+	for _, m := range n.Members {
+		p := Pointer("support", m.Key)
+		switch m.Key {
+		case "email":
+			c.checkEmail(m.Value, p)
+		case "irc":
+			if s, ok := m.Value.StringValue(); ok && !strings.HasPrefix(s, "irc://") && !strings.HasPrefix(s, "ircs://") {
+				c.add(SeverityWarning, p, "invalid value (%s), must be a irc://<server>/<channel> or ircs:// URL", s)
+			}
+		default:
+			c.checkURL(m.Value, p)
+		}
+	}
+}
+
+// numericAliasRegex extracts the numeric prefix of a branch such as "2.1.x-dev", as Composer's parseNumericAliasPrefix
+var numericAliasRegex = regexp.MustCompile(`(?i)^(?P<version>(\d+\.)*\d+)(?:\.x)?-dev$`)
+
+// checkBranchAliases checks that every branch alias targets a numeric -dev version compatible with the source branch
+func (c *checker) checkBranchAliases() {
+	aliases := c.root.Get("extra").Get("branch-alias")
+	if aliases == nil {
+		return
+	}
+	if aliases.Kind != document.Object {
+		c.add(SeverityError, "/extra/branch-alias", "must be an array of versions => aliases")
+		return
+	}
+
+	for _, m := range aliases.Members {
+		p := Pointer("extra", "branch-alias", m.Key)
+		target, ok := m.Value.StringValue()
+		if !ok {
+			c.add(SeverityWarning, p, "the target branch (%s) must be a string, %s given", m.Value.Raw, m.Value.Kind)
+			continue
+		}
+		if !strings.HasSuffix(target, "-dev") {
+			c.add(SeverityWarning, p, "the target branch (%s) must end in -dev", target)
+			continue
+		}
+		if version.IsBranch(version.NormalizeBranch(strings.TrimSuffix(target, "-dev"))) {
+			c.add(SeverityWarning, p, "the target branch (%s) must be a parseable number like 2.0-dev", target)
+			continue
+		}
+		source := numericAliasRegex.FindStringSubmatch(m.Key)
+		alias := numericAliasRegex.FindStringSubmatch(target)
+		if source != nil && alias != nil && !strings.HasPrefix(strings.ToLower(alias[1]+"."), strings.ToLower(source[1]+".")) {
+			c.add(SeverityWarning, p, "the target branch (%s) is not a valid numeric alias for this version", target)
+		}
+	}
+}
+
+// contains reports whether list contains s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+// validDocument passes every check
+const validDocument = `{
+    "name": "acme/app",
+    "description": "An example application",
+    "license": "MIT",
+    "require": {
+        "php": ">=8.1",
+        "psr/log": "^3.0"
+    },
+    "require-dev": {
+        "phpunit/phpunit": "^10.5"
+    },
+    "autoload": {
+        "psr-4": {"Acme\\App\\": "src/"}
+    },
+    "scripts": {"test": "phpunit"},
+    "scripts-descriptions": {"test": "Run the tests"},
+    "extra": {"branch-alias": {"dev-main": "1.x-dev"}}
+}`
+
+func TestValidate_Valid(t *testing.T) {
+	diags, err := Validate([]byte(validDocument), Options{})
+	if err != nil {
+		t.Fatalf("Validate() returned unexpected error: %v", err)
+	}
+	if len(diags) != 0 {
+		t.Errorf("Validate() = %v, want no diagnostics", diags)
+	}
+}
+
+func TestValidate_Checks(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		severity Severity
+		pointer  string
+		message  string
+	}{
+		{"duplicate key", "{\"name\": \"acme/app\",\n\"name\": \"acme/app\"}", SeverityWarning, "/name", "Key name is a duplicate at line 2"},
+		{"missing name", `{}`, SeverityPublishError, "/name", "The property name is required"},
+		{"invalid name", `{"name": "acme"}`, SeverityError, "/name", "acme is invalid, it should have a vendor name"},
+		{"reserved name", `{"name": "acme/con"}`, SeverityError, "/name", "acme/con is reserved"},
+		{"json name", `{"name": "acme/app.json"}`, SeverityError, "/name", "can not end in .json"},
+		{"name casing", `{"name": "Acme/MyApp"}`, SeverityPublishError, "/name", `We suggest using "acme/my-app" instead`},
+		{"missing description", `{}`, SeverityPublishError, "/description", "The property description is required"},
+		{"missing license", `{}`, SeverityWarning, "/license", "No license specified"},
+		{"empty license list", `{"license": []}`, SeverityWarning, "/license", "No license specified"},
+		{"invalid license", `{"license": "MIT-2"}`, SeverityWarning, "/license", `License "MIT-2" is not a valid SPDX license identifier`},
+		{"untrimmed license", `{"license": ["MIT", " ISC"]}`, SeverityWarning, "/license/1", `License " ISC" must not contain extra spaces`},
+		{"deprecated gpl", `{"license": "GPL-2.0"}`, SeverityWarning, "/license", `use "GPL-2.0-only" or "GPL-2.0-or-later" instead`},
+		{"deprecated gpl plus", `{"license": "LGPL-2.1+"}`, SeverityWarning, "/license", `use "LGPL-2.1-or-later" instead`},
+		{"deprecated other", `{"license": "eCos-2.0"}`, SeverityWarning, "/license", "is a deprecated SPDX license identifier, see https://spdx.org/licenses/"},
+		{"deprecated in expression", `{"license": ["MIT OR GPL-2.0+"]}`, SeverityWarning, "/license/0", `License "GPL-2.0+" is a deprecated SPDX license identifier, use "GPL-2.0-or-later" instead`},
+		{"license type", `{"license": 1}`, SeverityError, "/license", "must be a string or an array of strings"},
+		{"version present", `{"version": "1.0.0"}`, SeverityWarning, "/version", "The version field is present"},
+		{"invalid version", `{"version": "one"}`, SeverityError, "/version", "invalid value (one)"},
+		{"composer-installer", `{"type": "composer-installer"}`, SeverityWarning, "/type", "'composer-installer' is deprecated"},
+		{"minimum-stability", `{"minimum-stability": "unstable"}`, SeverityError, "/minimum-stability", "invalid value (unstable), must be one of"},
+		{"link section type", `{"require": "psr/log"}`, SeverityError, "/require", "must be an object"},
+		{"self link", `{"name": "acme/app", "require": {"ACME/app": "^1.0"}}`, SeverityError, "/require/ACME~1app", "a package cannot set a require on itself"},
+		{"link casing", `{"require": {"Psr/Log": "^3.0"}}`, SeverityWarning, "/require/Psr~1Log", "Please use psr/log instead"},
+		{"link characters", `{"require": {"psr/log ": "^3.0"}}`, SeverityWarning, "/require/psr~1log ", "psr/log  is invalid"},
+		{"link value type", `{"require": {"psr/log": 3}}`, SeverityError, "/require/psr~1log", "must be a string containing a version constraint"},
+		{"invalid constraint", `{"require": {"psr/log": "^^3"}}`, SeverityError, "/require/psr~1log", "invalid version constraint"},
+		{"unbound star", `{"require": {"psr/log": "*"}}`, SeverityWarning, "/require/psr~1log", "unbound version constraints (*) should be avoided"},
+		{"unbound lower bound", `{"require": {"psr/log": ">=1.0"}}`, SeverityWarning, "/require/psr~1log", "unbound version constraints (>=1.0)"},
+		{"exact", `{"require": {"psr/log": "3.0.0"}}`, SeverityWarning, "/require/psr~1log", "exact version constraints (3.0.0) should be avoided"},
+		{"match none", `{"require-dev": {"psr/log": ">=2.0 <1.0"}}`, SeverityWarning, "/require-dev/psr~1log", "cannot possibly match anything"},
+		{"conflict replaced", `{"replace": {"psr/log": "*"}, "conflict": {"psr/log": "<1.0"}}`, SeverityError, "/conflict/psr~1log", "also replaced"},
+		{"require overrides", `{"require": {"psr/log": "^3.0"}, "require-dev": {"psr/log": "^3.0"}}`, SeverityWarning, "/require-dev/psr~1log", "psr/log is required both in require and require-dev"},
+		{"satisfied by provide", `{"require": {"psr/log-implementation": "^1.0"}, "provide": {"psr/log-implementation": "1.0"}}`, SeverityWarning, "/provide/psr~1log-implementation", "in require is also listed in provide"},
+		{"commit ref", `{"require-dev": {"acme/tool": "dev-main#abc123"}}`, SeverityWarning, "/require-dev/acme~1tool", "pointing to a commit-ref"},
+		{"script description", `{"scripts-descriptions": {"lint": "Lint"}}`, SeverityWarning, "/scripts-descriptions/lint", `Description for non-existent script "lint"`},
+		{"script alias", `{"scripts": {}, "scripts-aliases": {"lint": ["l"]}}`, SeverityWarning, "/scripts-aliases/lint", `Aliases for non-existent script "lint"`},
+		{"autoload type", `{"autoload": {"psr4": {}}}`, SeverityError, "/autoload/psr4", "invalid value (psr4), must be one of psr-0, psr-4"},
+		{"psr-0", `{"autoload-dev": {"psr-0": {"Acme_": "src/"}}}`, SeverityWarning, "/autoload-dev/psr-0", "PSR-0 autoloading is deprecated"},
+		{"empty prefix", `{"autoload": {"psr-4": {"": "src/"}}}`, SeverityWarning, "/autoload/psr-4/", "empty namespace prefix"},
+		{"psr-4 separator", `{"autoload": {"psr-4": {"Acme\\App": "src/"}}}`, SeverityError, "/autoload/psr-4/Acme\\App", `should be Acme\App\\`},
+		{"time", `{"time": "yesterday"}`, SeverityError, "/time", "invalid value (yesterday)"},
+		{"homepage", `{"homepage": "example.com"}`, SeverityWarning, "/homepage", "must be an http/https URL"},
+		{"keywords", `{"keywords": ["ok", "no!"]}`, SeverityWarning, "/keywords/1", "invalid value (no!)"},
+		{"author email", `{"authors": [{"name": "A", "email": "a@"}]}`, SeverityWarning, "/authors/0/email", "must be a valid email address"},
+		{"support url", `{"support": {"issues": "ftp://example.com"}}`, SeverityWarning, "/support/issues", "must be an http/https URL"},
+		{"support irc", `{"support": {"irc": "#acme"}}`, SeverityWarning, "/support/irc", "irc://"},
+		{"branch alias suffix", `{"extra": {"branch-alias": {"dev-main": "1.x"}}}`, SeverityWarning, "/extra/branch-alias/dev-main", "must end in -dev"},
+		{"branch alias number", `{"extra": {"branch-alias": {"dev-main": "main-dev"}}}`, SeverityWarning, "/extra/branch-alias/dev-main", "must be a parseable number"},
+		{"branch alias prefix", `{"extra": {"branch-alias": {"2.1.x-dev": "3.0.x-dev"}}}`, SeverityWarning, "/extra/branch-alias/2.1.x-dev", "not a valid numeric alias"},
+		{"branch alias type", `{"extra": {"branch-alias": ["1.x-dev"]}}`, SeverityError, "/extra/branch-alias", "must be an array of versions => aliases"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags, err := Validate([]byte(tt.doc), Options{})
+			if err != nil {
+				t.Fatalf("Validate() returned unexpected error: %v", err)
+			}
+			for _, d := range diags {
+				if d.Severity == tt.severity && d.Pointer == tt.pointer && strings.Contains(d.Message, tt.message) {
+					return
+				}
+			}
+			t.Errorf("Validate() = %v, want %s at %s containing %q", diags, tt.severity, tt.pointer, tt.message)
+		})
+	}
+}
+
+func TestValidate_Options(t *testing.T) {
+	doc := []byte(`{"name": "Acme/App", "version": "1.0.0", "license": "MIT", "require": {"psr/log": "*", "php": ">=8.1"}}`)
+
+	diags, err := Validate(doc, Options{})
+	if err != nil {
+		t.Fatalf("Validate() returned unexpected error: %v", err)
+	}
+	if got := len(diags.Filter(SeverityPublishError)); got != 2 {
+		t.Errorf("publish errors = %d, want 2 (name casing and description): %v", got, diags)
+	}
+	if got := len(diags.Filter(SeverityWarning)); got != 2 {
+		t.Errorf("warnings = %d, want 2 (version and unbound psr/log; php is a platform package): %v", got, diags)
+	}
+	if diags.HasErrors() {
+		t.Errorf("HasErrors() = true, want false: %v", diags)
+	}
+
+	diags, _ = Validate(doc, Options{NoCheckAll: true, NoCheckPublish: true, NoCheckVersion: true})
+	if len(diags) != 0 {
+		t.Errorf("Validate() with all checks disabled = %v, want no diagnostics", diags)
+	}
+}
+
+func TestValidate_Order(t *testing.T) {
+	diags, err := Validate([]byte(`{"version": "1.0.0", "require": {"psr/log": "^^3"}}`), Options{})
+	if err != nil {
+		t.Fatalf("Validate() returned unexpected error: %v", err)
+	}
+	for i := 1; i < len(diags); i++ {
+		if diags[i-1].Severity.rank() > diags[i].Severity.rank() {
+			t.Fatalf("Validate() is not ordered by severity: %v", diags)
+		}
+	}
+	if !diags.HasErrors() || diags[0].Pointer != "/require/psr~1log" {
+		t.Errorf("Validate() first diagnostic = %v, want the invalid constraint", diags[0])
+	}
+}
+
+func TestValidate_InvalidDocument(t *testing.T) {
+	if _, err := Validate([]byte(`{`), Options{}); err == nil {
+		t.Error("Validate() with invalid JSON expected error, got nil")
+	}
+	if _, err := Validate([]byte(`[]`), Options{}); err == nil {
+		t.Error("Validate() with a non-object document expected error, got nil")
+	}
+}
+
+func TestDiagnostic_String(t *testing.T) {
+	d := Diagnostic{Severity: SeverityWarning, Pointer: Pointer("require", "acme/lib~1"), Message: "unbound"}
+	if got, want := d.String(), "warning /require/acme~1lib~01: unbound"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	d.Pointer = ""
+	if got, want := d.String(), "warning: unbound"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -3,8 +3,8 @@ package validation
 
 import (
 	"fmt"
-	"regexp"
 
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/constraint"
 	"github.com/scagogogo/php-composer-json-parser/pkg/composer/dependency"
 )
 
@@ -40,34 +40,16 @@ func ValidateComposerJSON(name, description, stability string) error {
 	return nil
 }
 
-// ValidateVersion validates a version string
+// ValidateVersion validates a version constraint using the full Composer grammar,
+// e.g. "^7.4 || ^8.0", "1.0.*", ">=1.2 <2.0,!=1.5", "1.0 - 2.0" or "dev-main#abc123"
 func ValidateVersion(version string) error {
 	if version == "" {
 		return nil // Empty version is valid (omitted)
 	}
 
-	// Allow "*" as a wildcard
-	if version == "*" {
-		return nil
+	if _, err := constraint.Parse(version); err != nil {
+		return fmt.Errorf("invalid version format: %w", err)
 	}
 
-	// Allow "dev-master", "dev-*", or specific branch
-	if regexp.MustCompile(`^dev-`).MatchString(version) {
-		return nil
-	}
-
-	// Check for valid semantic version with constraints
-	// Example patterns: "1.0.0", "^1.0", "~1.2.3", ">=1.0", ">1.0 <2.0", etc.
-	versionRegex := regexp.MustCompile(`^(\^|~|>=|<=|>|<|!=|==)?[0-9]+(\.[0-9]+)?(\.[0-9]+)?(\-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
-	if versionRegex.MatchString(version) {
-		return nil
-	}
-
-	// Check for version ranges
-	rangeRegex := regexp.MustCompile(`^([><=!]?[0-9]+(\.[0-9]+)?(\.[0-9]+)?)(\s+[><=!]?[0-9]+(\.[0-9]+)?(\.[0-9]+)?)$`)
-	if rangeRegex.MatchString(version) {
-		return nil
-	}
-
-	return fmt.Errorf("invalid version format: %s", version)
+	return nil
 }
@@ -191,6 +191,47 @@ func TestValidateVersion(t *testing.T) {
 			version: ">1.0.0 <2.0.0",
 			wantErr: false,
 		},
+		{
+			name:    "Or constraint",
+			version: "^7.4 || ^8.0",
+			wantErr: false,
+		},
+		{
+			name:    "Wildcard",
+			version: "1.0.*",
+			wantErr: false,
+		},
+		{
+			name:    "Range with comma and exclusion",
+			version: ">=1.2 <2.0,!=1.5",
+			wantErr: false,
+		},
+		{
+			name:    "Hyphen range",
+			version: "1.0 - 2.0",
+			wantErr: false,
+		},
+		{
+			name:    "Branch with commit reference",
+			version: "dev-main#abc123",
+			wantErr: false,
+		},
+		{
+			name:    "Numeric dev branch",
+			version: "2.x-dev",
+			wantErr: false,
+		},
+		{
+			name:    "Alias",
+			version: "1.0.x-dev as 1.0.0",
+			wantErr: false,
+		},
+		{
+			name:          "Unsupported tilde operator",
+			version:       "~>1.0",
+			wantErr:       true,
+			errorContains: "invalid version format",
+		},
 		{
 			name:          "Invalid version format",
 			version:       "invalid",
@@ -0,0 +1,68 @@
+package composer
+
+import (
+	"github.com/scagogogo/php-composer-json-parser/pkg/composer/version"
+)
+
+// NormalizeVersion 将版本字符串规范化为Composer的内部形式
+//
+// 参数:
+//   - v: 版本字符串，如"v1.0"、"1.0.0-b2"、"2.x-dev"、"dev-main"
+//
+// 返回:
+//   - string: 规范化后的版本，如"1.0.0.0"、"1.0.0.0-beta2"
+//   - error: 如果版本字符串无效，返回错误
+//
+// 示例:
+//
+//	v, err := composer.NormalizeVersion("v1.0")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(v) // 输出: 1.0.0.0
+func NormalizeVersion(v string) (string, error) {
+	return version.Normalize(v)
+}
+
+// CompareVersions 按Composer的规则比较两个版本
+//
+// 参数:
+//   - a, b: 要比较的版本，可以是未规范化的形式，如Git标签"v1.2.0"
+//
+// 返回:
+//   - int: a < b时为-1，a == b时为0，a > b时为1
+//   - error: 如果任一版本无效，返回错误
+//
+// 规则:
+//   - 预发布版本的顺序为dev < alpha < beta < RC < 正式版 < patch（如"1.0.0-p1"）
+//   - 默认分支（dev-master、dev-default、dev-trunk）视为"9999999-dev"，排在所有发布版本之后
+//   - 其他分支（dev-*）排在所有发布版本之前
+//
+// 示例:
+//
+//	c, _ := composer.CompareVersions("1.0.0-beta2", "1.0.0-beta10")
+//	fmt.Println(c) // 输出: -1
+func CompareVersions(a, b string) (int, error) {
+	return version.Order(a, b)
+}
+
+// SortVersions 按CompareVersions的规则对版本进行升序排序
+//
+// 参数:
+//   - versions: 要排序的版本列表，不会被修改
+//
+// 返回:
+//   - []string: 排好序的新列表，元素保持原来的写法
+//   - error: 如果有无效的版本，返回错误
+//
+// 示例:
+//
+//	tags := []string{"v1.10.0", "v1.2.0", "v1.2.0-RC1", "dev-master"}
+//	sorted, err := composer.SortVersions(tags)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(sorted) // 输出: [v1.2.0-RC1 v1.2.0 v1.10.0 dev-master]
+func SortVersions(versions []string) ([]string, error) {
+	return version.Sort(versions)
+}
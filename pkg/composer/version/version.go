@@ -0,0 +1,421 @@
+// Package version 提供与Composer的VersionParser一致的版本号规范化功能
+//
+// 本包处理Composer版本字符串的各种形式，包括：
+// - 经典版本号，如"1.0"、"v2.3.4"、"1.0.0-beta2"、"1.0.0-p1"
+// - 日期版本号，如"2024.01.15"
+// - 分支版本，如"dev-main"、"2.x-dev"
+// - 稳定性的解析，如"1.0.0-RC1"的稳定性为"RC"
+// - 按Composer的规则比较和排序版本
+package version
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 稳定性级别，按从不稳定到稳定的顺序排列
+const (
+	StabilityDev    = "dev"
+	StabilityAlpha  = "alpha"
+	StabilityBeta   = "beta"
+	StabilityRC     = "RC"
+	StabilityStable = "stable"
+)
+
+// DefaultBranchAlias 是Composer为默认分支（如dev-main）设置的别名版本
+const DefaultBranchAlias = "9999999-dev"
+
+// ErrInvalidVersion 表示版本字符串无效
+var ErrInvalidVersion = errors.New("invalid version string")
+
+// ModifierPattern 匹配版本号后的稳定性修饰符的正则表达式，分组依次为：稳定性、稳定性序号、dev后缀
+const ModifierPattern = `[._-]?(?:(stable|beta|b|RC|alpha|a|patch|pl|p)((?:[.-]?\d+)*)?)?([.-]?dev)?`
+
+var (
+	aliasRegex     = regexp.MustCompile(`^([^,\s]+) +as +([^,\s]+)$`)
+	flagRegex      = regexp.MustCompile(`(?i)@(?:stable|RC|beta|alpha|dev)$`)
+	buildRegex     = regexp.MustCompile(`^([^,\s+]+)\+[^\s]+$`)
+	classicalRegex = regexp.MustCompile(`(?i)^v?(\d{1,5})(\.\d+)?(\.\d+)?(\.\d+)?` + ModifierPattern + `$`)
+	dateRegex      = regexp.MustCompile(`(?i)^v?(\d{4}(?:[.:-]?\d{2}){1,6}(?:[.:-]?\d{1,3}){0,2})` + ModifierPattern + `$`)
+	devSuffixRegex = regexp.MustCompile(`(?i)^(.*?)[.-]?dev$`)
+	branchRegex    = regexp.MustCompile(`(?i)^v?(\d+)(\.(?:\d+|[xX*]))?(\.(?:\d+|[xX*]))?(\.(?:\d+|[xX*]))?$`)
+	stabilityRegex = regexp.MustCompile(`(?i)` + ModifierPattern + `(?:\+.*)?$`)
+	nonDigitRegex  = regexp.MustCompile(`\D`)
+)
+
+// Normalize 将版本字符串规范化为Composer的内部形式
+//
+// 参数:
+//   - v: 版本字符串，可以带"v"前缀、稳定性后缀、构建元数据、"as"别名或"@"稳定性标志
+//
+// 返回:
+//   - string: 规范化后的版本，如"1.0.0.0"、"1.0.0.0-beta2"、"dev-main"
+//   - error: 如果版本字符串无效，返回包装了ErrInvalidVersion的错误
+//
+// 示例:
+//
+//	v, _ := version.Normalize("v1.0")         // "1.0.0.0"
+//	v, _ = version.Normalize("1.0.0-b2")      // "1.0.0.0-beta2"
+//	v, _ = version.Normalize("2.x-dev")       // "2.9999999.9999999.9999999-dev"
+//	v, _ = version.Normalize("dev-feature/x") // "dev-feature/x"
+func Normalize(v string) (string, error) {
+	v = strings.TrimSpace(v)
+	orig := v
+
+	// 去掉别名
+	if m := aliasRegex.FindStringSubmatch(v); m != nil {
+		v = m[1]
+	}
+
+	// 去掉稳定性标志
+	if loc := flagRegex.FindStringIndex(v); loc != nil {
+		v = v[:loc[0]]
+	}
+
+	// 为兼容Composer 1.x，master/trunk/default视为分支名
+	switch v {
+	case "master", "trunk", "default":
+		v = "dev-" + v
+	}
+
+	if len(v) >= 4 && strings.EqualFold(v[:4], "dev-") {
+		return "dev-" + v[4:], nil
+	}
+
+	// 去掉构建元数据
+	if m := buildRegex.FindStringSubmatch(v); m != nil {
+		v = m[1]
+	}
+
+	var normalized string
+	var mods []string
+	if m := classicalRegex.FindStringSubmatch(v); m != nil {
+		normalized = m[1]
+		for _, part := range m[2:5] {
+			if part == "" {
+				part = ".0"
+			}
+			normalized += part
+		}
+		mods = m[5:]
+	} else if m := dateRegex.FindStringSubmatch(v); m != nil {
+		normalized = nonDigitRegex.ReplaceAllString(m[1], ".")
+		mods = m[2:]
+	}
+
+	if mods != nil {
+		if mods[0] != "" {
+			if strings.EqualFold(mods[0], StabilityStable) {
+				return normalized, nil
+			}
+			normalized += "-" + expandStability(mods[0]) + strings.TrimLeft(mods[1], ".-")
+		}
+		if mods[2] != "" {
+			normalized += "-dev"
+		}
+		return normalized, nil
+	}
+
+	// 以-dev结尾的数字分支，如"2.x-dev"、"1.0.x-dev"
+	if m := devSuffixRegex.FindStringSubmatch(v); m != nil {
+		if branch := NormalizeBranch(m[1]); !strings.HasPrefix(branch, "dev-") {
+			return branch, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrInvalidVersion, orig)
+}
+
+// NormalizeBranch 将分支名规范化为Composer的内部形式
+//
+// 数字分支（如"2.x"、"1.0"）转换为以9999999填充的开发版本，其他分支加上"dev-"前缀。
+//
+// 示例:
+//
+//	version.NormalizeBranch("2.x")  // "2.9999999.9999999.9999999-dev"
+//	version.NormalizeBranch("main") // "dev-main"
+func NormalizeBranch(name string) string {
+	name = strings.TrimSpace(name)
+	m := branchRegex.FindStringSubmatch(name)
+	if m == nil {
+		return "dev-" + name
+	}
+
+	normalized := m[1]
+	for _, part := range m[2:] {
+		if part == "" {
+			part = ".x"
+		}
+		normalized += part
+	}
+	normalized = strings.NewReplacer("x", "9999999", "X", "9999999", "*", "9999999").Replace(normalized)
+	return normalized + "-dev"
+}
+
+// ParseStability 返回版本字符串的稳定性
+//
+// 返回值为StabilityDev、StabilityAlpha、StabilityBeta、StabilityRC或StabilityStable之一。
+//
+// 示例:
+//
+//	version.ParseStability("1.0.0-RC1") // "RC"
+//	version.ParseStability("dev-main")  // "dev"
+//	version.ParseStability("1.0.0-p1")  // "stable"
+func ParseStability(v string) string {
+	if i := strings.IndexByte(v, '#'); i >= 0 {
+		v = v[:i]
+	}
+	if strings.HasPrefix(v, "dev-") || strings.HasSuffix(v, "-dev") {
+		return StabilityDev
+	}
+
+	m := stabilityRegex.FindStringSubmatch(strings.ToLower(v))
+	if m == nil {
+		return StabilityStable
+	}
+	if m[3] != "" {
+		return StabilityDev
+	}
+	switch m[1] {
+	case "beta", "b":
+		return StabilityBeta
+	case "alpha", "a":
+		return StabilityAlpha
+	case "rc":
+		return StabilityRC
+	}
+	return StabilityStable
+}
+
+// expandStability 将稳定性缩写展开为完整形式
+func expandStability(s string) string {
+	s = strings.ToLower(s)
+	switch s {
+	case "a":
+		return StabilityAlpha
+	case "b":
+		return StabilityBeta
+	case "p", "pl":
+		return "patch"
+	case "rc":
+		return StabilityRC
+	}
+	return s
+}
+
+// IsBranch 判断规范化后的版本是否为分支（以"dev-"开头）
+func IsBranch(normalized string) bool {
+	return strings.HasPrefix(normalized, "dev-")
+}
+
+// Compare 按PHP的version_compare规则比较两个规范化后的版本
+//
+// 返回:
+//   - int: a < b时为-1，a == b时为0，a > b时为1
+//
+// 注意:
+//   - 分支版本（dev-*）不参与数字比较，请先使用IsBranch判断
+func Compare(a, b string) int {
+	pa, pb := canonicalize(a), canonicalize(b)
+
+	i := 0
+	for ; i < len(pa) && i < len(pb); i++ {
+		if c := comparePart(pa[i], pb[i]); c != 0 {
+			return c
+		}
+	}
+
+	// 较长的一方剩余的部分与"#"（数字）比较
+	switch {
+	case i < len(pa):
+		if isDigit(pa[i][0]) {
+			return 1
+		}
+		return Compare(strings.Join(pa[i:], "."), "#")
+	case i < len(pb):
+		if isDigit(pb[i][0]) {
+			return -1
+		}
+		return Compare("#", strings.Join(pb[i:], "."))
+	}
+	return 0
+}
+
+// NormalizeDefaultBranch 将默认分支（dev-master、dev-default、dev-trunk）转换为DefaultBranchAlias
+//
+// 与Composer的VersionParser::normalizeDefaultBranch一致，其他版本原样返回。
+func NormalizeDefaultBranch(normalized string) string {
+	switch normalized {
+	case "dev-master", "dev-default", "dev-trunk":
+		return DefaultBranchAlias
+	}
+	return normalized
+}
+
+// Order 按Composer的排序规则比较两个未规范化的版本
+//
+// 规则:
+//   - 版本先规范化，默认分支视为DefaultBranchAlias（9999999-dev），排在所有发布版本之后
+//   - 其他分支（dev-*）排在所有数字版本之前，分支之间按名称排序
+//   - 数字版本按PHP的version_compare比较，如1.0.0-dev < 1.0.0-alpha1 < 1.0.0-beta2 < 1.0.0-beta10 < 1.0.0-RC1 < 1.0.0 < 1.0.0-p1
+//
+// 返回:
+//   - int: a < b时为-1，a == b时为0，a > b时为1
+//   - error: 如果任一版本无效，返回包装了ErrInvalidVersion的错误
+//
+// 示例:
+//
+//	c, _ := version.Order("v1.0", "1.0.0")          // 0
+//	c, _ = version.Order("1.0.0-beta2", "1.0.0-RC1") // -1
+//	c, _ = version.Order("dev-feature", "0.1.0")     // -1
+func Order(a, b string) (int, error) {
+	na, err := Normalize(a)
+	if err != nil {
+		return 0, err
+	}
+	nb, err := Normalize(b)
+	if err != nil {
+		return 0, err
+	}
+	return compareNormalized(NormalizeDefaultBranch(na), NormalizeDefaultBranch(nb)), nil
+}
+
+// Sort 按Order的规则对版本进行升序排序
+//
+// 参数:
+//   - versions: 未规范化的版本列表，如Git标签
+//
+// 返回:
+//   - []string: 排好序的新列表，元素保持原来的写法，相等的版本保持原来的相对顺序
+//   - error: 如果有无效的版本，返回包装了ErrInvalidVersion的错误
+//
+// 示例:
+//
+//	sorted, _ := version.Sort([]string{"1.10.0", "v1.2.0", "dev-master", "1.2.0-RC1", "dev-feature"})
+//	// [dev-feature 1.2.0-RC1 v1.2.0 1.10.0 dev-master]
+func Sort(versions []string) ([]string, error) {
+	normalized := make([]string, len(versions))
+	for i, v := range versions {
+		n, err := Normalize(v)
+		if err != nil {
+			return nil, err
+		}
+		normalized[i] = NormalizeDefaultBranch(n)
+	}
+
+	order := make([]int, len(versions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return compareNormalized(normalized[order[i]], normalized[order[j]]) < 0
+	})
+
+	sorted := make([]string, len(versions))
+	for i, idx := range order {
+		sorted[i] = versions[idx]
+	}
+	return sorted, nil
+}
+
+// compareNormalized 比较两个规范化后的版本，分支排在数字版本之前
+func compareNormalized(a, b string) int {
+	aBranch, bBranch := IsBranch(a), IsBranch(b)
+	switch {
+	case aBranch && bBranch:
+		return strings.Compare(a, b)
+	case aBranch:
+		return -1
+	case bBranch:
+		return 1
+	}
+	return Compare(a, b)
+}
+
+// canonicalize 按PHP的version_compare规则将版本拆分为数字和字符串部分
+func canonicalize(v string) []string {
+	var parts []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			parts = append(parts, cur.String())
+			cur.Reset()
+		}
+	}
+
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if c != '#' && !isDigit(c) && (c|0x20 < 'a' || c|0x20 > 'z') {
+			// "-"、"_"、"+"、"."及其他符号都是分隔符
+			flush()
+			continue
+		}
+		// 数字与非数字之间也是分隔
+		if cur.Len() > 0 && isDigit(c) != isDigit(v[i-1]) {
+			flush()
+		}
+		cur.WriteByte(c)
+	}
+	flush()
+	return parts
+}
+
+// comparePart 比较version_compare的两个部分
+func comparePart(a, b string) int {
+	aDigit, bDigit := isDigit(a[0]), isDigit(b[0])
+	switch {
+	case aDigit && bDigit:
+		return compareNumbers(a, b)
+	case aDigit:
+		return sign(specialOrder("#") - specialOrder(b))
+	case bDigit:
+		return sign(specialOrder(a) - specialOrder("#"))
+	default:
+		return sign(specialOrder(a) - specialOrder(b))
+	}
+}
+
+// compareNumbers 比较两个十进制数字串，不受整数溢出影响
+func compareNumbers(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return sign(len(a) - len(b))
+	}
+	return strings.Compare(a, b)
+}
+
+// specialForms 是version_compare中特殊字符串的顺序，按前缀匹配
+var specialForms = []struct {
+	name  string
+	order int
+}{
+	{"dev", 0}, {"alpha", 1}, {"a", 1}, {"beta", 2}, {"b", 2},
+	{"RC", 3}, {"rc", 3}, {"#", 4}, {"pl", 5}, {"p", 5},
+}
+
+// specialOrder 返回字符串部分的顺序，未知字符串排在最前
+func specialOrder(s string) int {
+	for _, f := range specialForms {
+		if strings.HasPrefix(s, f.name) {
+			return f.order
+		}
+	}
+	return -6
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,155 @@
+package version
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"1.0", "1.0.0.0"},
+		{"v2.3.4", "2.3.4.0"},
+		{"1.2.3.4", "1.2.3.4"},
+		{"1.0.0-b2", "1.0.0.0-beta2"},
+		{"1.0.0-beta.2", "1.0.0.0-beta2"},
+		{"1.0-RC1", "1.0.0.0-RC1"},
+		{"1.0.0-alpha", "1.0.0.0-alpha"},
+		{"1.0.0-p1", "1.0.0.0-patch1"},
+		{"1.0.0-stable", "1.0.0.0"},
+		{"1.0.0-dev", "1.0.0.0-dev"},
+		{"1.2.3+build.1", "1.2.3.0"},
+		{"2024.01.15", "2024.01.15.0"},
+		{"2.x-dev", "2.9999999.9999999.9999999-dev"},
+		{"1.0.x-dev", "1.0.9999999.9999999-dev"},
+		{"dev-main", "dev-main"},
+		{"DEV-Feature/x", "dev-Feature/x"},
+		{"master", "dev-master"},
+		{"1.0.x-dev as 1.0.0", "1.0.9999999.9999999-dev"},
+		{"1.0@beta", "1.0.0.0"},
+		{" 1.0 ", "1.0.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := Normalize(tt.version)
+			if err != nil {
+				t.Fatalf("Normalize() returned unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"", "foo", "1.0.0$", "1.0.0.0.0", "feature-dev"} {
+		if _, err := Normalize(invalid); !errors.Is(err, ErrInvalidVersion) {
+			t.Errorf("Normalize(%q) error = %v, want ErrInvalidVersion", invalid, err)
+		}
+	}
+}
+
+func TestParseStability(t *testing.T) {
+	tests := map[string]string{
+		"1.0.0":          StabilityStable,
+		"1.0.0-p1":       StabilityStable,
+		"1.0.0-RC1":      StabilityRC,
+		"1.0.0.0-beta2":  StabilityBeta,
+		"1.0.0-a1":       StabilityAlpha,
+		"1.0.0-dev":      StabilityDev,
+		"dev-main":       StabilityDev,
+		"dev-main#abc12": StabilityDev,
+	}
+
+	for v, want := range tests {
+		if got := ParseStability(v); got != want {
+			t.Errorf("ParseStability(%q) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0.0", "1.0.0.0", 0},
+		{"1.0.0.0", "1.0.0.1", -1},
+		{"1.10.0.0", "1.9.0.0", 1},
+		{"1.0.0.0-dev", "1.0.0.0-alpha1", -1},
+		{"1.0.0.0-alpha1", "1.0.0.0-beta1", -1},
+		{"1.0.0.0-beta2", "1.0.0.0-beta10", -1},
+		{"1.0.0.0-beta3", "1.0.0.0-RC1", -1},
+		{"1.0.0.0-RC1", "1.0.0.0", -1},
+		{"1.0.0.0", "1.0.0.0-patch1", -1},
+		{"1.0.0.0-dev", "1.0.0.0", -1},
+		{"99999999999999999999.0.0.0", "1.0.0.0", 1},
+	}
+
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Compare(tt.b, tt.a); got != -tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestOrder(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.0", "1.0.0", 0},
+		{"1.0.0-beta2", "1.0.0-beta10", -1},
+		{"1.0.0-beta2", "1.0.0-RC1", -1},
+		{"1.0.0", "1.0.0-p1", -1},
+		{"1.0.0-patch1", "1.0.1", -1},
+		{"dev-feature", "0.0.1", -1},
+		{"dev-a", "dev-b", -1},
+		{"dev-master", "99.0.0", 1},
+		{"dev-master", "9999999-dev", 0},
+		{"dev-trunk", "dev-default", 0},
+		{"2.x-dev", "2.99.0", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			got, err := Order(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Order() returned unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Order() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Order("1.0", "not a version"); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("Order() error = %v, want ErrInvalidVersion", err)
+	}
+}
+
+func TestSort(t *testing.T) {
+	input := []string{"1.10.0", "v1.2.0", "dev-master", "1.2.0-RC1", "dev-feature", "1.2", "1.2.0-p1", "1.2.0-beta10", "1.2.0-beta2"}
+	want := []string{"dev-feature", "1.2.0-beta2", "1.2.0-beta10", "1.2.0-RC1", "v1.2.0", "1.2", "1.2.0-p1", "1.10.0", "dev-master"}
+
+	got, err := Sort(input)
+	if err != nil {
+		t.Fatalf("Sort() returned unexpected error: %v", err)
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Sort() = %v, want %v", got, want)
+	}
+	if input[0] != "1.10.0" {
+		t.Errorf("Sort() should not modify its input")
+	}
+
+	if _, err := Sort([]string{"1.0", "foo"}); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("Sort() error = %v, want ErrInvalidVersion", err)
+	}
+}
@@ -0,0 +1,32 @@
+package composer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVersionFunctions(t *testing.T) {
+	normalized, err := NormalizeVersion("v1.0")
+	if err != nil || normalized != "1.0.0.0" {
+		t.Errorf("NormalizeVersion() = %q, %v, want 1.0.0.0", normalized, err)
+	}
+	if _, err := NormalizeVersion("not a version"); err == nil {
+		t.Errorf("NormalizeVersion() should fail for invalid versions")
+	}
+
+	if c, err := CompareVersions("1.0.0-beta2", "1.0.0-beta10"); err != nil || c != -1 {
+		t.Errorf("CompareVersions() = %d, %v, want -1", c, err)
+	}
+	if c, err := CompareVersions("1.0.0-p1", "1.0.0"); err != nil || c != 1 {
+		t.Errorf("CompareVersions() = %d, %v, want 1", c, err)
+	}
+
+	sorted, err := SortVersions([]string{"v1.10.0", "dev-master", "v1.2.0", "dev-feature", "v1.2.0-RC1"})
+	if err != nil {
+		t.Fatalf("SortVersions() returned unexpected error: %v", err)
+	}
+	want := []string{"dev-feature", "v1.2.0-RC1", "v1.2.0", "v1.10.0", "dev-master"}
+	if !reflect.DeepEqual(sorted, want) {
+		t.Errorf("SortVersions() = %v, want %v", sorted, want)
+	}
+}